@@ -4,24 +4,49 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/shubhamku044/gopenapi/internal/generator"
+	"github.com/shubhamku044/gopenapi/internal/mock"
 	"github.com/shubhamku044/gopenapi/internal/parser"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "check-handlers" {
+		checkHandlers(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "mock" {
+		mockServer(os.Args[2:])
+		return
+	}
+
 	specFile := flag.String("spec", "", "Path to OpenAPI specification file (YAML or JSON)")
 	outputDir := flag.String("output", ".", "Output directory for generated code (defaults to current directory)")
 	packageName := flag.String("package", "", "Package name for generated code (auto-detected from go.mod if not provided)")
+	framework := flag.String("framework", generator.FrameworkGin, "Web framework for the generated server: gin, net/http, chi, echo, or the name of a --backend-file backend")
+	backendFile := flag.String("backend-file", "", "YAML file defining an additional framework backend")
+	paginationHeuristic := flag.Bool("pagination-heuristic", true, "Detect paginated operations without an x-pagination extension by their parameter names")
+	splitByTag := flag.Bool("split-by-tag", false, "Generate one handler interface and handlers file per tag instead of a single APIHandlers")
+	server := flag.String("server", "", "Entry of the spec's servers to mount the API at and call from the client, by index, URL or description (defaults to the first)")
+	serverVars := serverVariables{}
+	flag.Var(serverVars, "server-var", "Value of a variable in the server URL as name=value, overriding its default (repeatable)")
 	flag.Parse()
 
 	if *specFile == "" {
 		log.Fatal("Please provide an OpenAPI specification file with --spec")
 	}
 
+	if *backendFile != "" {
+		registerBackendFile(*backendFile)
+	}
+
 	// Check if spec file exists
 	if _, err := os.Stat(*specFile); os.IsNotExist(err) {
 		log.Fatalf("OpenAPI specification file '%s' not found", *specFile)
@@ -74,6 +99,15 @@ func main() {
 		OutputDir:   *outputDir,
 		PackageName: pkg,
 		ModuleName:  moduleName,
+		Framework:   *framework,
+		SplitByTag:  *splitByTag,
+		Server:      *server,
+	}
+	if len(serverVars) > 0 {
+		config.ServerVariables = serverVars
+	}
+	if !*paginationHeuristic {
+		config.Pagination = &generator.PaginationHeuristic{}
 	}
 
 	err = generator.GenerateCode(spec, config)
@@ -100,3 +134,162 @@ func main() {
 	}
 	fmt.Println("   go run main.go                 # Start your API server")
 }
+
+// serverVariables collects the --server-var flags
+type serverVariables map[string]string
+
+// String implements flag.Value
+func (v serverVariables) String() string {
+	pairs := make([]string, 0, len(v))
+	for name, value := range v {
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Set implements flag.Value, parsing name=value
+func (v serverVariables) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("want name=value, got %q", s)
+	}
+	v[name] = value
+	return nil
+}
+
+// checkHandlers implements the check-handlers subcommand, which compares the
+// user's handlers against the interface generated from the spec
+func checkHandlers(args []string) {
+	fs := flag.NewFlagSet("check-handlers", flag.ExitOnError)
+	specFile := fs.String("spec", "", "Path to OpenAPI specification file (YAML or JSON)")
+	outputDir := fs.String("output", ".", "Project directory containing handlers/ (defaults to current directory)")
+	fix := fs.Bool("fix", false, "Rewrite drifted handler signatures to match the spec, leaving method bodies alone")
+	backendFile := fs.String("backend-file", "", "YAML file defining the framework backend the handlers were generated for")
+	_ = fs.Parse(args)
+
+	if *specFile == "" {
+		log.Fatal("Please provide an OpenAPI specification file with --spec")
+	}
+
+	if *backendFile != "" {
+		registerBackendFile(*backendFile)
+	}
+
+	spec, err := parser.ParseSpecFile(*specFile)
+	if err != nil {
+		log.Fatalf("Failed to parse OpenAPI specification: %v", err)
+	}
+
+	report, err := generator.CheckHandlers(spec, *outputDir)
+	if err != nil {
+		log.Fatalf("Failed to check handlers: %v", err)
+	}
+
+	if len(report.Missing) > 0 {
+		fmt.Println("❌ Missing handlers (run gopenapi to generate stubs):")
+		for _, name := range report.Missing {
+			fmt.Printf("   %s\n", name)
+		}
+	}
+
+	if len(report.Obsolete) > 0 {
+		fmt.Println("🗑️  Obsolete handlers (no longer in the spec):")
+		for _, issue := range report.Obsolete {
+			fmt.Printf("   %s:%d: %s\n", issue.File, issue.Line, issue.Have)
+		}
+	}
+
+	if len(report.Drifted) > 0 {
+		fmt.Println("⚠️  Handler signatures that no longer match the spec:")
+		for _, issue := range report.Drifted {
+			fmt.Printf("   %s:%d:\n", issue.File, issue.Line)
+			fmt.Printf("   - %s\n", issue.Have)
+			fmt.Printf("   + %s\n", issue.Want)
+		}
+
+		if *fix {
+			if err := generator.FixHandlerSignatures(report); err != nil {
+				log.Fatalf("Failed to fix handler signatures: %v", err)
+			}
+			fmt.Printf("🔧 Rewrote %d handler signature(s)\n", len(report.Drifted))
+			report.Drifted = nil
+		}
+	}
+
+	if report.HasIssues() {
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Handlers in %s match the spec\n", *outputDir)
+}
+
+// mockServer implements the mock subcommand, which serves the examples of
+// every operation in the spec
+func mockServer(args []string) {
+	fs := flag.NewFlagSet("mock", flag.ExitOnError)
+	specFile := fs.String("spec", "", "Path to OpenAPI specification file (YAML or JSON)")
+	host := fs.String("host", "localhost", "Host to listen on")
+	port := fs.Int("port", 8080, "Port to listen on")
+	seed := fs.Uint64("seed", 0, "Seed of the values synthesized for responses without examples")
+	stateful := fs.Bool("stateful", false, "Keep the resources created, updated and deleted in memory")
+	fixturesFile := fs.String("fixtures", "", "Path to a YAML or JSON file of resources to seed the stateful mock with (implies --stateful)")
+	server := fs.String("server", "", "Entry of the spec's servers whose base path the mock serves the API at, by index, URL or description (defaults to the first)")
+	serverVars := serverVariables{}
+	fs.Var(serverVars, "server-var", "Value of a variable in the server URL as name=value, overriding its default (repeatable)")
+	_ = fs.Parse(args)
+
+	if *specFile == "" {
+		log.Fatal("Please provide an OpenAPI specification file with --spec")
+	}
+
+	spec, err := parser.ParseSpecFile(*specFile)
+	if err != nil {
+		log.Fatalf("Failed to parse OpenAPI specification: %v", err)
+	}
+
+	basePath, err := generator.ServerBasePath(spec, *server, serverVars)
+	if err != nil {
+		log.Fatalf("Failed to select the server: %v", err)
+	}
+
+	opts := []mock.Option{mock.WithSeed(*seed), mock.WithBasePath(basePath)}
+	if *stateful || *fixturesFile != "" {
+		opts = append(opts, mock.WithState())
+	}
+	api := mock.New(spec, opts...)
+	if *fixturesFile != "" {
+		fixtures, err := mock.LoadFixtures(*fixturesFile)
+		if err != nil {
+			log.Fatalf("Failed to load fixtures: %v", err)
+		}
+		if err := api.Seed(fixtures); err != nil {
+			log.Fatalf("Failed to seed the mock: %v", err)
+		}
+	}
+	addr := net.JoinHostPort(*host, strconv.Itoa(*port))
+	fmt.Printf("🎭 Mocking %d operation(s) of %s on http://%s\n", len(api.Routes()), *specFile, addr)
+	for _, route := range api.Routes() {
+		fmt.Printf("   %s\n", route)
+	}
+	fmt.Println("   Select responses with \"Prefer: code=404\" or \"Prefer: example=<name>\"")
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           api,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+	log.Fatal(httpServer.ListenAndServe())
+}
+
+// registerBackendFile makes the backend defined in filename available to --framework
+func registerBackendFile(filename string) {
+	backend, err := generator.LoadBackendFile(filename)
+	if err != nil {
+		log.Fatalf("Failed to load backend: %v", err)
+	}
+	if err := generator.RegisterBackend(backend); err != nil {
+		log.Fatalf("Failed to register backend: %v", err)
+	}
+}
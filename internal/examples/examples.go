@@ -0,0 +1,391 @@
+// Package examples synthesizes example values of the schemas of an OpenAPI
+// spec, for the generated README and handler stubs and for the mock server.
+// Values honour the schema's example, default and enum, its format, its
+// bounds and its required properties. The same seed always produces the same
+// values, so generated files don't change between runs.
+package examples
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+)
+
+// Generator synthesizes example values of schemas, resolving references
+// against the component schemas of a spec
+type Generator struct {
+	schemas map[string]models.Schema
+	seed    uint64
+}
+
+// New returns a generator resolving references against schemas whose values
+// are derived from seed
+func New(schemas map[string]models.Schema, seed uint64) *Generator {
+	return &Generator{schemas: schemas, seed: seed}
+}
+
+// Value returns an example value of schema. Objects are returned as
+// map[string]interface{} and arrays as []interface{}. It returns nil for
+// schemas no value can be synthesized for, such as unresolved references.
+func (g *Generator) Value(schema models.Schema) interface{} {
+	return g.Named("", schema)
+}
+
+// Named returns an example value of schema for a parameter or property
+// called name, which strings without a format are derived from, as in an
+// email address for email
+func (g *Generator) Named(name string, schema models.Schema) interface{} {
+	w := &walker{
+		schemas:    g.schemas,
+		rand:       rand.New(rand.NewPCG(g.seed, g.seed)),
+		inProgress: map[string]bool{},
+	}
+	return w.value(schema, name)
+}
+
+// MediaType returns the example of a media type: its example, its first named
+// example with a value, or a value synthesized from its schema
+func (g *Generator) MediaType(content models.MediaType) interface{} {
+	if content.Example != nil {
+		return content.Example
+	}
+	names := make([]string, 0, len(content.Examples))
+	for name := range content.Examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if example := content.Examples[name]; example.Value != nil {
+			return example.Value
+		}
+	}
+	return g.Value(content.Schema)
+}
+
+// walker synthesizes one value. inProgress holds the component schemas being
+// synthesized, so that self-referencing schemas end instead of recursing
+// forever.
+type walker struct {
+	schemas    map[string]models.Schema
+	rand       *rand.Rand
+	inProgress map[string]bool
+}
+
+// value synthesizes a value of schema for the property name, which is empty
+// outside of objects
+func (w *walker) value(schema models.Schema, name string) interface{} {
+	if schema.Ref != "" {
+		ref := schema.Ref[strings.LastIndex(schema.Ref, "/")+1:]
+		resolved, ok := w.schemas[ref]
+		if !ok || w.inProgress[ref] {
+			return nil
+		}
+		w.inProgress[ref] = true
+		defer delete(w.inProgress, ref)
+		return w.value(resolved, name)
+	}
+
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[w.rand.IntN(len(schema.Enum))]
+	case len(schema.AllOf) > 0:
+		return w.allOf(schema, name)
+	case len(schema.OneOf) > 0:
+		// The first alternative, which the others are least likely to match
+		return w.value(schema.OneOf[0], name)
+	case len(schema.AnyOf) > 0:
+		return w.value(schema.AnyOf[0], name)
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		return w.object(schema)
+	case "array":
+		return w.array(schema, name)
+	case "string":
+		return w.string(schema, name)
+	case "integer":
+		return int64(w.number(schema, true))
+	case "number":
+		return w.number(schema, false)
+	case "boolean":
+		return w.rand.IntN(2) == 0
+	}
+	return nil
+}
+
+// schemaType returns the type of schema, inferred from its keywords if it
+// declares none
+func schemaType(schema models.Schema) string {
+	switch {
+	case schema.Type != "":
+		return schema.Type
+	case len(schema.Properties) > 0:
+		return "object"
+	case schema.Items != nil:
+		return "array"
+	}
+	return ""
+}
+
+// allOf merges the objects synthesized for each schema of allOf with the
+// schema's own properties
+func (w *walker) allOf(schema models.Schema, name string) interface{} {
+	merged := map[string]interface{}{}
+	var last interface{}
+	for _, sub := range schema.AllOf {
+		value := w.value(sub, name)
+		if obj, ok := value.(map[string]interface{}); ok {
+			for key, v := range obj {
+				merged[key] = v
+			}
+			continue
+		}
+		if value != nil {
+			last = value
+		}
+	}
+	if len(schema.Properties) > 0 {
+		for key, v := range w.object(schema) {
+			merged[key] = v
+		}
+	}
+	if len(merged) == 0 && last != nil {
+		return last
+	}
+	return merged
+}
+
+// object synthesizes every property of schema, leaving out optional
+// properties that can't be synthesized, such as self-references
+func (w *walker) object(schema models.Schema) map[string]interface{} {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	obj := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		value := w.value(schema.Properties[name], name)
+		if value == nil && !slices.Contains(schema.Required, name) {
+			continue
+		}
+		obj[name] = value
+	}
+	return obj
+}
+
+// array synthesizes between minItems, at least one, and two more items,
+// within maxItems. Duplicates are dropped from arrays of unique items.
+func (w *walker) array(schema models.Schema, name string) []interface{} {
+	lo := 1
+	if schema.MinItems != nil && *schema.MinItems > lo {
+		lo = *schema.MinItems
+	}
+	hi := lo + 2
+	if schema.MaxItems != nil {
+		hi = min(hi, *schema.MaxItems)
+		lo = min(lo, hi)
+	}
+	if schema.Items == nil || hi <= 0 {
+		return []interface{}{}
+	}
+
+	n := lo + w.rand.IntN(hi-lo+1)
+	items := make([]interface{}, 0, n)
+	seen := map[string]bool{}
+	for range n {
+		item := w.value(*schema.Items, singular(name))
+		if item == nil {
+			break
+		}
+		if schema.UniqueItems {
+			key := fmt.Sprintf("%#v", item)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// singular returns the name of an item of the array property name, such as
+// tag for tags, so that items get the examples their name suggests
+func singular(name string) string {
+	if strings.HasSuffix(name, "ies") {
+		return strings.TrimSuffix(name, "ies") + "y"
+	}
+	if strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") {
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
+
+// number returns a number within the bounds of schema, a multiple of
+// multipleOf if it is set. Unbounded numbers are kept between 1 and 100.
+func (w *walker) number(schema models.Schema, integer bool) float64 {
+	lo, hi := 1.0, 100.0
+	switch {
+	case schema.Minimum != nil && schema.Maximum != nil:
+		lo, hi = *schema.Minimum, *schema.Maximum
+	case schema.Minimum != nil:
+		lo, hi = *schema.Minimum, *schema.Minimum+100
+	case schema.Maximum != nil && *schema.Maximum >= 1:
+		hi = min(hi, *schema.Maximum)
+	case schema.Maximum != nil:
+		lo, hi = *schema.Maximum-100, *schema.Maximum
+	}
+	if integer {
+		lo, hi = math.Ceil(lo), math.Floor(hi)
+	}
+	if hi < lo {
+		return lo
+	}
+
+	if step := schema.MultipleOf; step != nil && *step > 0 {
+		first, last := math.Ceil(lo / *step), math.Floor(hi / *step)
+		if last < first {
+			return first * *step
+		}
+		return (first + float64(w.rand.IntN(int(min(last-first, 1000))+1))) * *step
+	}
+	if integer {
+		return lo + float64(w.rand.IntN(int(min(hi-lo, 1e6))+1))
+	}
+	// Two decimals read better than the float's full precision
+	n := math.Round((lo+w.rand.Float64()*(hi-lo))*100) / 100
+	return math.Max(lo, math.Min(hi, n))
+}
+
+// string returns a string of the schema's format, or one its property name
+// suggests, padded or cut to its length bounds. Patterns aren't honoured.
+func (w *walker) string(schema models.Schema, name string) string {
+	str := w.formatted(schema.Format)
+	if str == "" {
+		str = w.named(name)
+	}
+	if schema.MinLength != nil {
+		for len(str) < *schema.MinLength {
+			str += "x"
+		}
+	}
+	if schema.MaxLength != nil && len(str) > *schema.MaxLength {
+		str = str[:*schema.MaxLength]
+	}
+	return str
+}
+
+// epoch is the earliest date synthesized
+var epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// formatted returns a string of format, or "" for formats it doesn't know
+func (w *walker) formatted(format string) string {
+	switch format {
+	case "uuid":
+		var b [16]byte
+		for i := range b {
+			b[i] = byte(w.rand.UintN(256))
+		}
+		b[6] = b[6]&0x0f | 0x40 // version 4
+		b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	case "email":
+		first, last := w.pick(firstNames), w.pick(lastNames)
+		return strings.ToLower(first + "." + last + "@example.com")
+	case "date-time":
+		return w.time().Format(time.RFC3339)
+	case "date":
+		return w.time().Format(time.DateOnly)
+	case "time":
+		return w.time().Format(time.TimeOnly)
+	case "uri", "url":
+		return "https://example.com/" + w.pick(words)
+	case "hostname":
+		return w.pick(words) + ".example.com"
+	case "ipv4":
+		// From the range reserved for documentation
+		return fmt.Sprintf("192.0.2.%d", 1+w.rand.IntN(254))
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x", 1+w.rand.IntN(0xfffe))
+	case "byte":
+		return base64.StdEncoding.EncodeToString([]byte(w.pick(words)))
+	case "password":
+		return "correct-horse-" + w.pick(words)
+	}
+	return ""
+}
+
+// time returns a time in the year from epoch, to the second
+func (w *walker) time() time.Time {
+	return epoch.Add(time.Duration(w.rand.IntN(365*24*60*60)) * time.Second)
+}
+
+// named returns a string suggested by the name of the property it's the
+// value of, or a word if the name suggests nothing
+func (w *walker) named(name string) string {
+	key := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	switch {
+	case key == "firstname" || key == "givenname":
+		return w.pick(firstNames)
+	case key == "lastname" || key == "familyname" || key == "surname":
+		return w.pick(lastNames)
+	case key == "username" || key == "login" || key == "handle":
+		return strings.ToLower(w.pick(firstNames)) + fmt.Sprint(w.rand.IntN(100))
+	case key == "name" || key == "fullname" || key == "displayname" || key == "author" || key == "owner":
+		return w.pick(firstNames) + " " + w.pick(lastNames)
+	case strings.Contains(key, "email"):
+		return w.formatted("email")
+	case strings.Contains(key, "phone"):
+		return fmt.Sprintf("+1-555-01%02d", w.rand.IntN(100))
+	case key == "city":
+		return w.pick(cities)
+	case key == "country":
+		return w.pick(countries)
+	case strings.HasSuffix(key, "url") || strings.HasSuffix(key, "uri") || key == "website" || key == "link":
+		return w.formatted("uri")
+	case key == "id" || strings.HasSuffix(name, "Id") || strings.HasSuffix(name, "_id"):
+		return fmt.Sprint(1 + w.rand.IntN(1000))
+	case key == "description" || key == "summary" || key == "bio" || key == "comment" || key == "message" || key == "body":
+		return w.sentence(8)
+	case key == "title" || key == "subject" || key == "headline":
+		return w.sentence(3)
+	}
+	return w.pick(words)
+}
+
+// sentence returns n words as a sentence
+func (w *walker) sentence(n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = w.pick(words)
+	}
+	s := strings.Join(parts, " ")
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// pick returns one of choices
+func (w *walker) pick(choices []string) string {
+	return choices[w.rand.IntN(len(choices))]
+}
+
+var (
+	firstNames = []string{"Ada", "Alan", "Barbara", "Dennis", "Edsger", "Frances", "Grace", "Ken", "Linus", "Margaret", "Radia", "Rob"}
+	lastNames  = []string{"Allen", "Dijkstra", "Hamilton", "Hopper", "Kernighan", "Liskov", "Lovelace", "Perlman", "Pike", "Ritchie", "Thompson", "Turing"}
+	cities     = []string{"Amsterdam", "Bengaluru", "Berlin", "Lagos", "Lisbon", "Montreal", "Osaka", "Seoul", "Sydney", "Valparaíso"}
+	countries  = []string{"Brazil", "Canada", "France", "Ghana", "India", "Japan", "Kenya", "Mexico", "Norway", "Portugal"}
+	words      = []string{"alpha", "amber", "anchor", "beacon", "cobalt", "delta", "ember", "falcon", "harbor", "juniper", "lumen", "meadow", "nimbus", "orbit", "pebble", "quartz", "river", "summit", "tundra", "willow"}
+)
@@ -0,0 +1,171 @@
+package examples
+
+import (
+	"math"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+)
+
+func float(v float64) *float64 { return &v }
+func integer(v int) *int       { return &v }
+
+var testSchemas = map[string]models.Schema{
+	"User": {
+		Type:     "object",
+		Required: []string{"id", "name", "role"},
+		Properties: map[string]models.Schema{
+			"id":        {Type: "string", Format: "uuid"},
+			"name":      {Type: "string"},
+			"email":     {Type: "string", Format: "email"},
+			"role":      {Type: "string", Enum: []interface{}{"admin", "member"}},
+			"age":       {Type: "integer", Minimum: float(18), Maximum: float(65)},
+			"score":     {Type: "number", Example: 9.5},
+			"createdAt": {Type: "string", Format: "date-time"},
+			"manager":   {Ref: "#/components/schemas/User"},
+			"tags":      {Type: "array", MinItems: integer(2), MaxItems: integer(2), Items: &models.Schema{Type: "string"}},
+		},
+	},
+}
+
+func TestValue(t *testing.T) {
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	for seed := uint64(0); seed < 20; seed++ {
+		value := New(testSchemas, seed).Value(models.Schema{Ref: "#/components/schemas/User"})
+		user, ok := value.(map[string]interface{})
+		if !ok {
+			t.Fatalf("Expected an object, got %#v", value)
+		}
+
+		if id, _ := user["id"].(string); !uuidPattern.MatchString(id) {
+			t.Errorf("Expected a UUID id, got %q", id)
+		}
+		if name, _ := user["name"].(string); name == "" {
+			t.Errorf("Expected a name, got %#v", user["name"])
+		}
+		if _, err := mail.ParseAddress(user["email"].(string)); err != nil {
+			t.Errorf("Expected an email address, got %q", user["email"])
+		}
+		if role := user["role"]; role != "admin" && role != "member" {
+			t.Errorf("Expected a role of the enum, got %#v", role)
+		}
+		if age, _ := user["age"].(int64); age < 18 || age > 65 {
+			t.Errorf("Expected an age between 18 and 65, got %#v", user["age"])
+		}
+		if user["score"] != 9.5 {
+			t.Errorf("Expected the example score, got %#v", user["score"])
+		}
+		if _, err := time.Parse(time.RFC3339, user["createdAt"].(string)); err != nil {
+			t.Errorf("Expected a date-time, got %q", user["createdAt"])
+		}
+		if _, ok := user["manager"]; ok {
+			t.Errorf("Expected the self-referencing manager to be left out, got %#v", user["manager"])
+		}
+		if tags, _ := user["tags"].([]interface{}); len(tags) != 2 {
+			t.Errorf("Expected 2 tags, got %#v", user["tags"])
+		}
+	}
+}
+
+func TestValueDeterministic(t *testing.T) {
+	user := models.Schema{Ref: "#/components/schemas/User"}
+
+	first := New(testSchemas, 42).Value(user)
+	if second := New(testSchemas, 42).Value(user); !reflect.DeepEqual(first, second) {
+		t.Errorf("Expected the same value for the same seed, got %v and %v", first, second)
+	}
+	if other := New(testSchemas, 43).Value(user); reflect.DeepEqual(first, other) {
+		t.Errorf("Expected another value for another seed, got %v twice", first)
+	}
+}
+
+func TestValueConstraints(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema models.Schema
+		check  func(interface{}) bool
+	}{
+		{"Default", models.Schema{Type: "integer", Default: 7}, func(v interface{}) bool { return v == 7 }},
+		{"MultipleOf", models.Schema{Type: "integer", Minimum: float(10), Maximum: float(50), MultipleOf: float(15)}, func(v interface{}) bool {
+			n := v.(int64)
+			return n%15 == 0 && n >= 10 && n <= 50
+		}},
+		{"NegativeMaximum", models.Schema{Type: "number", Maximum: float(-10)}, func(v interface{}) bool { return v.(float64) <= -10 }},
+		{"NumberPrecision", models.Schema{Type: "number", Minimum: float(0), Maximum: float(1)}, func(v interface{}) bool {
+			n := v.(float64)
+			return n >= 0 && n <= 1 && math.Round(n*100) == n*100
+		}},
+		{"MinLength", models.Schema{Type: "string", MinLength: integer(40)}, func(v interface{}) bool { return len(v.(string)) >= 40 }},
+		{"MaxLength", models.Schema{Type: "string", Format: "uuid", MaxLength: integer(8)}, func(v interface{}) bool { return len(v.(string)) == 8 }},
+		{"MaxItemsZero", models.Schema{Type: "array", MaxItems: integer(0), Items: &models.Schema{Type: "string"}}, func(v interface{}) bool {
+			return len(v.([]interface{})) == 0
+		}},
+		{"UniqueItems", models.Schema{Type: "array", MinItems: integer(3), UniqueItems: true, Items: &models.Schema{Type: "boolean"}}, func(v interface{}) bool {
+			return len(v.([]interface{})) <= 2
+		}},
+		{"AllOf", models.Schema{AllOf: []models.Schema{
+			{Ref: "#/components/schemas/User"},
+			{Type: "object", Properties: map[string]models.Schema{"admin": {Type: "boolean", Example: true}}},
+		}}, func(v interface{}) bool {
+			obj := v.(map[string]interface{})
+			return obj["admin"] == true && obj["id"] != nil
+		}},
+		{"OneOf", models.Schema{OneOf: []models.Schema{{Type: "string", Format: "date"}, {Type: "integer"}}}, func(v interface{}) bool {
+			_, err := time.Parse(time.DateOnly, v.(string))
+			return err == nil
+		}},
+		{"UnresolvedRef", models.Schema{Ref: "#/components/schemas/Missing"}, func(v interface{}) bool { return v == nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := uint64(0); seed < 20; seed++ {
+				if v := New(testSchemas, seed).Value(tt.schema); !tt.check(v) {
+					t.Fatalf("Seed %d: unexpected value %#v", seed, v)
+				}
+			}
+		})
+	}
+}
+
+func TestNamed(t *testing.T) {
+	g := New(nil, 0)
+	if email, _ := g.Named("contactEmail", models.Schema{Type: "string"}).(string); !regexp.MustCompile(`^[a-z]+\.[a-z]+@example\.com$`).MatchString(email) {
+		t.Errorf("Expected an email address for contactEmail, got %q", email)
+	}
+	if city, _ := g.Named("city", models.Schema{Type: "string"}).(string); !slices.Contains(cities, city) {
+		t.Errorf("Expected a city, got %#v", city)
+	}
+	if id := g.Named("user_id", models.Schema{Type: "integer", Minimum: float(5), Maximum: float(5)}); id != int64(5) {
+		t.Errorf("Expected the name to leave other types alone, got %#v", id)
+	}
+}
+
+func TestMediaType(t *testing.T) {
+	g := New(testSchemas, 0)
+	schema := models.Schema{Type: "string", Example: "from the schema"}
+
+	tests := []struct {
+		name    string
+		content models.MediaType
+		want    interface{}
+	}{
+		{"Example", models.MediaType{Schema: schema, Example: "example", Examples: map[string]models.Example{"a": {Value: "named"}}}, "example"},
+		{"FirstNamedExample", models.MediaType{Schema: schema, Examples: map[string]models.Example{"b": {Value: "b"}, "a": {ExternalValue: "https://example.com"}, "c": {Value: "c"}}}, "b"},
+		{"Schema", models.MediaType{Schema: schema}, "from the schema"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := g.MediaType(tt.content); got != tt.want {
+				t.Errorf("Expected %#v, got %#v", tt.want, got)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package generator
 
 import (
-	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
@@ -14,6 +13,17 @@ const (
 	pathParameterType = "path"
 )
 
+// ginMethods are the HTTP methods gin.Engine has a dedicated registration method for
+var ginMethods = map[string]bool{
+	"GET":     true,
+	"POST":    true,
+	"PUT":     true,
+	"PATCH":   true,
+	"DELETE":  true,
+	"HEAD":    true,
+	"OPTIONS": true,
+}
+
 // APIMethod represents an API method for generation
 type APIMethod struct {
 	Name        string
@@ -32,10 +42,12 @@ import (
 
 // API defines the interface for API operations
 type API interface {
-{{range .Methods}}
+{{- range $i, $m := .Methods}}
+{{- if $i}}
+{{end}}
 	{{.Comment}}
 	{{.HandlerName}}(c *gin.Context{{.Parameters}})
-{{end}}
+{{- end}}
 }
 `
 
@@ -48,23 +60,22 @@ type API interface {
 	var methods []APIMethod
 	for path, operations := range spec.Paths {
 		for method, op := range operations {
-			handlerName := utils.ToCamelCase(op.OperationID)
+			handlerName := utils.ToGoIdentifier(op.OperationID)
 
 			// Build comment
-			comment := "// " + handlerName + " handles " + strings.ToUpper(method) + " " + path
+			comment := "// " + handlerName + " handles " + utils.EscapeComment(strings.ToUpper(method)+" "+path)
 			if op.Summary != "" {
-				comment += "\n\t// " + op.Summary
+				comment += "\n\t// " + utils.EscapeComment(op.Summary)
 			}
 			if op.Description != "" {
-				comment += "\n\t// " + op.Description
+				comment += "\n\t// " + utils.EscapeComment(op.Description)
 			}
 
 			// Build parameters
 			var params []string
 			for _, param := range op.Parameters {
 				if param.In == pathParameterType {
-					paramType := utils.GetGoType(param.Schema)
-					params = append(params, param.Name+" "+paramType)
+					params = append(params, goParamName(param.Name)+" "+goTypeOf(param.Schema))
 				}
 			}
 
@@ -88,11 +99,5 @@ type API interface {
 		Methods: methods,
 	}
 
-	f, err := os.Create(filepath.Join(baseDir, "api", "api.go"))
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	return tmpl.Execute(f, data)
+	return writeGoFile(filepath.Join(baseDir, "api", "api.go"), tmpl, data, "")
 }
@@ -0,0 +1,266 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+)
+
+// fakeMethod is a method of a generated fake
+type fakeMethod struct {
+	Name    string
+	Params  string // ", id string" parameters after the handler or client context
+	Args    string // ", id" passing the parameters on
+	Results string // result types, empty for handlers without a result
+	Zero    string // statement returning the zero results with an error, for the client
+}
+
+// GenerateAPITest generates generated/apitest, with fakes of api.APIHandlers
+// and client.API for unit tests that record their calls and respond with 501
+// Not Implemented unless told otherwise
+func GenerateAPITest(spec *models.OpenAPISpec, baseDir string, moduleName string, framework string, heuristic *PaginationHeuristic) error {
+	backend, err := LookupBackend(framework)
+	if err != nil {
+		return err
+	}
+
+	apitestTemplate := `// Code generated by gopenapi. DO NOT EDIT.
+
+// Package apitest provides fakes for unit tests: FakeHandlers implements
+// api.APIHandlers, and FakeClient implements client.API. Each method calls the
+// function in the field named after it, such as GetUserFunc, if it is set,
+// and responds with 501 Not Implemented otherwise. Every call is recorded for
+// assertions on its arguments.
+package apitest
+
+import (
+{{- range .Imports}}
+	{{goString .}}
+{{- end}}
+)
+
+// Call is a recorded call to a method of a fake
+type Call struct {
+	Method string
+	// Args are the arguments after the handler or client context, such as
+	// path parameters, query parameters and the request body
+	Args []interface{}
+}
+
+// Recorder records the calls to a fake. It is safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// record records a call to method
+func (r *Recorder) record(method string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns the calls to method in the order they were made, or every
+// call if method is empty
+func (r *Recorder) Calls(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, call := range r.calls {
+		if method == "" || call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// AssertCalled fails the test unless method was called with args. Arguments
+// are compared with reflect.DeepEqual, so they must have the parameters'
+// types: int64(7) rather than 7 for an int64 path parameter.
+func (r *Recorder) AssertCalled(t testing.TB, method string, args ...interface{}) {
+	t.Helper()
+	calls := r.Calls(method)
+	for _, call := range calls {
+		if reflect.DeepEqual(call.Args, args) {
+			return
+		}
+	}
+	if len(calls) == 0 {
+		t.Errorf("%s was not called", method)
+		return
+	}
+	got := make([]string, len(calls))
+	for i, call := range calls {
+		got[i] = fmt.Sprintf("%#v", call.Args)
+	}
+	t.Errorf("%s was not called with %#v, got calls with:\n%s", method, args, strings.Join(got, "\n"))
+}
+
+// AssertCalledTimes fails the test unless method was called n times
+func (r *Recorder) AssertCalledTimes(t testing.TB, method string, n int) {
+	t.Helper()
+	if calls := r.Calls(method); len(calls) != n {
+		t.Errorf("%s was called %d times, want %d", method, len(calls), n)
+	}
+}
+
+// AssertNotCalled fails the test if method was called
+func (r *Recorder) AssertNotCalled(t testing.TB, method string) {
+	t.Helper()
+	r.AssertCalledTimes(t, method, 0)
+}
+
+// FakeHandlers is a fake api.APIHandlers. Its zero value responds with 501
+// Not Implemented to every operation.
+type FakeHandlers struct {
+	Recorder
+{{range .Handlers}}
+	{{.Name}}Func func({{$.ContextParams}}{{.Params}}){{if .Results}} {{.Results}}{{end}}
+{{- end}}
+}
+
+var _ api.APIHandlers = (*FakeHandlers)(nil)
+{{range .Handlers}}
+// {{.Name}} records the call and calls {{.Name}}Func, responding with 501 Not
+// Implemented if it is nil
+func (f *FakeHandlers) {{.Name}}({{$.ContextParams}}{{.Params}}){{if .Results}} {{.Results}}{{end}} {
+	f.record({{goString .Name}}{{.Args}})
+	if f.{{.Name}}Func != nil {
+{{- if .Results}}
+		return f.{{.Name}}Func({{$.ContextArgs}}{{.Args}})
+{{- else}}
+		f.{{.Name}}Func({{$.ContextArgs}}{{.Args}})
+		return
+{{- end}}
+	}
+	{{$.NotImplemented}}
+}
+{{end}}
+// FakeClient is a fake client.API. Its zero value returns a
+// *client.APIError with status 501 Not Implemented from every operation.
+type FakeClient struct {
+	Recorder
+{{range .ClientMethods}}
+	{{.Name}}Func func(ctx context.Context{{.Params}}) {{.Results}}
+{{- end}}
+}
+
+var _ client.API = (*FakeClient)(nil)
+{{range .ClientMethods}}
+// {{.Name}} records the call and calls {{.Name}}Func, returning a 501
+// *client.APIError if it is nil
+func (c *FakeClient) {{.Name}}(ctx context.Context{{.Params}}) {{.Results}} {
+	c.record({{goString .Name}}{{.Args}})
+	if c.{{.Name}}Func != nil {
+		return c.{{.Name}}Func(ctx{{.Args}})
+	}
+	{{.Zero}}
+}
+{{end}}
+// notImplemented is the error of FakeClient's operations without a function
+func notImplemented() error {
+	return &client.APIError{
+		StatusCode: http.StatusNotImplemented,
+		Header:     http.Header{},
+		Body:       []byte(http.StatusText(http.StatusNotImplemented)),
+	}
+}
+`
+
+	tmpl, err := template.New("apitest").Funcs(templateFuncs).Parse(apitestTemplate)
+	if err != nil {
+		return err
+	}
+
+	var handlers []fakeMethod
+	for _, entry := range sortedOperations(spec) {
+		method := fakeMethod{
+			Name:    utils.ToGoIdentifier(entry.Operation.OperationID),
+			Results: backend.HandlerResult,
+		}
+		for _, param := range entry.Operation.Parameters {
+			if param.In == pathParameterType {
+				name := goParamName(param.Name)
+				method.Params += ", " + name + " " + goTypeOf(param.Schema)
+				method.Args += ", " + name
+			}
+		}
+		handlers = append(handlers, method)
+	}
+
+	operations, _, err := clientOperations(spec, heuristic)
+	if err != nil {
+		return err
+	}
+	var clientMethods []fakeMethod
+	for _, op := range operations {
+		method := fakeMethod{
+			Name:    op.Name,
+			Params:  op.PathArgs,
+			Args:    op.PathNames,
+			Results: "error",
+			Zero:    "return notImplemented()",
+		}
+		// The params type is declared by the client package; body and result
+		// types are models or builtin types, qualified already
+		if op.ParamsType != "" {
+			method.Params += ", params *client." + op.ParamsType
+			method.Args += ", params"
+		}
+		if op.BodyType != "" {
+			method.Params += ", body " + op.BodyType
+			method.Args += ", body"
+		}
+		if op.ReturnType != "" {
+			method.Results = "(" + op.ReturnType + ", error)"
+			method.Zero = "var zero " + op.ReturnType + "\n\treturn zero, notImplemented()"
+		}
+		clientMethods = append(clientMethods, method)
+	}
+
+	data := struct {
+		Imports        []string
+		ContextParams  string
+		ContextArgs    string
+		NotImplemented string
+		Handlers       []fakeMethod
+		ClientMethods  []fakeMethod
+	}{
+		Imports:       backend.Imports,
+		ContextParams: backend.ContextParams,
+		ContextArgs:   paramNames(backend.ContextParams),
+		// The 501 response of handler stubs, without their TODO comment
+		NotImplemented: strings.TrimPrefix(backend.ExampleCode("", nil), "// TODO: Implement your business logic here\n\n\t"),
+		Handlers:       handlers,
+		ClientMethods:  clientMethods,
+	}
+
+	apitestDir := filepath.Join(baseDir, "generated", "apitest")
+	if err := os.MkdirAll(apitestDir, 0755); err != nil {
+		return err
+	}
+	return writeGoFile(filepath.Join(apitestDir, "apitest.go"), tmpl, data, moduleName)
+}
+
+// paramNames returns the names of a Go parameter list such as
+// "w http.ResponseWriter, r *http.Request", separated by commas
+func paramNames(params string) string {
+	var names []string
+	for _, param := range strings.Split(params, ",") {
+		if fields := strings.Fields(param); len(fields) > 0 {
+			names = append(names, fields[0])
+		}
+	}
+	return strings.Join(names, ", ")
+}
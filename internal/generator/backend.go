@@ -0,0 +1,330 @@
+package generator
+
+import (
+	"fmt"
+	"go/version"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+)
+
+// Frameworks the generated server can be built on out of the box
+const (
+	FrameworkGin     = "gin"
+	FrameworkNetHTTP = "net/http"
+	FrameworkChi     = "chi"
+	FrameworkEcho    = "echo"
+)
+
+// Backend describes how the server side of the API is generated for a web
+// framework. The built-in backends are registered by the generator; others
+// can be added with RegisterBackend or loaded with LoadBackendFile.
+type Backend struct {
+	// Name selects the backend in Config.Framework
+	Name string
+	// ConvertPath converts an OpenAPI path to the framework's route syntax.
+	// It also returns the key each path parameter is looked up by when that
+	// differs from the parameter's name.
+	ConvertPath func(path string) (route string, keys map[string]string, err error)
+	// ContextParams are the parameters handler methods take before their path
+	// parameters, e.g. "c *gin.Context"
+	ContextParams string
+	// ContextTypes are the types of ContextParams, which check-handlers uses
+	// to recognize handler methods
+	ContextTypes []string
+	// HandlerResult is the result type of handler methods, if they have one
+	HandlerResult string
+	// Imports are the non-standard packages handlers and interfaces reference
+	Imports []string
+	// Require lists the requirements of a generated go.mod, e.g.
+	// "github.com/gin-gonic/gin v1.10.0"
+	Require []string
+	// GoVersion is the go directive of a generated go.mod
+	GoVersion string
+	// RouterType is the type Server.GetRouter returns
+	RouterType string
+	// RouterTemplate generates generated/server/router.go from a routerData.
+	// The generated main.go uses its NewServer, Start and Shutdown.
+	RouterTemplate string
+	// CommonServer generates the framework-neutral part of the Server in
+	// generated/server/server.go: its fields, options, NewServer, Start,
+	// StartTLS, Serve, Shutdown, GetRouter and the Use methods. RouterTemplate
+	// then declares only the Middleware type, the routerOptions struct
+	// embedded in Server, and the setupRouter, serverHandler, setupRoutes and
+	// setupSpecRoutes methods. Without it, RouterTemplate declares the whole
+	// Server.
+	CommonServer bool
+	// Funcs are functions RouterTemplate may call besides the generator's own
+	Funcs template.FuncMap
+	// ResponseHelpers adds api.WriteJSON and api.ReadJSON for handlers
+	// writing to an http.ResponseWriter
+	ResponseHelpers bool
+	// ExampleCode returns the body of the handler generated for an operation
+	// with method. example is the response the handler writes, from the spec
+	// or synthesized from its schema, nil if the operation has none to write.
+	ExampleCode func(method string, example *HandlerExample) string
+}
+
+// routerData is the data router templates are executed with
+type routerData struct {
+	ModuleName string
+	Routes     []routerRoute
+}
+
+// routerRoute is an operation registered on the router
+type routerRoute struct {
+	Method      string // upper case
+	Path        string // as written in the spec
+	Route       string // converted by the backend
+	HandlerName string
+	Comment     string
+	PathParams  []routeParam
+	Secured     bool // whether the operation has security requirements
+}
+
+// routeParam is a path parameter the router passes on to the handler
+type routeParam struct {
+	Name string // as written in the spec
+	Key  string // name the framework looks the parameter up by
+	Var  string // Go variable holding the value
+	Type string // Go type the handler takes the value as
+	// Parse is set when the raw string must be converted to Type with the
+	// generated pathParam, which responds 400 if it doesn't parse
+	Parse bool
+}
+
+// parsedPathParamTypes are the types other than string pathParam converts
+// path parameters to
+var parsedPathParamTypes = map[string]bool{
+	"int": true, "int32": true, "int64": true,
+	"float32": true, "float64": true,
+	"bool": true, "time.Time": true,
+}
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]*Backend{
+		FrameworkGin:     ginBackend(),
+		FrameworkNetHTTP: netHTTPBackend(),
+		FrameworkChi:     chiBackend(),
+		FrameworkEcho:    echoBackend(),
+	}
+)
+
+// RegisterBackend makes b available to Config.Framework under its name
+func RegisterBackend(b *Backend) error {
+	if b.Name == "" {
+		return fmt.Errorf("backend has no name")
+	}
+	if b.ConvertPath == nil {
+		return fmt.Errorf("backend %q has no path conversion", b.Name)
+	}
+	if b.ContextParams == "" || len(b.ContextTypes) == 0 {
+		return fmt.Errorf("backend %q has no handler context parameters", b.Name)
+	}
+	if b.RouterType == "" {
+		return fmt.Errorf("backend %q has no router type", b.Name)
+	}
+	if b.ExampleCode == nil {
+		return fmt.Errorf("backend %q has no handler example code", b.Name)
+	}
+	if _, err := b.routerTemplate(); err != nil {
+		return fmt.Errorf("backend %q: %w", b.Name, err)
+	}
+
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	if _, ok := backends[b.Name]; ok {
+		return fmt.Errorf("backend %q is already registered", b.Name)
+	}
+	backends[b.Name] = b
+	return nil
+}
+
+// LookupBackend returns the backend registered as name, defaulting to Gin
+func LookupBackend(name string) (*Backend, error) {
+	if name == "" {
+		name = FrameworkGin
+	}
+
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	b, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported framework %q (supported: %s)", name, strings.Join(backendNamesLocked(), ", "))
+	}
+	return b, nil
+}
+
+// BackendNames returns the names of the registered backends in order
+func BackendNames() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	return backendNamesLocked()
+}
+
+func backendNamesLocked() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// registeredContextTypes returns the handler context types of every backend
+func registeredContextTypes() [][]string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	var result [][]string
+	for _, name := range backendNamesLocked() {
+		result = append(result, backends[name].ContextTypes)
+	}
+	// Prefer the longest match when one backend's types prefix another's
+	sort.SliceStable(result, func(i, j int) bool { return len(result[i]) > len(result[j]) })
+	return result
+}
+
+// contextParams returns the context parameters, named, of the registered
+// backend whose handlers take contextTypes, or nil if there is none
+func contextParams(contextTypes []string) []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	for _, name := range backendNamesLocked() {
+		backend := backends[name]
+		if !slices.Equal(backend.ContextTypes, contextTypes) {
+			continue
+		}
+		params := strings.Split(backend.ContextParams, ",")
+		for i := range params {
+			params[i] = strings.TrimSpace(params[i])
+		}
+		return params
+	}
+	return nil
+}
+
+// routerTemplate parses the backend's router template
+func (b *Backend) routerTemplate() (*template.Template, error) {
+	if b.RouterTemplate == "" {
+		return nil, fmt.Errorf("no router template")
+	}
+	return template.New("router").Funcs(templateFuncs).Funcs(b.Funcs).Parse(b.RouterTemplate)
+}
+
+// xNetRequire is the requirement of golang.org/x/net every generated go.mod
+// has, the version Gin v1.10.0 requires
+const xNetRequire = "golang.org/x/net v0.25.0"
+
+// goVersion returns the oldest go directive the generated code builds with
+func (b *Backend) goVersion() string {
+	if b.GoVersion == "" {
+		return "1.21"
+	}
+	return b.GoVersion
+}
+
+// goMod returns the go.mod of a new project using the backend
+func (b *Backend) goMod(moduleName string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "module %s\n\ngo %s\n", moduleName, b.goVersion())
+	sb.WriteString("\nrequire (\n")
+	for _, req := range b.Require {
+		sb.WriteString("\t" + req + "\n")
+	}
+	// The server's h2c fallback for toolchains before Go 1.24 needs x/net
+	sb.WriteString("\t" + xNetRequire + "\n")
+	sb.WriteString(")\n")
+	return sb.String()
+}
+
+// raiseGoVersion raises the go directive of the existing go.mod at
+// goModPath to the backend's when it is older, or adds it when it is
+// missing. Generated code relies on the language and standard library of
+// that version, such as the method and wildcard patterns http.ServeMux only
+// matches in modules declaring go 1.22 or later.
+func (b *Backend) raiseGoVersion(goModPath string) error {
+	content, err := os.ReadFile(goModPath)
+	if err != nil {
+		return err
+	}
+
+	want := b.goVersion()
+	lines := strings.Split(string(content), "\n")
+	module := -1
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case "module":
+			module = i
+		case "go":
+			if version.Compare("go"+fields[1], "go"+want) >= 0 {
+				return nil
+			}
+			lines[i] = "go " + want
+			return os.WriteFile(goModPath, []byte(strings.Join(lines, "\n")), 0600)
+		}
+	}
+	if module < 0 {
+		return fmt.Errorf("%s declares no module", goModPath)
+	}
+
+	// Modules without a go directive are treated as go 1.16
+	lines = slices.Insert(lines, module+1, "", "go "+want)
+	return os.WriteFile(goModPath, []byte(strings.Join(lines, "\n")), 0600)
+}
+
+// buildRoutes collects the routes of every operation in spec
+func (b *Backend) buildRoutes(spec *models.OpenAPISpec) ([]routerRoute, error) {
+	var routes []routerRoute
+	for _, entry := range sortedOperations(spec) {
+		op := entry.Operation
+
+		route, keys, err := b.ConvertPath(entry.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		comment := entry.Method + " " + entry.Path
+		if op.Summary != "" {
+			comment += " - " + op.Summary
+		}
+
+		r := routerRoute{
+			Method:      entry.Method,
+			Path:        entry.Path,
+			Route:       route,
+			HandlerName: utils.ToGoIdentifier(op.OperationID),
+			Comment:     comment,
+			Secured:     len(effectiveSecurity(spec, op)) > 0,
+		}
+		for _, param := range op.Parameters {
+			if param.In != pathParameterType {
+				continue
+			}
+			key, ok := keys[param.Name]
+			if !ok {
+				key = param.Name
+			}
+			paramType := goTypeOf(param.Schema)
+			r.PathParams = append(r.PathParams, routeParam{
+				Name:  param.Name,
+				Key:   key,
+				Var:   goParamName(param.Name),
+				Type:  paramType,
+				Parse: parsedPathParamTypes[paramType],
+			})
+		}
+		routes = append(routes, r)
+	}
+	return routes, nil
+}
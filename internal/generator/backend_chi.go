@@ -0,0 +1,133 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+)
+
+// chiBackend generates a router built on chi. Handlers take the same
+// parameters as net/http handlers.
+func chiBackend() *Backend {
+	return &Backend{
+		Name:            FrameworkChi,
+		ConvertPath:     chiPath,
+		ContextParams:   "w http.ResponseWriter, r *http.Request",
+		ContextTypes:    []string{"http.ResponseWriter", "*http.Request"},
+		Require:         []string{"github.com/go-chi/chi/v5 v5.1.0"},
+		GoVersion:       "1.21",
+		RouterType:      "*chi.Mux",
+		RouterTemplate:  chiRouterTemplate,
+		CommonServer:    true,
+		ResponseHelpers: true,
+		ExampleCode:     netHTTPExampleCode,
+	}
+}
+
+// chiPath converts an OpenAPI path to a chi route, returning the URL
+// parameter names that differ from the path parameter names
+func chiPath(path string) (string, map[string]string, error) {
+	keys := map[string]string{}
+	for _, segment := range strings.Split(path, "{")[1:] {
+		name, _, _ := strings.Cut(segment, "}")
+		if key := strings.ReplaceAll(name, ":", "_"); key != name {
+			keys[name] = key
+		}
+	}
+	return utils.ConvertPathToChi(path), keys, nil
+}
+
+const chiRouterTemplate = `// Code generated by gopenapi. DO NOT EDIT.
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"{{.ModuleName}}/generated/api"
+)
+
+// routerOptions holds the options of the chi router, which has none
+type routerOptions struct{}
+
+// WithRouter registers the routes on router instead of a new chi router. It
+// is used as is, without the default request logger and panic recovery.
+func WithRouter(router *chi.Mux) Option {
+	return func(s *Server) {
+		s.router = router
+	}
+}
+
+// Middleware wraps the handler of an operation
+type Middleware func(http.Handler) http.Handler
+
+// setupRouter creates the chi router unless WithRouter gave one. It logs
+// requests to the logger given with WithLogger.
+func (s *Server) setupRouter() {
+	if s.router == nil {
+		logger := middleware.Logger
+		if s.logger != nil {
+			logger = middleware.RequestLogger(&middleware.DefaultLogFormatter{Logger: s.logger, NoColor: true})
+		}
+		s.router = chi.NewRouter()
+		s.router.Use(logger, middleware.Recoverer)
+	}
+}
+
+// serverHandler returns the handler the HTTP server runs, which the router's
+// own middleware already logs and recovers
+func (s *Server) serverHandler(handler http.Handler) http.Handler {
+	return handler
+}
+
+// operation returns the handler of the operation handled by handlerName,
+// running its middleware and handler once prepare lets the request through
+func (s *Server) operation(handlerName string, handler http.HandlerFunc) http.HandlerFunc {
+	op := operations[handlerName]
+	var h http.Handler = handler
+	mw := s.middleware.forOperation(op)
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		r, ok := s.prepare(w, r, op, handlerName)
+		if !ok {
+			return
+		}
+		h.ServeHTTP(w, r)
+	}
+}
+
+// setupRoutes configures all API routes
+func (s *Server) setupRoutes() {
+{{- range $i, $r := .Routes}}
+{{- if $i}}
+{{end}}
+	// {{comment .Comment}}
+	s.router.MethodFunc({{goString .Method}}, {{goString .Route}}, s.operation({{goString .HandlerName}}, func(w http.ResponseWriter, r *http.Request) {
+		{{- range .PathParams}}
+		{{- if .Parse}}
+		{{.Var}}, ok := pathParam[{{.Type}}](w, {{goString .Name}}, chi.URLParam(r, {{goString .Key}}))
+		if !ok {
+			return
+		}
+		{{- else}}
+		{{.Var}} := chi.URLParam(r, {{goString .Key}})
+		{{- end}}
+		{{- end}}
+		s.handlers.{{.HandlerName}}(w, r{{range .PathParams}}, {{.Var}}{{end}})
+	}))
+{{- end}}
+}
+
+// setupSpecRoutes serves the embedded OpenAPI document and API reference page
+func (s *Server) setupSpecRoutes() {
+	handler := SpecHandler()
+	for _, path := range specPaths {
+		s.router.Method(http.MethodGet, path, handler)
+		s.router.Method(http.MethodHead, path, handler)
+	}
+}
+`
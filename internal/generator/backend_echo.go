@@ -0,0 +1,136 @@
+package generator
+
+import "github.com/shubhamku044/gopenapi/pkg/utils"
+
+// echoBackend generates a router built on Echo. Handlers return an error
+// like Echo's own handler functions.
+func echoBackend() *Backend {
+	return &Backend{
+		Name: FrameworkEcho,
+		ConvertPath: func(path string) (string, map[string]string, error) {
+			return utils.ConvertPathToEcho(path), nil, nil
+		},
+		ContextParams:  "c echo.Context",
+		ContextTypes:   []string{"echo.Context"},
+		HandlerResult:  "error",
+		Imports:        []string{"github.com/labstack/echo/v4"},
+		Require:        []string{"github.com/labstack/echo/v4 v4.12.0"},
+		GoVersion:      "1.21",
+		RouterType:     "*echo.Echo",
+		RouterTemplate: echoRouterTemplate,
+		CommonServer:   true,
+		ExampleCode:    echoExampleCode,
+	}
+}
+
+const echoRouterTemplate = `// Code generated by gopenapi. DO NOT EDIT.
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"{{.ModuleName}}/generated/api"
+)
+
+// routerOptions holds the options of the Echo router, which has none
+type routerOptions struct{}
+
+// WithRouter registers the routes on router instead of a new Echo instance. It
+// is used as is, without the default request logger and panic recovery.
+func WithRouter(router *echo.Echo) Option {
+	return func(s *Server) {
+		s.router = router
+	}
+}
+
+// Middleware wraps the handler of an operation
+type Middleware = echo.MiddlewareFunc
+
+// setupRouter creates the Echo instance unless WithRouter gave one. It logs
+// requests to the logger given with WithLogger.
+func (s *Server) setupRouter() {
+	if s.router == nil {
+		logger := middleware.Logger()
+		if s.logger != nil {
+			logger = middleware.LoggerWithConfig(middleware.LoggerConfig{Output: s.logger.Writer()})
+		}
+		s.router = echo.New()
+		s.router.HideBanner = true
+		s.router.Use(logger, middleware.Recover())
+	}
+}
+
+// serverHandler returns the handler the HTTP server runs, which the
+// instance's own middleware already logs and recovers
+func (s *Server) serverHandler(handler http.Handler) http.Handler {
+	return handler
+}
+
+// operation returns the handler of the operation handled by handlerName,
+// running its middleware and handler once prepare lets the request through
+func (s *Server) operation(handlerName string, handler echo.HandlerFunc) echo.HandlerFunc {
+	op := operations[handlerName]
+	mw := s.middleware.forOperation(op)
+	for i := len(mw) - 1; i >= 0; i-- {
+		handler = mw[i](handler)
+	}
+	return func(c echo.Context) error {
+		r, ok := s.prepare(c.Response(), c.Request(), op, handlerName)
+		if !ok {
+			return nil
+		}
+		c.SetRequest(r)
+		return handler(c)
+	}
+}
+
+// setupRoutes configures all API routes
+func (s *Server) setupRoutes() {
+{{- range $i, $r := .Routes}}
+{{- if $i}}
+{{end}}
+	// {{comment .Comment}}
+	s.router.Add({{goString .Method}}, {{goString .Route}}, s.operation({{goString .HandlerName}}, func(c echo.Context) error {
+		{{- range .PathParams}}
+		{{- if .Parse}}
+		{{.Var}}, ok := pathParam[{{.Type}}](c.Response(), {{goString .Name}}, c.Param({{goString .Key}}))
+		if !ok {
+			return nil
+		}
+		{{- else}}
+		{{.Var}} := c.Param({{goString .Key}})
+		{{- end}}
+		{{- end}}
+		return s.handlers.{{.HandlerName}}(c{{range .PathParams}}, {{.Var}}{{end}})
+	}))
+{{- end}}
+}
+
+// setupSpecRoutes serves the embedded OpenAPI document and API reference page
+func (s *Server) setupSpecRoutes() {
+	handler := echo.WrapHandler(SpecHandler())
+	for _, path := range specPaths {
+		s.router.GET(path, handler)
+		s.router.HEAD(path, handler)
+	}
+}
+`
+
+// echoExampleCode returns the body of a generated Echo handler, writing
+// example if it is set and responding 501 Not Implemented otherwise
+func echoExampleCode(_ string, example *HandlerExample) string {
+	if example == nil {
+		return `// TODO: Implement your business logic here
+
+	return c.JSON(http.StatusNotImplemented, echo.Map{
+		"error": "Not implemented yet",
+	})`
+	}
+	if example.Body == "" {
+		return "// TODO: Implement your business logic here\n\n\treturn c.NoContent(" + example.Status + ")"
+	}
+	return "// TODO: Implement your business logic here\n\n\t" + example.Comment() + "\n\treturn c.JSON(" + example.Status + ", " + example.Body + ")"
+}
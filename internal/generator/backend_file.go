@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Path styles a backend file can declare
+const (
+	pathStyleColon  = "colon"  // /users/:id
+	pathStyleBraces = "braces" // /users/{id}
+)
+
+// backendFile is the YAML definition of a backend loaded with LoadBackendFile
+type backendFile struct {
+	Name            string   `yaml:"name"`
+	PathStyle       string   `yaml:"pathStyle"`
+	ContextParams   string   `yaml:"contextParams"`
+	ContextTypes    []string `yaml:"contextTypes"`
+	HandlerResult   string   `yaml:"handlerResult"`
+	Imports         []string `yaml:"imports"`
+	Require         []string `yaml:"require"`
+	GoVersion       string   `yaml:"goVersion"`
+	RouterType      string   `yaml:"routerType"`
+	RouterTemplate  string   `yaml:"routerTemplate"` // file relative to the backend file
+	ResponseHelpers bool     `yaml:"responseHelpers"`
+	NotImplemented  string   `yaml:"notImplemented"` // handler statements responding 501
+}
+
+// LoadBackendFile reads a backend defined in YAML, so frameworks without a
+// built-in backend can be supported without changing the generator. The
+// backend still has to be registered with RegisterBackend.
+func LoadBackendFile(filename string) (*Backend, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var def backendFile
+	if err := yaml.Unmarshal(content, &def); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if def.RouterTemplate == "" {
+		return nil, fmt.Errorf("%s: routerTemplate is required", filename)
+	}
+	if strings.TrimSpace(def.NotImplemented) == "" {
+		return nil, fmt.Errorf("%s: notImplemented is required", filename)
+	}
+
+	templatePath := def.RouterTemplate
+	if !filepath.IsAbs(templatePath) {
+		templatePath = filepath.Join(filepath.Dir(filename), templatePath)
+	}
+	routerTemplate, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Backend{
+		Name:            def.Name,
+		ContextParams:   def.ContextParams,
+		ContextTypes:    def.ContextTypes,
+		HandlerResult:   def.HandlerResult,
+		Imports:         def.Imports,
+		Require:         def.Require,
+		GoVersion:       def.GoVersion,
+		RouterType:      def.RouterType,
+		RouterTemplate:  string(routerTemplate),
+		ResponseHelpers: def.ResponseHelpers,
+	}
+
+	switch def.PathStyle {
+	case pathStyleColon:
+		b.ConvertPath = func(path string) (string, map[string]string, error) {
+			return utils.ConvertPathToGin(path), nil, nil
+		}
+	case pathStyleBraces:
+		b.ConvertPath = func(path string) (string, map[string]string, error) {
+			return path, nil, nil
+		}
+	default:
+		return nil, fmt.Errorf("%s: unknown pathStyle %q (supported: %s, %s)", filename, def.PathStyle, pathStyleColon, pathStyleBraces)
+	}
+
+	body := "// TODO: Implement your business logic here\n\n\t" +
+		strings.ReplaceAll(strings.TrimSpace(def.NotImplemented), "\n", "\n\t")
+	b.ExampleCode = func(string, *HandlerExample) string {
+		return body
+	}
+
+	return b, nil
+}
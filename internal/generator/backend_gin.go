@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"text/template"
+
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+)
+
+// ginBackend generates a router built on Gin, the default framework
+func ginBackend() *Backend {
+	return &Backend{
+		Name: FrameworkGin,
+		ConvertPath: func(path string) (string, map[string]string, error) {
+			return utils.ConvertPathToGin(path), nil, nil
+		},
+		ContextParams:  "c *gin.Context",
+		ContextTypes:   []string{"*gin.Context"},
+		Imports:        []string{"github.com/gin-gonic/gin"},
+		Require:        []string{"github.com/gin-gonic/gin v1.10.0"},
+		GoVersion:      "1.21",
+		RouterType:     "*gin.Engine",
+		RouterTemplate: ginRouterTemplate,
+		CommonServer:   true,
+		Funcs: template.FuncMap{
+			"ginMethod": func(method string) bool { return ginMethods[method] },
+		},
+		ExampleCode: ginExampleCode,
+	}
+}
+
+const ginRouterTemplate = `// Code generated by gopenapi. DO NOT EDIT.
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"{{.ModuleName}}/generated/api"
+)
+
+// routerOptions holds the options of the Gin router
+type routerOptions struct {
+	mode string
+}
+
+// WithRouter registers the routes on router instead of a new Gin engine. It
+// is used as is, without the default request logger and panic recovery.
+func WithRouter(router *gin.Engine) Option {
+	return func(s *Server) {
+		s.router = router
+	}
+}
+
+// WithMode sets Gin's mode, such as gin.ReleaseMode or gin.TestMode. The
+// mode is global to Gin, so it affects every engine in the process.
+func WithMode(mode string) Option {
+	return func(s *Server) {
+		s.mode = mode
+	}
+}
+
+// Middleware runs before the handler of an operation
+type Middleware = gin.HandlerFunc
+
+// setupRouter sets Gin's mode and creates the engine unless WithRouter gave
+// one. It logs requests to the logger given with WithLogger.
+func (s *Server) setupRouter() {
+	if s.mode != "" {
+		gin.SetMode(s.mode)
+	}
+	if s.router == nil {
+		if s.logger != nil {
+			s.router = gin.New()
+			s.router.Use(gin.LoggerWithWriter(s.logger.Writer()), gin.Recovery())
+		} else {
+			s.router = gin.Default()
+		}
+	}
+}
+
+// serverHandler returns the handler the HTTP server runs, which the engine's
+// own middleware already logs and recovers
+func (s *Server) serverHandler(handler http.Handler) http.Handler {
+	return handler
+}
+
+// operation returns the handlers of the operation handled by handlerName.
+// The first runs prepare, then its middleware and handler run if prepare
+// lets the request through.
+func (s *Server) operation(handlerName string, handler gin.HandlerFunc) []gin.HandlerFunc {
+	op := operations[handlerName]
+	prepare := func(c *gin.Context) {
+		r, ok := s.prepare(c.Writer, c.Request, op, handlerName)
+		if !ok {
+			c.Abort()
+			return
+		}
+		c.Request = r
+	}
+	handlers := append([]gin.HandlerFunc{prepare}, s.middleware.forOperation(op)...)
+	return append(handlers, handler)
+}
+
+// setupRoutes configures all API routes
+func (s *Server) setupRoutes() {
+{{- range $i, $r := .Routes}}
+{{- if $i}}
+{{end}}
+	// {{comment .Comment}}
+	{{if ginMethod .Method}}s.router.{{.Method}}({{else}}s.router.Handle({{goString .Method}}, {{end}}{{goString .Route}}, s.operation({{goString .HandlerName}}, func(c *gin.Context) {
+		{{- range .PathParams}}
+		{{- if .Parse}}
+		{{.Var}}, ok := pathParam[{{.Type}}](c.Writer, {{goString .Name}}, c.Param({{goString .Key}}))
+		if !ok {
+			return
+		}
+		{{- else}}
+		{{.Var}} := c.Param({{goString .Key}})
+		{{- end}}
+		{{- end}}
+		s.handlers.{{.HandlerName}}(c{{range .PathParams}}, {{.Var}}{{end}})
+	})...)
+{{- end}}
+}
+
+// setupSpecRoutes serves the embedded OpenAPI document and API reference page
+func (s *Server) setupSpecRoutes() {
+	handler := gin.WrapH(SpecHandler())
+	for _, path := range specPaths {
+		s.router.GET(path, handler)
+		s.router.HEAD(path, handler)
+	}
+}
+`
+
+// ginExampleCode returns the body of a generated Gin handler, writing
+// example if it is set and responding 501 Not Implemented otherwise
+func ginExampleCode(_ string, example *HandlerExample) string {
+	if example == nil {
+		return `// TODO: Implement your business logic here
+
+	c.JSON(http.StatusNotImplemented, gin.H{
+		"error": "Not implemented yet",
+	})`
+	}
+	if example.Body == "" {
+		return "// TODO: Implement your business logic here\n\n\tc.Status(" + example.Status + ")"
+	}
+	return "// TODO: Implement your business logic here\n\n\t" + example.Comment() + "\n\tc.JSON(" + example.Status + ", " + example.Body + ")"
+}
@@ -0,0 +1,256 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+)
+
+// netHTTPBackend generates a router built on the Go 1.22 http.ServeMux,
+// behaving like the Gin router
+func netHTTPBackend() *Backend {
+	return &Backend{
+		Name:          FrameworkNetHTTP,
+		ConvertPath:   serveMuxPath,
+		ContextParams: "w http.ResponseWriter, r *http.Request",
+		ContextTypes:  []string{"http.ResponseWriter", "*http.Request"},
+		// ServeMux method and wildcard patterns need go 1.22 or later
+		GoVersion:       "1.22",
+		RouterType:      "*http.ServeMux",
+		RouterTemplate:  netHTTPRouterTemplate,
+		CommonServer:    true,
+		ResponseHelpers: true,
+		ExampleCode:     netHTTPExampleCode,
+	}
+}
+
+const netHTTPRouterTemplate = `// Code generated by gopenapi. DO NOT EDIT.
+
+package server
+
+import (
+	"net/http"
+
+	"{{.ModuleName}}/generated/api"
+)
+
+// routerOptions holds the options of the ServeMux router, which has none
+type routerOptions struct{}
+
+// WithRouter registers the routes on router instead of a new ServeMux
+func WithRouter(router *http.ServeMux) Option {
+	return func(s *Server) {
+		s.router = router
+	}
+}
+
+// Middleware wraps the handler of an operation
+type Middleware func(http.Handler) http.Handler
+
+// setupRouter creates the ServeMux unless WithRouter gave one, and defaults
+// the logger to the standard logger
+func (s *Server) setupRouter() {
+	if s.router == nil {
+		s.router = http.NewServeMux()
+	}
+	if s.logger == nil {
+		s.logger = log.Default()
+	}
+}
+
+// serverHandler returns the handler the HTTP server runs, which logs
+// requests and recovers panics
+func (s *Server) serverHandler(handler http.Handler) http.Handler {
+	return logRequests(s.logger, recoverPanics(s.logger, handler))
+}
+
+// operation returns the handler of the operation handled by handlerName,
+// running its middleware and handler once prepare lets the request through
+func (s *Server) operation(handlerName string, handler http.HandlerFunc) http.HandlerFunc {
+	op := operations[handlerName]
+	var h http.Handler = handler
+	mw := s.middleware.forOperation(op)
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		r, ok := s.prepare(w, r, op, handlerName)
+		if !ok {
+			return
+		}
+		h.ServeHTTP(w, r)
+	}
+}
+
+// setupRoutes configures all API routes
+func (s *Server) setupRoutes() {
+{{- range $i, $r := .Routes}}
+{{- if $i}}
+{{end}}
+	// {{comment .Comment}}
+	s.router.HandleFunc({{goString (print .Method " " .Route)}}, s.operation({{goString .HandlerName}}, func(w http.ResponseWriter, r *http.Request) {
+		{{- range .PathParams}}
+		{{- if .Parse}}
+		{{.Var}}, ok := pathParam[{{.Type}}](w, {{goString .Name}}, r.PathValue({{goString .Key}}))
+		if !ok {
+			return
+		}
+		{{- else}}
+		{{.Var}} := r.PathValue({{goString .Key}})
+		{{- end}}
+		{{- end}}
+		s.handlers.{{.HandlerName}}(w, r{{range .PathParams}}, {{.Var}}{{end}})
+	}))
+{{- end}}
+}
+
+// setupSpecRoutes serves the embedded OpenAPI document and API reference page
+func (s *Server) setupSpecRoutes() {
+	handler := SpecHandler()
+	for _, path := range specPaths {
+		// GET patterns also match HEAD requests
+		s.router.Handle("GET "+path, handler)
+	}
+}
+
+// statusRecorder captures the status code written to a ResponseWriter
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code before writing it
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying ResponseWriter
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// logRequests logs every request with its status and latency
+func logRequests(logger *log.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		logger.Printf("%3d | %13v | %15s | %-7s %q", rec.status, time.Since(start), r.RemoteAddr, r.Method, r.URL.Path)
+	})
+}
+
+// recoverPanics turns a panicking handler into a 500 response
+func recoverPanics(logger *log.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if err := recover(); err != nil {
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				logger.Printf("panic recovered: %v", err)
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+`
+
+// serveMuxPath converts an OpenAPI path to the path of a ServeMux pattern.
+// Wildcards must be valid Go identifiers, so it also returns the wildcard
+// name used for each path parameter.
+func serveMuxPath(path string) (string, map[string]string, error) {
+	wildcards := map[string]string{}
+	used := map[string]bool{}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if !strings.ContainsAny(segment, "{}") {
+			continue
+		}
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") || strings.Count(segment, "{") != 1 {
+			return "", nil, fmt.Errorf("path %s: net/http path parameters must span a whole segment", path)
+		}
+
+		name := segment[1 : len(segment)-1]
+		wildcard := utils.SanitizeIdentifier(name)
+		if used[wildcard] {
+			return "", nil, fmt.Errorf("path %s: path parameters collide as wildcard %q", path, wildcard)
+		}
+		used[wildcard] = true
+		wildcards[name] = wildcard
+		segments[i] = "{" + wildcard + "}"
+	}
+
+	pattern := strings.Join(segments, "/")
+	if strings.HasSuffix(pattern, "/") {
+		// A trailing slash would otherwise match every path below it
+		pattern += "{$}"
+	}
+	return pattern, wildcards, nil
+}
+
+// generateResponseHelpers writes the helpers net/http handlers use to write
+// JSON responses to generated/api/responses.go, and removes them for backends
+// that don't need them
+func generateResponseHelpers(baseDir string, moduleName string, backend *Backend) error {
+	responsesTemplate := `// Code generated by gopenapi. DO NOT EDIT.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// WriteJSON writes v as a JSON response with the given status code
+func WriteJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if v != nil && status != http.StatusNoContent {
+		_ = json.NewEncoder(w).Encode(v)
+	}
+}
+
+// ReadJSON decodes the JSON request body into v
+func ReadJSON(r *http.Request, v interface{}) error {
+	return json.NewDecoder(r.Body).Decode(v)
+}
+`
+
+	filename := filepath.Join(baseDir, "generated", "api", "responses.go")
+	if !backend.ResponseHelpers {
+		if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	tmpl, err := template.New("responses").Parse(responsesTemplate)
+	if err != nil {
+		return err
+	}
+
+	return writeGoFile(filename, tmpl, nil, moduleName)
+}
+
+// netHTTPExampleCode returns the body of a generated net/http handler, writing
+// example if it is set and responding 501 Not Implemented otherwise
+func netHTTPExampleCode(_ string, example *HandlerExample) string {
+	if example == nil {
+		return `// TODO: Implement your business logic here
+
+	api.WriteJSON(w, http.StatusNotImplemented, map[string]interface{}{
+		"error": "Not implemented yet",
+	})`
+	}
+	if example.Body == "" {
+		return "// TODO: Implement your business logic here\n\n\tw.WriteHeader(" + example.Status + ")"
+	}
+	return "// TODO: Implement your business logic here\n\n\t" + example.Comment() + "\n\tapi.WriteJSON(w, " + example.Status + ", " + example.Body + ")"
+}
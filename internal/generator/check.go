@@ -0,0 +1,277 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+)
+
+// HandlerIssue describes a handler method that doesn't match api.APIHandlers
+type HandlerIssue struct {
+	Method string
+	File   string
+	Line   int
+	Have   string // signature found in handlers/
+	Want   string // signature declared by api.APIHandlers, empty for obsolete methods
+
+	decl *handlerMethodDecl
+}
+
+// HandlerReport lists the differences between the user's handlers and api.APIHandlers
+type HandlerReport struct {
+	TypeName string
+	Missing  []string // handler names, prefixed with their interface when split by tag
+	Obsolete []HandlerIssue
+	Drifted  []HandlerIssue
+}
+
+// HasIssues reports whether the handlers don't match the spec
+func (r *HandlerReport) HasIssues() bool {
+	return len(r.Missing) > 0 || len(r.Obsolete) > 0 || len(r.Drifted) > 0
+}
+
+// handlerSignature is the signature api.APIHandlers declares for an operation
+type handlerSignature struct {
+	Params []string // "name type" pairs following the framework's context parameters
+	Types  []string
+}
+
+// expectedHandlerSignatures returns the api.APIHandlers methods generated for spec
+func expectedHandlerSignatures(spec *models.OpenAPISpec) map[string]handlerSignature {
+	signatures := map[string]handlerSignature{}
+	for _, operations := range spec.Paths {
+		for _, op := range operations {
+			var sig handlerSignature
+			for _, param := range op.Parameters {
+				if param.In == pathParameterType {
+					paramType := goTypeOf(param.Schema)
+					sig.Params = append(sig.Params, goParamName(param.Name)+" "+paramType)
+					sig.Types = append(sig.Types, paramType)
+				}
+			}
+			signatures[utils.ToGoIdentifier(op.OperationID)] = sig
+		}
+	}
+	return signatures
+}
+
+// CheckHandlers compares the user's handlers in baseDir/handlers against the
+// api.APIHandlers interface generated from spec. Handlers split by tag are
+// compared against each tag's interface.
+func CheckHandlers(spec *models.OpenAPISpec, baseDir string) (*HandlerReport, error) {
+	handlersDir := filepath.Join(baseDir, "handlers")
+	pkg, err := parseHandlerPackage(handlersDir)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := expectedHandlerSignatures(spec)
+
+	if !pkg.Types["APIHandlers"] {
+		groups := tagGroups(spec)
+		split := false
+		for _, group := range groups {
+			split = split || pkg.Types[group.Name]
+		}
+		if split {
+			report := &HandlerReport{}
+			var typeNames []string
+			for _, group := range groups {
+				typeNames = append(typeNames, group.Name)
+				groupExpected := map[string]handlerSignature{}
+				for _, name := range group.Handlers {
+					groupExpected[name] = expected[name]
+				}
+				impl := pkg.impl(group.Name)
+				if impl == nil {
+					impl = &handlerImpl{TypeName: group.Name}
+				}
+				compareHandlers(report, impl, groupExpected, group.Name+".")
+			}
+			report.TypeName = strings.Join(typeNames, ", ")
+			sortHandlerReport(report)
+			return report, nil
+		}
+	}
+
+	impl := pkg.apiImpl()
+	if impl == nil {
+		return nil, fmt.Errorf("no implementation of api.APIHandlers found in %s", handlersDir)
+	}
+
+	report := &HandlerReport{TypeName: impl.TypeName}
+	compareHandlers(report, impl, expected, "")
+	sortHandlerReport(report)
+	return report, nil
+}
+
+// compareHandlers adds the differences between impl's methods and the
+// expected handlers to report. Missing handlers are reported with prefix.
+func compareHandlers(report *HandlerReport, impl *handlerImpl, expected map[string]handlerSignature, prefix string) {
+	for name := range expected {
+		if impl.Methods[name] == nil {
+			report.Missing = append(report.Missing, prefix+name)
+		}
+	}
+
+	for name, decl := range impl.Methods {
+		paramTypes := fieldTypes(decl.Params)
+		// Only methods shaped like handlers are compared; helpers are left alone
+		n := contextParamCount(paramTypes)
+		if n == 0 {
+			continue
+		}
+
+		issue := HandlerIssue{
+			Method: name,
+			File:   decl.File,
+			Line:   decl.Line,
+			Have:   name + fieldListString(decl.Params),
+			decl:   decl,
+		}
+
+		sig, ok := expected[name]
+		if !ok {
+			report.Obsolete = append(report.Obsolete, issue)
+			continue
+		}
+		if !slices.Equal(paramTypes[n:], sig.Types) {
+			context := fieldParams(decl.Params)[:n:n]
+			want := sig.Params
+			if names := fieldNames(decl.Params); len(names)-n == len(sig.Types) {
+				// Only the types drifted, so the user's names are kept
+				want = make([]string, len(sig.Types))
+				for i, typ := range sig.Types {
+					want[i] = strings.TrimSpace(names[n+i] + " " + typ)
+				}
+			} else if len(want) > 0 && names[0] == "" {
+				// Unnamed context parameters can't precede named ones
+				if named := contextParams(paramTypes[:n]); len(named) == n {
+					context = named
+				} else {
+					want = sig.Types
+				}
+			}
+			params := append(context, want...)
+			issue.Want = name + "(" + strings.Join(params, ", ") + ")"
+			report.Drifted = append(report.Drifted, issue)
+		}
+	}
+}
+
+// sortHandlerReport sorts the entries of report
+func sortHandlerReport(report *HandlerReport) {
+	sort.Strings(report.Missing)
+	sortIssues(report.Obsolete)
+	sortIssues(report.Drifted)
+}
+
+// FixHandlerSignatures rewrites the parameter lists of drifted handler methods
+// to match api.APIHandlers. Method bodies and all other code are left untouched.
+func FixHandlerSignatures(report *HandlerReport) error {
+	byFile := map[string][]HandlerIssue{}
+	for _, issue := range report.Drifted {
+		byFile[issue.File] = append(byFile[issue.File], issue)
+	}
+
+	for file, issues := range byFile {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		// Rewrite from the end of the file so earlier offsets stay valid
+		sort.Slice(issues, func(i, j int) bool { return issues[i].decl.Start > issues[j].decl.Start })
+		for _, issue := range issues {
+			params := strings.TrimPrefix(issue.Want, issue.Method)
+			src = append(src[:issue.decl.Start], append([]byte(params), src[issue.decl.End:]...)...)
+		}
+
+		if err := os.WriteFile(file, src, 0600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fieldTypes returns the type of every parameter in fields
+func fieldTypes(fields *ast.FieldList) []string {
+	var result []string
+	for _, field := range fields.List {
+		typ := types.ExprString(field.Type)
+		n := max(len(field.Names), 1)
+		for i := 0; i < n; i++ {
+			result = append(result, typ)
+		}
+	}
+	return result
+}
+
+// contextParamCount returns how many of the leading paramTypes are a
+// registered backend's handler context, or 0 if the method isn't shaped like
+// a handler
+func contextParamCount(paramTypes []string) int {
+	for _, contextTypes := range registeredContextTypes() {
+		if len(paramTypes) >= len(contextTypes) && slices.Equal(paramTypes[:len(contextTypes)], contextTypes) {
+			return len(contextTypes)
+		}
+	}
+	return 0
+}
+
+// fieldListString renders a parameter list as it appears in a signature
+func fieldListString(fields *ast.FieldList) string {
+	return "(" + strings.Join(fieldParams(fields), ", ") + ")"
+}
+
+// fieldParams renders each parameter in fields as "name type", or just the
+// type for unnamed parameters
+func fieldParams(fields *ast.FieldList) []string {
+	var params []string
+	for _, field := range fields.List {
+		typ := types.ExprString(field.Type)
+		if len(field.Names) == 0 {
+			params = append(params, typ)
+			continue
+		}
+		for _, name := range field.Names {
+			params = append(params, name.Name+" "+typ)
+		}
+	}
+	return params
+}
+
+// fieldNames returns the name of every parameter in fields, empty for
+// unnamed parameters
+func fieldNames(fields *ast.FieldList) []string {
+	var names []string
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			names = append(names, "")
+			continue
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+// sortIssues orders issues by file and line
+func sortIssues(issues []HandlerIssue) {
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		return issues[i].Line < issues[j].Line
+	})
+}
@@ -0,0 +1,962 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+)
+
+// clientReservedNames are identifiers generated client methods already use,
+// including the packages the client imports
+var clientReservedNames = map[string]bool{
+	"c": true, "ctx": true, "params": true, "body": true, "r": true,
+	"resp": true, "respBody": true, "result": true, "err": true, "data": true,
+	"models": true, "http": true, "json": true, "url": true, "fmt": true,
+	"context": true, "strings": true, "time": true,
+	// used by the iterators of paginated operations
+	"p": true, "page": true, "items": true, "item": true, "next": true,
+	"first": true, "zero": true, "yield": true, "iter": true,
+}
+
+// clientParam is a query or header parameter of a client operation
+type clientParam struct {
+	Name     string // name in the spec
+	Field    string // field in the <Operation>Params struct
+	Type     string
+	Style    string
+	Explode  bool
+	Optional bool // optional scalars are pointers and skipped when nil
+	Nilable  bool // slices and maps are skipped when nil
+}
+
+// clientErrorResponse maps a response status condition to its error body type
+type clientErrorResponse struct {
+	Cond string
+	Type string
+}
+
+// clientOperation is the data a client method is generated from
+type clientOperation struct {
+	Name         string
+	Comment      string
+	Method       string
+	PathExpr     string
+	PathArgs     string // ", id string" arguments for path parameters
+	PathNames    string // ", id" passing the path arguments on
+	ParamsType   string
+	QueryParams  []clientParam
+	HeaderParams []clientParam
+	BodyType     string
+	BodyKind     string // json, form or raw
+	ContentType  string
+	ReturnType   string
+	DecodeType   string
+	ReturnsPtr   bool
+	Errors       []clientErrorResponse
+	ErrorDefault string // error body type of the default response
+	Retryable    bool
+	Timeout      string // Go expression for the x-timeout duration
+}
+
+// idempotentMethods are the HTTP methods that are safe to retry
+var idempotentMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"OPTIONS": true,
+	"TRACE":   true,
+	"PUT":     true,
+	"DELETE":  true,
+}
+
+// GenerateClient generates a typed HTTP client for the API in generated/client/.
+// Paginated operations, recognized by heuristic if they have no x-pagination
+// extension, also get iterators over their items.
+func GenerateClient(spec *models.OpenAPISpec, baseDir string, moduleName string, heuristic *PaginationHeuristic) error {
+	server, err := resolveServer(spec, "", nil)
+	if err != nil {
+		return err
+	}
+	return generateClient(spec, baseDir, moduleName, heuristic, server)
+}
+
+// generateClient is GenerateClient calling the given server by default
+func generateClient(spec *models.OpenAPISpec, baseDir string, moduleName string, heuristic *PaginationHeuristic, server serverInfo) error {
+	clientTemplate := `// Code generated by gopenapi. DO NOT EDIT.
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client calls the {{comment .API}} over HTTP
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	retry      RetryPolicy
+	timeout    time.Duration
+	timeouts   map[string]time.Duration
+}
+
+// RetryPolicy controls how failed requests are retried. Only idempotent
+// operations and operations marked x-retryable in the spec are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, the first included.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after each retry
+	Multiplier float64
+	// Jitter randomizes each wait by up to this fraction of it, between 0 and 1
+	Jitter float64
+	// ShouldRetry decides whether an attempt is retried. When nil, network
+	// errors and 429, 502, 503 and 504 responses are retried.
+	ShouldRetry func(resp *http.Response, err error) bool
+}
+
+// DefaultRetryPolicy returns a policy making up to three attempts with
+// exponential backoff starting at 100ms
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used to send requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithRetry retries failed requests according to policy
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithTimeout limits the time each operation may take, retries included.
+// It overrides the x-timeout the spec declares for an operation.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithOperationTimeout limits the time the named operation may take, retries
+// included. It takes precedence over WithTimeout.
+func WithOperationTimeout(operation string, timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeouts[operation] = timeout
+	}
+}
+
+{{if .BaseURL -}}
+// DefaultBaseURL is the URL of the server the spec declares
+const DefaultBaseURL = {{goString .BaseURL}}
+
+// NewClient creates a client for the API served at baseURL, or at
+// DefaultBaseURL if baseURL is empty
+func NewClient(baseURL string, opts ...Option) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+{{- else -}}
+// NewClient creates a client for the API served at baseURL
+func NewClient(baseURL string, opts ...Option) *Client {
+{{- end}}
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		timeouts:   map[string]time.Duration{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is returned when the API responds with a non-2xx status code
+type APIError struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Decoded is the body decoded into the error schema the spec declares
+	// for the status code, or nil if there is none
+	Decoded interface{}
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// newAPIError builds an APIError, decoding the body into the value errorType
+// returns for the status code
+func newAPIError(resp *http.Response, body []byte, errorType func(status int) interface{}) error {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}
+	if errorType != nil {
+		if v := errorType(resp.StatusCode); v != nil && json.Unmarshal(body, v) == nil {
+			apiErr.Decoded = v
+		}
+	}
+	return apiErr
+}
+
+// request describes a call to an operation
+type request struct {
+	operation   string
+	method      string
+	path        string
+	query       url.Values
+	header      http.Header
+	body        []byte
+	contentType string
+	retryable   bool          // idempotent or marked x-retryable
+	timeout     time.Duration // x-timeout from the spec
+}
+
+// do sends r, retrying as the client's policy allows, and returns the final
+// response with its body read
+func (c *Client) do(ctx context.Context, r request) (*http.Response, []byte, error) {
+	timeout := r.timeout
+	if c.timeout > 0 {
+		timeout = c.timeout
+	}
+	if t, ok := c.timeouts[r.operation]; ok {
+		timeout = t
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	attempts := 1
+	if r.retryable && c.retry.MaxAttempts > 1 {
+		attempts = c.retry.MaxAttempts
+	}
+	shouldRetry := c.retry.ShouldRetry
+	if shouldRetry == nil {
+		shouldRetry = defaultShouldRetry
+	}
+
+	backoff := c.retry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		resp, respBody, err := c.send(ctx, r)
+		if attempt >= attempts || ctx.Err() != nil || !shouldRetry(resp, err) {
+			return resp, respBody, err
+		}
+
+		wait := c.retry.jittered(backoff)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				wait = retryAfter
+			}
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			// The next attempt can't finish in time
+			return resp, respBody, err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff = c.retry.next(backoff)
+	}
+}
+
+// defaultShouldRetry retries network errors and responses signalling a
+// temporary condition
+func defaultShouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// next returns the backoff following backoff
+func (p RetryPolicy) next(backoff time.Duration) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	backoff = time.Duration(float64(backoff) * multiplier)
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	return backoff
+}
+
+// jittered randomizes backoff by up to the policy's jitter fraction
+func (p RetryPolicy) jittered(backoff time.Duration) time.Duration {
+	if p.Jitter <= 0 || backoff <= 0 {
+		return backoff
+	}
+	jitter := min(p.Jitter, 1)
+	// Jitter doesn't need a cryptographic source
+	return time.Duration(float64(backoff) * (1 - jitter*rand.Float64()))
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// send makes a single attempt at r
+func (c *Client) send(ctx context.Context, r request) (*http.Response, []byte, error) {
+	u := c.baseURL + r.path
+	if len(r.query) > 0 {
+		u += "?" + r.query.Encode()
+	}
+
+	var body io.Reader
+	if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.method, u, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	for name, values := range r.header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	if r.contentType != "" {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, respBody, nil
+}
+
+// formatParam returns the string form of a scalar parameter value
+func formatParam(v reflect.Value) string {
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// paramParts splits a parameter value into its elements. Objects are returned
+// as alternating keys and values with isObject set.
+func paramParts(value interface{}) (parts []string, isObject bool) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return []string{string(v.Bytes())}, false
+		}
+		for i := 0; i < v.Len(); i++ {
+			parts = append(parts, formatParam(v.Index(i)))
+		}
+		return parts, false
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		values := map[string]string{}
+		for _, key := range v.MapKeys() {
+			k := fmt.Sprint(key.Interface())
+			keys = append(keys, k)
+			values[k] = formatParam(v.MapIndex(key))
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			parts = append(parts, k, values[k])
+		}
+		return parts, true
+	default:
+		return []string{formatParam(v)}, false
+	}
+}
+
+// pathParam serializes a path parameter according to its style
+func pathParam(name, style string, explode bool, value interface{}) string {
+	parts, isObject := paramParts(value)
+	for i := range parts {
+		parts[i] = url.PathEscape(parts[i])
+	}
+
+	switch style {
+	case "label":
+		if explode {
+			return "." + joinParts(parts, isObject, "=", ".")
+		}
+		return "." + strings.Join(parts, ",")
+	case "matrix":
+		if explode && !isObject {
+			return ";" + name + "=" + strings.Join(parts, ";"+name+"=")
+		}
+		if explode {
+			return ";" + joinParts(parts, true, "=", ";")
+		}
+		return ";" + name + "=" + strings.Join(parts, ",")
+	default: // simple
+		if explode {
+			return joinParts(parts, isObject, "=", ",")
+		}
+		return strings.Join(parts, ",")
+	}
+}
+
+// addQueryParam serializes a query parameter according to its style
+func addQueryParam(query url.Values, name, style string, explode bool, value interface{}) {
+	parts, isObject := paramParts(value)
+
+	switch style {
+	case "deepObject":
+		for i := 0; i+1 < len(parts); i += 2 {
+			query.Add(name+"["+parts[i]+"]", parts[i+1])
+		}
+	case "spaceDelimited":
+		query.Add(name, strings.Join(parts, " "))
+	case "pipeDelimited":
+		query.Add(name, strings.Join(parts, "|"))
+	default: // form
+		switch {
+		case explode && isObject:
+			for i := 0; i+1 < len(parts); i += 2 {
+				query.Add(parts[i], parts[i+1])
+			}
+		case explode:
+			for _, part := range parts {
+				query.Add(name, part)
+			}
+		default:
+			query.Add(name, strings.Join(parts, ","))
+		}
+	}
+}
+
+// headerParam serializes a header parameter using the simple style
+func headerParam(explode bool, value interface{}) string {
+	parts, isObject := paramParts(value)
+	if explode {
+		return joinParts(parts, isObject, "=", ",")
+	}
+	return strings.Join(parts, ",")
+}
+
+// joinParts joins parameter parts, pairing object keys and values with kv
+func joinParts(parts []string, isObject bool, kv, sep string) string {
+	if !isObject {
+		return strings.Join(parts, sep)
+	}
+	pairs := make([]string, 0, len(parts)/2)
+	for i := 0; i+1 < len(parts); i += 2 {
+		pairs = append(pairs, parts[i]+kv+parts[i+1])
+	}
+	return strings.Join(pairs, sep)
+}
+
+// formBody encodes a request body as application/x-www-form-urlencoded
+func formBody(body interface{}) ([]byte, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	for name, value := range fields {
+		addQueryParam(form, name, "form", true, value)
+	}
+	return []byte(form.Encode()), nil
+}
+`
+
+	operationsTemplate := `// Code generated by gopenapi. DO NOT EDIT.
+
+package client
+
+// API is implemented by Client with a method per operation. Code depending on
+// it rather than on *Client can be tested with a fake such as
+// apitest.FakeClient.
+type API interface {
+{{- range .Operations}}
+	{{.Name}}(ctx context.Context{{.PathArgs}}{{if .ParamsType}}, params *{{.ParamsType}}{{end}}{{if .BodyType}}, body {{.BodyType}}{{end}}) {{if .ReturnType}}({{.ReturnType}}, error){{else}}error{{end}}
+{{- end}}
+}
+
+var _ API = (*Client)(nil)
+{{range .Operations}}
+{{- if .ParamsType}}
+
+// {{.ParamsType}} holds the query and header parameters of {{.Name}}
+type {{.ParamsType}} struct {
+{{- range .QueryParams}}
+	{{.Field}} {{if .Optional}}*{{end}}{{.Type}}
+{{- end}}
+{{- range .HeaderParams}}
+	{{.Field}} {{if .Optional}}*{{end}}{{.Type}}
+{{- end}}
+}
+{{- end}}
+
+// {{.Name}} {{comment .Comment}}
+func (c *Client) {{.Name}}(ctx context.Context{{.PathArgs}}{{if .ParamsType}}, params *{{.ParamsType}}{{end}}{{if .BodyType}}, body {{.BodyType}}{{end}}) {{if .ReturnType}}({{.ReturnType}}, error){{else}}error{{end}} {
+	r := request{
+		operation: {{goString .Name}},
+		method:    {{goString .Method}},
+		path:      {{.PathExpr}},
+		query:     url.Values{},
+		header:    http.Header{},
+		retryable: {{.Retryable}},
+{{- if .Timeout}}
+		timeout:   {{.Timeout}},
+{{- end}}
+	}
+{{- if .ParamsType}}
+	if params != nil {
+{{- range .QueryParams}}
+{{- if or .Optional .Nilable}}
+		if params.{{.Field}} != nil {
+			addQueryParam(r.query, {{goString .Name}}, {{goString .Style}}, {{.Explode}}, params.{{.Field}})
+		}
+{{- else}}
+		addQueryParam(r.query, {{goString .Name}}, {{goString .Style}}, {{.Explode}}, params.{{.Field}})
+{{- end}}
+{{- end}}
+{{- range .HeaderParams}}
+{{- if or .Optional .Nilable}}
+		if params.{{.Field}} != nil {
+			r.header.Set({{goString .Name}}, headerParam({{.Explode}}, params.{{.Field}}))
+		}
+{{- else}}
+		r.header.Set({{goString .Name}}, headerParam({{.Explode}}, params.{{.Field}}))
+{{- end}}
+{{- end}}
+	}
+{{- end}}
+{{- if eq .BodyKind "json"}}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return {{if .ReturnType}}nil, {{end}}fmt.Errorf("encoding {{.Name}} request: %w", err)
+	}
+	r.body = data
+	r.contentType = {{goString .ContentType}}
+{{- else if eq .BodyKind "form"}}
+	data, err := formBody(body)
+	if err != nil {
+		return {{if .ReturnType}}nil, {{end}}fmt.Errorf("encoding {{.Name}} request: %w", err)
+	}
+	r.body = data
+	r.contentType = {{goString .ContentType}}
+{{- else if eq .BodyKind "raw"}}
+	r.body = body
+	r.contentType = {{goString .ContentType}}
+{{- end}}
+
+	resp, respBody, err := c.do(ctx, r)
+	if err != nil {
+		return {{if .ReturnType}}nil, {{end}}err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+{{- if or .Errors .ErrorDefault}}
+		return {{if .ReturnType}}nil, {{end}}newAPIError(resp, respBody, func(status int) interface{} {
+			switch {
+{{- range .Errors}}
+			case {{.Cond}}:
+				return new({{.Type}})
+{{- end}}
+			default:
+				return {{if .ErrorDefault}}new({{.ErrorDefault}}){{else}}nil{{end}}
+			}
+		})
+{{- else}}
+		return {{if .ReturnType}}nil, {{end}}newAPIError(resp, respBody, nil)
+{{- end}}
+	}
+{{- if .ReturnType}}
+	if len(respBody) == 0 {
+		return nil, nil
+	}
+
+	var result {{.DecodeType}}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("decoding {{.Name}} response: %w", err)
+	}
+	return {{if .ReturnsPtr}}&{{end}}result, nil
+{{- else}}
+	return nil
+{{- end}}
+}
+{{- end}}
+`
+
+	tmpl, err := template.New("client").Funcs(templateFuncs).Parse(clientTemplate)
+	if err != nil {
+		return err
+	}
+
+	api := spec.Info.Title
+	if strings.TrimSpace(api) == "" {
+		api = "generated"
+	}
+	// Titles such as "Pet Store API" already name the API
+	if fields := strings.Fields(api); !strings.EqualFold(fields[len(fields)-1], "API") {
+		api += " API"
+	}
+
+	data := struct {
+		API     string
+		BaseURL string
+	}{
+		API:     api,
+		BaseURL: server.BaseURL(),
+	}
+
+	clientDir := filepath.Join(baseDir, "generated", "client")
+	if err := writeGoFile(filepath.Join(clientDir, "client.go"), tmpl, data, moduleName); err != nil {
+		return err
+	}
+
+	tmpl, err = template.New("operations").Funcs(templateFuncs).Parse(operationsTemplate)
+	if err != nil {
+		return err
+	}
+
+	operations, paginated, err := clientOperations(spec, heuristic)
+	if err != nil {
+		return err
+	}
+
+	opsData := struct {
+		Operations []clientOperation
+	}{
+		Operations: operations,
+	}
+
+	if err := writeGoFile(filepath.Join(clientDir, "operations.go"), tmpl, opsData, moduleName); err != nil {
+		return err
+	}
+
+	return generatePagination(clientDir, paginated, moduleName)
+}
+
+// clientOperations collects the client methods of every operation of spec,
+// and the iterators of the paginated ones
+func clientOperations(spec *models.OpenAPISpec, heuristic *PaginationHeuristic) ([]clientOperation, []paginatedOperation, error) {
+	var operations []clientOperation
+	var paginated []paginatedOperation
+	for _, entry := range sortedOperations(spec) {
+		clientOp, err := buildClientOperation(entry)
+		if err != nil {
+			return nil, nil, err
+		}
+		operations = append(operations, clientOp)
+
+		p := operationPagination(spec, entry, heuristic)
+		if p == nil {
+			continue
+		}
+		pageOp, err := buildPaginatedOperation(spec, entry, clientOp, p)
+		if err != nil {
+			if entry.Operation.Pagination != nil {
+				return nil, nil, err
+			}
+			// Operations the heuristic misjudged are left without an iterator
+			continue
+		}
+		paginated = append(paginated, pageOp)
+	}
+	return operations, paginated, nil
+}
+
+// buildClientOperation collects what the client method for entry needs
+func buildClientOperation(entry operationEntry) (clientOperation, error) {
+	op := entry.Operation
+	name := utils.ToGoIdentifier(op.OperationID)
+
+	comment := "calls " + entry.Method + " " + entry.Path
+	if op.Summary != "" {
+		comment = op.Summary
+	}
+
+	clientOp := clientOperation{
+		Name:      name,
+		Comment:   comment,
+		Method:    entry.Method,
+		Retryable: idempotentMethods[entry.Method],
+	}
+	if op.Retryable != nil {
+		clientOp.Retryable = *op.Retryable
+	}
+	if op.Timeout != "" {
+		timeout, err := time.ParseDuration(op.Timeout)
+		if err != nil || timeout <= 0 {
+			return clientOp, fmt.Errorf("invalid x-timeout %q for %s %s", op.Timeout, entry.Method, entry.Path)
+		}
+		clientOp.Timeout = durationExpr(timeout)
+	}
+
+	// Path parameters become arguments and are substituted into the path
+	pathVars := map[string]string{}
+	var pathArgs, pathNames []string
+	for _, param := range op.Parameters {
+		switch param.In {
+		case pathParameterType:
+			arg := clientParamName(param.Name)
+			pathArgs = append(pathArgs, arg+" "+modelType(param.Schema))
+			pathNames = append(pathNames, arg)
+			style, explode := paramStyle(param)
+			pathVars[param.Name] = "pathParam(" + utils.QuoteGoString(param.Name) + ", " +
+				utils.QuoteGoString(style) + ", " + strconv.FormatBool(explode) + ", " + arg + ")"
+		case "query", "header":
+			style, explode := paramStyle(param)
+			paramType := modelType(param.Schema)
+			nilable := strings.HasPrefix(paramType, "[]") || strings.HasPrefix(paramType, "map[") || paramType == "interface{}"
+			cp := clientParam{
+				Name:     param.Name,
+				Field:    utils.ToGoIdentifier(param.Name),
+				Type:     paramType,
+				Style:    style,
+				Explode:  explode,
+				Optional: !param.Required && !nilable,
+				Nilable:  nilable,
+			}
+			if param.In == "query" {
+				clientOp.QueryParams = append(clientOp.QueryParams, cp)
+			} else {
+				clientOp.HeaderParams = append(clientOp.HeaderParams, cp)
+			}
+		}
+	}
+	if len(pathArgs) > 0 {
+		clientOp.PathArgs = ", " + strings.Join(pathArgs, ", ")
+		clientOp.PathNames = ", " + strings.Join(pathNames, ", ")
+	}
+	if len(clientOp.QueryParams) > 0 || len(clientOp.HeaderParams) > 0 {
+		clientOp.ParamsType = name + "Params"
+	}
+	clientOp.PathExpr = pathExpr(entry.Path, pathVars)
+
+	if op.RequestBody != nil && len(op.RequestBody.Content) > 0 {
+		mediaTypes := make([]string, 0, len(op.RequestBody.Content))
+		for mediaType := range op.RequestBody.Content {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+		sort.Strings(mediaTypes)
+
+		clientOp.ContentType = mediaTypes[0]
+		clientOp.BodyKind = "raw"
+		clientOp.BodyType = "[]byte"
+		if schema := jsonSchema(op.RequestBody.Content); schema != nil {
+			for _, mediaType := range mediaTypes {
+				if isJSONMediaType(mediaType) {
+					clientOp.ContentType = mediaType
+					break
+				}
+			}
+			clientOp.BodyKind = "json"
+			clientOp.BodyType = modelType(*schema)
+		} else if content, ok := op.RequestBody.Content["application/x-www-form-urlencoded"]; ok {
+			clientOp.ContentType = "application/x-www-form-urlencoded"
+			clientOp.BodyKind = "form"
+			clientOp.BodyType = modelType(content.Schema)
+		}
+	}
+
+	if _, schema := successResponse(op); schema != nil {
+		clientOp.DecodeType = modelType(*schema)
+		clientOp.ReturnType = clientOp.DecodeType
+		if !strings.HasPrefix(clientOp.DecodeType, "[]") && !strings.HasPrefix(clientOp.DecodeType, "map[") {
+			clientOp.ReturnType = "*" + clientOp.DecodeType
+			clientOp.ReturnsPtr = true
+		}
+	}
+
+	clientOp.Errors, clientOp.ErrorDefault = clientErrorResponses(op)
+	return clientOp, nil
+}
+
+// durationExpr returns a Go expression for d in the largest whole unit
+func durationExpr(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d%u.unit == 0 {
+			return strconv.FormatInt(int64(d/u.unit), 10) + " * " + u.name
+		}
+	}
+	return "time.Duration(" + strconv.FormatInt(int64(d), 10) + ")"
+}
+
+// clientErrorResponses returns the error body types op declares, most
+// specific status first, and the type of its default response
+func clientErrorResponses(op models.Operation) ([]clientErrorResponse, string) {
+	var exact, ranges []clientErrorResponse
+	fallback := ""
+
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		schema := jsonSchema(op.Responses[code].Content)
+		if schema == nil || strings.HasPrefix(code, "2") {
+			continue
+		}
+		errType := modelType(*schema)
+		upper := strings.ToUpper(code)
+		switch {
+		case code == "default":
+			fallback = errType
+		case len(upper) == 3 && strings.HasSuffix(upper, "XX"):
+			low := upper[:1] + "00"
+			high := upper[:1] + "99"
+			ranges = append(ranges, clientErrorResponse{Cond: "status >= " + low + " && status <= " + high, Type: errType})
+		case isStatusCode(code):
+			exact = append(exact, clientErrorResponse{Cond: "status == " + code, Type: errType})
+		}
+	}
+
+	return append(exact, ranges...), fallback
+}
+
+// isStatusCode reports whether code is a three digit HTTP status code
+func isStatusCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// paramStyle returns the serialization style of param with the OpenAPI defaults applied
+func paramStyle(param models.Parameter) (string, bool) {
+	style := param.Style
+	if style == "" {
+		style = "simple"
+		if param.In == "query" || param.In == "cookie" {
+			style = "form"
+		}
+	}
+	explode := style == "form"
+	if param.Explode != nil {
+		explode = *param.Explode
+	}
+	return style, explode
+}
+
+// clientParamName converts a path parameter name to a client method argument name
+func clientParamName(name string) string {
+	ident := goParamName(name)
+	if clientReservedNames[ident] {
+		return ident + "Param"
+	}
+	return ident
+}
+
+// pathExpr returns a Go expression building path, with each {name} segment
+// replaced by the expression in vars
+func pathExpr(path string, vars map[string]string) string {
+	var parts []string
+	for path != "" {
+		start := strings.Index(path, "{")
+		end := strings.Index(path, "}")
+		if start < 0 || end < start {
+			parts = append(parts, utils.QuoteGoString(path))
+			break
+		}
+		if start > 0 {
+			parts = append(parts, utils.QuoteGoString(path[:start]))
+		}
+		name := path[start+1 : end]
+		if expr, ok := vars[name]; ok {
+			parts = append(parts, expr)
+		} else {
+			parts = append(parts, utils.QuoteGoString(path[start:end+1]))
+		}
+		path = path[end+1:]
+	}
+	if len(parts) == 0 {
+		return `""`
+	}
+	return strings.Join(parts, " + ")
+}
@@ -0,0 +1,429 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/shubhamku044/gopenapi/internal/examples"
+	"github.com/shubhamku044/gopenapi/internal/models"
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+)
+
+// contractCase is an example request to one operation, as the Case literal
+// generated/contract sends it
+type contractCase struct {
+	Operation   string
+	Method      string
+	Path        string
+	Query       string
+	Header      string // Go expression of the http.Header, if any
+	ContentType string
+	Body        string // Go string literal
+	Secured     bool
+	Skip        string
+}
+
+// GenerateContract generates generated/contract, which sends an example
+// request to every operation of a running server and validates the responses
+// against the spec
+func GenerateContract(spec *models.OpenAPISpec, baseDir string, moduleName string) error {
+	contractTemplate := `// Code generated by gopenapi. DO NOT EDIT.
+
+// Package contract checks an implementation of the API against the spec.
+// Run sends an example request to every operation and fails the test on
+// responses with a status code the operation doesn't declare or a body that
+// doesn't match the declared schema.
+package contract
+
+// Case is the example request Run sends to an operation
+type Case struct {
+	Operation   string      // handler name, such as GetUser
+	Method      string      // upper case
+	Path        string      // with example path parameters, without the base path
+	Query       string      // encoded required query parameters
+	Header      http.Header // required header and cookie parameters
+	ContentType string      // of Body, empty without one
+	Body        string
+	Secured     bool   // whether the spec secures the operation
+	Skip        string // why no valid request could be built, if none could
+}
+
+// Cases are the requests Run sends, one per operation
+var Cases = []Case{
+{{- range .Cases}}
+	{
+		Operation: {{goString .Operation}},
+		Method:    {{goString .Method}},
+		Path:      {{goString .Path}},
+{{- if .Query}}
+		Query:     {{goString .Query}},
+{{- end}}
+{{- if .Header}}
+		Header:    {{.Header}},
+{{- end}}
+{{- if .ContentType}}
+		ContentType: {{goString .ContentType}},
+		Body:        {{.Body}},
+{{- end}}
+{{- if .Secured}}
+		Secured:   true,
+{{- end}}
+{{- if .Skip}}
+		Skip:      {{goString .Skip}},
+{{- end}}
+	},
+{{- end}}
+}
+
+// Server serves the API, as the generated server.Server does
+type Server interface {
+	Serve(l net.Listener) error
+	Shutdown(ctx context.Context) error
+}
+
+// Run serves the API with srv on a loopback port and sends every case to it,
+// each in a subtest named after its operation. prepare, if not nil, is called
+// with every request before it is sent, to add credentials for example.
+// Operations the spec secures are skipped without prepare, since the server
+// needs an authenticator and the requests credentials to reach the handlers.
+// Requests go to the spec's BasePath; prepare can change their URL if srv was
+// given another with WithBasePath.
+func Run(t *testing.T, srv Server, prepare func(r *http.Request)) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening on a loopback port: %v", err)
+	}
+	go func() {
+		_ = srv.Serve(l) // http.ErrServerClosed after Shutdown
+	}()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	})
+
+	baseURL := "http://" + l.Addr().String() + server.BasePath
+	validator := server.Validator()
+	client := &http.Client{Timeout: 30 * time.Second}
+	for _, c := range Cases {
+		t.Run(c.Operation, func(t *testing.T) {
+			if c.Skip != "" {
+				t.Skip(c.Skip)
+			}
+			if c.Secured && prepare == nil {
+				t.Skip("the operation is secured; give the server an authenticator and pass Run a prepare function adding credentials to the requests")
+			}
+			c.check(t, client, validator, baseURL, prepare)
+		})
+	}
+}
+
+// check sends the request of c to the server at baseURL and validates the
+// response against the spec
+func (c Case) check(t *testing.T, client *http.Client, validator *validate.Validator, baseURL string, prepare func(r *http.Request)) {
+	t.Helper()
+
+	// The validator knows operations by their path in the spec, without the
+	// base path, and reads the body of the requests it validates
+	if op, _ := validator.Match(c.request("")); op == nil || op.Name != c.Operation {
+		t.Skipf("%s %s is served by another operation; add an example to the spec", c.Method, c.Path)
+	}
+	if err := validator.ValidateRequest(c.request("")); err != nil {
+		t.Skipf("the example request doesn't match the spec; add an example to the spec: %v", err)
+	}
+
+	r := c.request(baseURL)
+	if prepare != nil {
+		prepare(r)
+	}
+	resp, err := client.Do(r)
+	if err != nil {
+		t.Fatalf("%s %s: %v", c.Method, c.Path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("%s %s: reading the response: %v", c.Method, c.Path, err)
+	}
+
+	if err := validator.ValidateResponse(c.request(""), resp.StatusCode, resp.Header, body); err != nil {
+		t.Errorf("%s %s: %v\n%s", c.Method, c.Path, err, body)
+	}
+}
+
+// request returns the request of c to the server at baseURL
+func (c Case) request(baseURL string) *http.Request {
+	target := baseURL + c.Path
+	if c.Query != "" {
+		target += "?" + c.Query
+	}
+	r := httptest.NewRequest(c.Method, target, strings.NewReader(c.Body))
+	r.RequestURI = "" // set by NewRequest for servers, refused by clients
+	for name, values := range c.Header {
+		r.Header[name] = values
+	}
+	if c.ContentType != "" {
+		r.Header.Set("Content-Type", c.ContentType)
+	}
+	return r
+}
+`
+
+	tmpl, err := template.New("contract").Funcs(templateFuncs).Parse(contractTemplate)
+	if err != nil {
+		return err
+	}
+
+	gen := examples.New(spec.Components.Schemas, exampleSeed)
+	var data struct {
+		Cases []contractCase
+	}
+	for _, entry := range sortedOperations(spec) {
+		c := buildContractCase(gen, entry)
+		c.Secured = len(effectiveSecurity(spec, entry.Operation)) > 0
+		data.Cases = append(data.Cases, c)
+	}
+
+	contractDir := filepath.Join(baseDir, "generated", "contract")
+	if err := os.MkdirAll(contractDir, 0755); err != nil {
+		return err
+	}
+	return writeGoFile(filepath.Join(contractDir, "contract.go"), tmpl, data, moduleName)
+}
+
+// buildContractCase builds the example request to an operation from the
+// examples of its required parameters and request body
+func buildContractCase(gen *examples.Generator, entry operationEntry) contractCase {
+	op := entry.Operation
+	c := contractCase{
+		Operation: utils.ToGoIdentifier(op.OperationID),
+		Method:    entry.Method,
+		Path:      entry.Path,
+	}
+
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []string
+	for _, param := range op.Parameters {
+		if !param.Required && param.In != pathParameterType {
+			continue
+		}
+		style, explode := paramStyle(param)
+		parts, ok := paramParts(gen.Named(param.Name, param.Schema))
+		if !ok {
+			c.Skip = fmt.Sprintf("no example of the %s parameter %s can be sent", param.In, param.Name)
+			return c
+		}
+
+		switch param.In {
+		case pathParameterType:
+			for i := range parts {
+				parts[i] = url.PathEscape(parts[i])
+			}
+			value := strings.Join(parts, ",")
+			switch {
+			case style == "label" && explode:
+				value = "." + strings.Join(parts, ".")
+			case style == "label":
+				value = "." + value
+			case style == "matrix" && explode:
+				value = ";" + param.Name + "=" + strings.Join(parts, ";"+param.Name+"=")
+			case style == "matrix":
+				value = ";" + param.Name + "=" + value
+			}
+			c.Path = strings.ReplaceAll(c.Path, "{"+param.Name+"}", value)
+		case "query":
+			switch {
+			case style == "spaceDelimited":
+				query.Add(param.Name, strings.Join(parts, " "))
+			case style == "pipeDelimited":
+				query.Add(param.Name, strings.Join(parts, "|"))
+			case explode:
+				query[param.Name] = append(query[param.Name], parts...)
+			default:
+				query.Add(param.Name, strings.Join(parts, ","))
+			}
+		case "header":
+			header.Add(param.Name, strings.Join(parts, ","))
+		case "cookie":
+			cookies = append(cookies, param.Name+"="+url.QueryEscape(strings.Join(parts, ",")))
+		}
+	}
+	c.Query = query.Encode()
+	if len(cookies) > 0 {
+		header.Set("Cookie", strings.Join(cookies, "; "))
+	}
+	c.Header = headerLiteral(header)
+
+	if op.RequestBody != nil && len(op.RequestBody.Content) > 0 {
+		mediaType, ok := jsonMediaType(op.RequestBody.Content)
+		if !ok {
+			if op.RequestBody.Required {
+				c.Skip = "the request body has no JSON media type to send an example of"
+			}
+			return c
+		}
+		body, err := json.Marshal(gen.MediaType(op.RequestBody.Content[mediaType]))
+		if err != nil || string(body) == "null" {
+			if op.RequestBody.Required {
+				c.Skip = "no example of the request body can be sent"
+			}
+			return c
+		}
+		c.ContentType = mediaType
+		c.Body = rawStringLiteral(string(body))
+	}
+	return c
+}
+
+// paramParts returns the example value of a parameter as the strings it is
+// serialized from: the value itself, or the items of an array. ok is false
+// for objects and other values no example can be sent for.
+func paramParts(value interface{}) (parts []string, ok bool) {
+	if items, isArray := value.([]interface{}); isArray {
+		for _, item := range items {
+			part, ok := paramScalar(item)
+			if !ok {
+				return nil, false
+			}
+			parts = append(parts, part)
+		}
+		return parts, true
+	}
+	part, ok := paramScalar(value)
+	if !ok {
+		return nil, false
+	}
+	return []string{part}, true
+}
+
+// paramScalar formats a string, number or boolean parameter value
+func paramScalar(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int, int64, bool:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
+
+// headerLiteral returns the Go expression of header, or "" if it is empty
+func headerLiteral(header http.Header) string {
+	if len(header) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]string, len(names))
+	for i, name := range names {
+		values := make([]string, len(header[name]))
+		for j, value := range header[name] {
+			values[j] = strconv.Quote(value)
+		}
+		fields[i] = strconv.Quote(name) + ": {" + strings.Join(values, ", ") + "}"
+	}
+	return "http.Header{" + strings.Join(fields, ", ") + "}"
+}
+
+// GenerateContractTestIfNotExists generates handlers/contract_test.go, which
+// runs the contract checks against the user's handlers, only if it doesn't
+// exist. It is left out if the handlers package doesn't declare
+// NewAPIHandlers to build them with, or the server, from a custom backend,
+// has no Serve method for contract.Run.
+func GenerateContractTestIfNotExists(baseDir string, moduleName string) error {
+	testPath := filepath.Join(baseDir, "handlers", "contract_test.go")
+	if _, err := os.Stat(testPath); !os.IsNotExist(err) {
+		return nil // Already exists, don't overwrite
+	}
+	constructor, pkg := findFunc(filepath.Join(baseDir, "handlers"), "", "NewAPIHandlers")
+	if constructor == nil || constructor.Type.Params.NumFields() > 0 {
+		return nil
+	}
+	if serve, _ := findFunc(filepath.Join(baseDir, "generated", "server"), "Server", "Serve"); serve == nil {
+		return nil
+	}
+
+	contractTestTemplate := `package {{.Package}}
+
+// TestContract sends an example request to every operation and checks the
+// responses against the spec. Give the server the options main.go gives it,
+// such as an authenticator, and add credentials to the requests with the
+// function passed to contract.Run.
+func TestContract(t *testing.T) {
+	contract.Run(t, server.NewServer(NewAPIHandlers()), nil)
+}
+`
+
+	tmpl, err := template.New("contractTest").Parse(contractTestTemplate)
+	if err != nil {
+		return err
+	}
+	data := struct {
+		Package string
+	}{
+		Package: pkg,
+	}
+	return writeGoFile(testPath, tmpl, data, moduleName)
+}
+
+// findFunc returns the declaration of the function name in the Go package in
+// dir, or of the method name of recv if recv isn't empty, and the package's
+// name
+func findFunc(dir string, recv string, name string) (*ast.FuncDecl, string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, ""
+	}
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		filename := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(filename, ".go") || strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, filename), nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Name.Name != name || (fn.Recv == nil) != (recv == "") {
+				continue
+			}
+			if fn.Recv == nil || receiverTypeName(fn.Recv.List[0].Type) == recv {
+				return fn, file.Name.Name
+			}
+		}
+	}
+	return nil, ""
+}
+
+// receiverTypeName returns the name of the type of a method receiver
+func receiverTypeName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+)
+
+// templateFuncs escapes spec-derived values for the context a template puts them in
+var templateFuncs = template.FuncMap{
+	"comment":    utils.EscapeComment,
+	"goString":   utils.QuoteGoString,
+	"goIdent":    utils.SanitizeIdentifier,
+	"markdown":   utils.EscapeMarkdown,
+	"codeSpan":   codeSpan,
+	"shellQuote": shellQuote,
+	"jsonTag":    jsonTag,
+}
+
+// reservedParamNames are identifiers generated code already uses in the
+// scopes where path parameters become variables
+var reservedParamNames = map[string]bool{
+	"authenticate": true,
+	"c":            true,
+	"h":            true,
+	"ok":           true,
+	"r":            true,
+	"s":            true,
+	"w":            true,
+}
+
+// goParamName converts a spec parameter name to a Go variable name that
+// shadows neither the identifiers nor the packages generated code uses. Names
+// without a valid character, which sanitize to the blank identifier, become
+// param.
+func goParamName(name string) string {
+	ident := utils.SanitizeIdentifier(name)
+	if ident == "_" {
+		return "param"
+	}
+	// Any module name makes knownImports include the generated packages
+	if _, imported := knownImports("module")[ident]; imported || reservedParamNames[ident] {
+		return ident + "Param"
+	}
+	return ident
+}
+
+// goTypeOf returns the Go type for schema with referenced model names made
+// valid identifiers
+func goTypeOf(schema models.Schema) string {
+	if schema.Ref != "" {
+		return utils.SanitizeIdentifier(utils.GetGoType(schema))
+	}
+	if schema.Type == "array" && schema.Items != nil {
+		return "[]" + goTypeOf(*schema.Items)
+	}
+	return utils.GetGoType(schema)
+}
+
+// jsonTag returns a struct tag literal mapping a field to the JSON property name
+func jsonTag(name string) string {
+	tag := "json:" + strconv.Quote(name)
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+// codeSpan renders s as an inline Markdown code span, using a backtick fence
+// longer than any backtick run inside s
+func codeSpan(s string) string {
+	s = utils.EscapeComment(s)
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", longest+1)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
+
+// shellQuote quotes s as a single POSIX shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(utils.EscapeComment(s), "'", `'\''`) + "'"
+}
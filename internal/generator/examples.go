@@ -0,0 +1,193 @@
+package generator
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shubhamku044/gopenapi/internal/examples"
+	"github.com/shubhamku044/gopenapi/internal/models"
+)
+
+// exampleSeed seeds the examples synthesized for generated files, so that
+// regenerating from an unchanged spec leaves them unchanged
+const exampleSeed = 0
+
+// HandlerExample is the example response a generated handler stub writes
+type HandlerExample struct {
+	Status string // Go expression of the status code, such as http.StatusOK
+	Body   string // Go expression of the JSON body, empty for responses without one
+	// Synthesized is set when the spec has no example of the body, so Body
+	// was synthesized from the response schema
+	Synthesized bool
+}
+
+// Comment returns the comment introducing Body in a handler stub
+func (e *HandlerExample) Comment() string {
+	if e.Synthesized {
+		return "// Example response synthesized from the response schema"
+	}
+	return "// Example response from the spec"
+}
+
+// statusConstants are the net/http constants of the status codes handler
+// stubs respond with
+var statusConstants = map[int]string{
+	200: "http.StatusOK",
+	201: "http.StatusCreated",
+	202: "http.StatusAccepted",
+	203: "http.StatusNonAuthoritativeInfo",
+	204: "http.StatusNoContent",
+	205: "http.StatusResetContent",
+	206: "http.StatusPartialContent",
+	207: "http.StatusMultiStatus",
+	208: "http.StatusAlreadyReported",
+	226: "http.StatusIMUsed",
+	400: "http.StatusBadRequest",
+	401: "http.StatusUnauthorized",
+	403: "http.StatusForbidden",
+	404: "http.StatusNotFound",
+	409: "http.StatusConflict",
+	422: "http.StatusUnprocessableEntity",
+	500: "http.StatusInternalServerError",
+	501: "http.StatusNotImplemented",
+	502: "http.StatusBadGateway",
+	503: "http.StatusServiceUnavailable",
+}
+
+// operationExample returns the status code and example body of the first
+// 2xx response of op, from the spec's examples or synthesized from its
+// schema. body is nil for responses without content. ok is false if op
+// declares no 2xx response, or its first one has no JSON body to show.
+func operationExample(gen *examples.Generator, op models.Operation) (status int, body interface{}, ok bool) {
+	code, ok := firstResponseCode(op, "2")
+	if !ok {
+		return 0, nil, false
+	}
+	return responseExample(gen, code, op.Responses[code])
+}
+
+// firstResponseCode returns the first status code or range, such as 404 or
+// 4XX, op declares starting with one of the digits in classes
+func firstResponseCode(op models.Operation, classes string) (string, bool) {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		if len(code) == 3 && strings.ContainsRune(classes, rune(code[0])) {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// responseExample returns the status code and example body of the response
+// op declares for code, as operationExample does
+func responseExample(gen *examples.Generator, code string, response models.Response) (status int, body interface{}, ok bool) {
+	status, err := strconv.Atoi(code)
+	if err != nil {
+		status = int(code[0]-'0') * 100 // 2XX
+	}
+	if len(response.Content) == 0 {
+		return status, nil, true
+	}
+	mediaType, ok := jsonMediaType(response.Content)
+	if !ok {
+		return 0, nil, false
+	}
+	body = gen.MediaType(response.Content[mediaType])
+	return status, body, body != nil
+}
+
+// requestExample returns the example JSON body of op's request, if it has one
+func requestExample(gen *examples.Generator, op models.Operation) (interface{}, bool) {
+	if op.RequestBody == nil {
+		return nil, false
+	}
+	mediaType, ok := jsonMediaType(op.RequestBody.Content)
+	if !ok {
+		return nil, false
+	}
+	body := gen.MediaType(op.RequestBody.Content[mediaType])
+	return body, body != nil
+}
+
+// jsonMediaType returns the first JSON media type of content
+func jsonMediaType(content map[string]models.MediaType) (string, bool) {
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+
+	for _, mediaType := range mediaTypes {
+		if isJSONMediaType(mediaType) {
+			return mediaType, true
+		}
+	}
+	return "", false
+}
+
+// handlerExample returns the example response the handler stub of op
+// writes: its first 2xx response or, for operations that declare none, its
+// first response, so that the stub answers with a status op declares. It is
+// nil if op has none to write.
+func handlerExample(gen *examples.Generator, op models.Operation) *HandlerExample {
+	code, ok := firstResponseCode(op, "2")
+	if !ok {
+		code, ok = firstResponseCode(op, "12345")
+	}
+	if !ok {
+		return nil
+	}
+	status, body, ok := responseExample(gen, code, op.Responses[code])
+	if !ok {
+		return nil
+	}
+
+	example := &HandlerExample{Status: strconv.Itoa(status)}
+	if constant, ok := statusConstants[status]; ok {
+		example.Status = constant
+	}
+	if body == nil {
+		return example
+	}
+
+	// Indented to line up with the statement in the handler body
+	data, err := json.MarshalIndent(body, "\t", "\t")
+	if err != nil {
+		return nil
+	}
+	example.Body = "json.RawMessage(" + rawStringLiteral(string(data)) + ")"
+	content := op.Responses[code].Content
+	mediaType, _ := jsonMediaType(content)
+	example.Synthesized = !specExample(content[mediaType])
+	return example
+}
+
+// specExample reports whether the spec gives an example of content, rather
+// than leaving it to be synthesized from the schema
+func specExample(content models.MediaType) bool {
+	if content.Example != nil || content.Schema.Example != nil {
+		return true
+	}
+	for _, example := range content.Examples {
+		if example.Value != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// rawStringLiteral returns s as a raw Go string literal, or as an
+// interpreted one if s contains a backtick
+func rawStringLiteral(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
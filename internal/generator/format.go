@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"go/scanner"
+	"strings"
+)
+
+// contextLines is the number of lines shown around a syntax error in generated code
+const contextLines = 3
+
+// formatGoSource formats generated Go source like gofmt
+func formatGoSource(filename string, src []byte) ([]byte, error) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, templateError(filename, src, err)
+	}
+	return formatted, nil
+}
+
+// templateError describes a syntax error in generated source, quoting the
+// offending lines so the broken template can be located
+func templateError(filename string, src []byte, err error) error {
+	var list scanner.ErrorList
+	if !errors.As(err, &list) || len(list) == 0 {
+		return fmt.Errorf("generated %s is not valid Go: %w", filename, err)
+	}
+
+	line := list[0].Pos.Line
+	lines := strings.Split(string(src), "\n")
+	first := max(line-contextLines, 1)
+	last := min(line+contextLines, len(lines))
+
+	var b strings.Builder
+	for i := first; i <= last; i++ {
+		marker := "  "
+		if i == line {
+			marker = "> "
+		}
+		fmt.Fprintf(&b, "%s%4d | %s\n", marker, i, lines[i-1])
+	}
+
+	return fmt.Errorf("generated %s is not valid Go (template error): %w\n%s", filename, list[0], b.String())
+}
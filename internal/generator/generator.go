@@ -1,12 +1,12 @@
 package generator
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
+	"github.com/shubhamku044/gopenapi/internal/examples"
 	"github.com/shubhamku044/gopenapi/internal/models"
 	"github.com/shubhamku044/gopenapi/pkg/utils"
 )
@@ -16,6 +16,25 @@ type Config struct {
 	OutputDir   string
 	PackageName string
 	ModuleName  string
+	// Framework names the backend the server is generated for: FrameworkGin
+	// (the default), FrameworkNetHTTP, FrameworkChi, FrameworkEcho or a
+	// backend added with RegisterBackend
+	Framework string
+	// Pagination recognizes paginated operations without an x-pagination
+	// extension; nil uses DefaultPaginationHeuristic
+	Pagination *PaginationHeuristic
+	// SplitByTag generates one handler interface per tag, such as
+	// UsersHandlers, instead of a single APIHandlers interface, with the
+	// handlers of each tag in their own file under handlers/
+	SplitByTag bool
+	// Server selects the entry of the spec's servers the code is generated
+	// for, by its index, URL or description. The first is used if empty.
+	// The server mounts the API at the path of its URL and the client calls
+	// it by default.
+	Server string
+	// ServerVariables sets the variables in the selected server's URL,
+	// overriding their defaults
+	ServerVariables map[string]string
 }
 
 // GenerateCode generates all code from an OpenAPI spec with complete separation
@@ -24,14 +43,25 @@ func GenerateCode(spec *models.OpenAPISpec, config Config) error {
 		config.ModuleName = config.PackageName
 	}
 
+	backend, err := LookupBackend(config.Framework)
+	if err != nil {
+		return err
+	}
+	framework := backend.Name
+
+	server, err := resolveServer(spec, config.Server, config.ServerVariables)
+	if err != nil {
+		return err
+	}
+
 	// Create directory structure with separation
-	err := createProjectStructure(config.OutputDir)
+	err = createProjectStructure(config.OutputDir)
 	if err != nil {
 		return err
 	}
 
 	// Generate go.mod ONLY if it doesn't exist (user's project)
-	err = GenerateGoModIfNotExists(config.OutputDir, config.ModuleName)
+	err = GenerateGoModIfNotExists(config.OutputDir, config.ModuleName, framework)
 	if err != nil {
 		return err
 	}
@@ -43,7 +73,7 @@ func GenerateCode(spec *models.OpenAPISpec, config Config) error {
 	}
 
 	// Always regenerate the generated/ directory (safe to overwrite)
-	err = GenerateInterfaces(spec, config.OutputDir, config.ModuleName)
+	err = generateInterfaces(spec, config.OutputDir, config.ModuleName, framework, config.SplitByTag)
 	if err != nil {
 		return err
 	}
@@ -53,19 +83,59 @@ func GenerateCode(spec *models.OpenAPISpec, config Config) error {
 		return err
 	}
 
-	err = GenerateRouter(spec, config.OutputDir, config.ModuleName)
+	err = generateRouter(spec, config.OutputDir, config.ModuleName, framework, server)
+	if err != nil {
+		return err
+	}
+
+	err = GenerateSpec(spec, config.OutputDir, config.ModuleName)
+	if err != nil {
+		return err
+	}
+
+	err = GenerateValidation(spec, config.OutputDir, config.ModuleName)
+	if err != nil {
+		return err
+	}
+
+	err = GenerateSecurity(spec, config.OutputDir, config.ModuleName)
+	if err != nil {
+		return err
+	}
+
+	pagination := config.Pagination
+	if pagination == nil {
+		pagination = DefaultPaginationHeuristic()
+	}
+	err = generateClient(spec, config.OutputDir, config.ModuleName, pagination, server)
+	if err != nil {
+		return err
+	}
+
+	err = GenerateAPITest(spec, config.OutputDir, config.ModuleName, framework, pagination)
+	if err != nil {
+		return err
+	}
+
+	err = GenerateContract(spec, config.OutputDir, config.ModuleName)
 	if err != nil {
 		return err
 	}
 
 	// Generate handler templates ONLY if they don't exist
-	err = GenerateHandlerTemplates(spec, config.OutputDir, config.ModuleName)
+	err = generateHandlerTemplates(spec, config.OutputDir, config.ModuleName, framework, config.SplitByTag)
+	if err != nil {
+		return err
+	}
+
+	// Generate the contract test of the handlers ONLY if it doesn't exist
+	err = GenerateContractTestIfNotExists(config.OutputDir, config.ModuleName)
 	if err != nil {
 		return err
 	}
 
 	// Always regenerate documentation
-	err = GenerateReadme(spec, config.OutputDir, config.PackageName)
+	err = generateReadme(spec, config.OutputDir, config.PackageName, framework, config.SplitByTag)
 	if err != nil {
 		return err
 	}
@@ -78,9 +148,14 @@ func createProjectStructure(baseDir string) error {
 	dirs := []string{
 		baseDir,
 		filepath.Join(baseDir, "handlers"), // User implementations
-		filepath.Join(baseDir, "generated", "api"),    // Generated interfaces
-		filepath.Join(baseDir, "generated", "models"), // Generated models
-		filepath.Join(baseDir, "generated", "server"), // Generated server
+		filepath.Join(baseDir, "generated", "api"),      // Generated interfaces
+		filepath.Join(baseDir, "generated", "models"),   // Generated models
+		filepath.Join(baseDir, "generated", "server"),   // Generated server
+		filepath.Join(baseDir, "generated", "client"),   // Generated client
+		filepath.Join(baseDir, "generated", "validate"), // Generated request validation
+		filepath.Join(baseDir, "generated", "auth"),     // Generated authentication
+		filepath.Join(baseDir, "generated", "contract"), // Generated contract tests
+		filepath.Join(baseDir, "generated", "apitest"),  // Generated fakes for unit tests
 	}
 
 	for _, dir := range dirs {
@@ -93,24 +168,23 @@ func createProjectStructure(baseDir string) error {
 	return nil
 }
 
-// GenerateGoModIfNotExists generates go.mod only if it doesn't exist
-func GenerateGoModIfNotExists(outputDir, moduleName string) error {
+// GenerateGoModIfNotExists generates go.mod only if it doesn't exist. The go
+// directive of an existing go.mod is raised to the version the framework's
+// generated code needs.
+func GenerateGoModIfNotExists(outputDir, moduleName, framework string) error {
 	goModPath := filepath.Join(outputDir, "go.mod")
 
+	backend, err := LookupBackend(framework)
+	if err != nil {
+		return err
+	}
+
 	// Check if go.mod already exists
 	if _, err := os.Stat(goModPath); err == nil {
-		// go.mod already exists, skip generation
-		return nil
+		return backend.raiseGoVersion(goModPath)
 	}
 
-	goModContent := fmt.Sprintf(`module %s
-
-go 1.21
-
-require (
-	github.com/gin-gonic/gin v1.10.0
-)
-`, moduleName)
+	goModContent := backend.goMod(moduleName)
 
 	return os.WriteFile(goModPath, []byte(goModContent), 0600)
 }
@@ -140,17 +214,17 @@ import (
 func main() {
 	// Create handler implementation
 	apiHandlers := handlers.NewAPIHandlers()
-	
+
 	// Create server with handlers
 	srv := server.NewServer(apiHandlers)
-	
+
 	// Start server in a goroutine
 	go func() {
 		port := os.Getenv("PORT")
 		if port == "" {
 			port = "8080"
 		}
-		
+
 		log.Printf("Server starting on port %s", port)
 		if err := srv.Start(":" + port); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Server failed to start: %v", err)
@@ -166,7 +240,7 @@ func main() {
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
-	
+
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal("Server forced to shutdown:", err)
 	}
@@ -186,30 +260,88 @@ func main() {
 		ModuleName: moduleName,
 	}
 
-	f, err := os.Create(mainPath)
+	return writeGoFile(mainPath, tmpl, data, moduleName)
+}
+
+// GenerateInterfaces generates the API interfaces in generated/api/
+func GenerateInterfaces(spec *models.OpenAPISpec, baseDir string, moduleName string, framework string) error {
+	return generateInterfaces(spec, baseDir, moduleName, framework, false)
+}
+
+// generateInterfaces generates the API interfaces in generated/api/. With
+// splitByTag, APIHandlers embeds one interface per tag.
+func generateInterfaces(spec *models.OpenAPISpec, baseDir string, moduleName string, framework string, splitByTag bool) error {
+	backend, err := LookupBackend(framework)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-
-	return tmpl.Execute(f, data)
-}
 
-// GenerateInterfaces generates the API interfaces in generated/api/
-func GenerateInterfaces(spec *models.OpenAPISpec, baseDir string, moduleName string) error {
 	interfaceTemplate := `// Code generated by gopenapi. DO NOT EDIT.
 
 package api
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
+{{- range .Imports}}
+	{{goString .}}
+{{- end}}
+)
+
+{{- if .Groups}}
+{{- range .Groups}}
+
+// {{.Name}} {{comment .Comment}}
+type {{.Name}} interface {
+{{- range $i, $m := .Methods}}
+{{- if $i}}
+{{end}}
+	// {{.HandlerName}} {{comment .Comment}}
+	{{.HandlerName}}({{$.ContextParams}}{{.Parameters}}){{if $.HandlerResult}} {{$.HandlerResult}}{{end}}
+{{- end}}
+}
+{{- end}}
+
+// APIHandlers defines the interface that users must implement, made up of
+// one interface per tag
+type APIHandlers interface {
+{{- range .Groups}}
+	{{.Name}}
+{{- end}}
+}
+
+// NewHandlers implements APIHandlers with a separate implementation of each
+// tag's interface. A tag added to the spec adds a parameter, so callers that
+// don't implement it yet fail to compile.
+func NewHandlers(
+{{- range .Groups}}
+	{{.ParamName}} {{.Name}},
+{{- end}}
+) APIHandlers {
+	return handlers{
+{{- range .Groups}}
+		{{.Name}}: {{.ParamName}},
+{{- end}}
+	}
+}
+
+// handlers combines the implementations passed to NewHandlers
+type handlers struct {
+{{- range .Groups}}
+	{{.Name}}
+{{- end}}
+}
+{{- else}}
 
 // APIHandlers defines the interface that users must implement
 type APIHandlers interface {
-{{range .Methods}}
-	// {{.HandlerName}} {{.Comment}}
-	{{.HandlerName}}(c *gin.Context{{.Parameters}})
+{{- range $i, $m := .Methods}}
+{{- if $i}}
 {{end}}
+	// {{.HandlerName}} {{comment .Comment}}
+	{{.HandlerName}}({{$.ContextParams}}{{.Parameters}}){{if $.HandlerResult}} {{$.HandlerResult}}{{end}}
+{{- end}}
 }
+{{- end}}
 
 // APIMethod represents an API endpoint
 type APIMethod struct {
@@ -221,34 +353,40 @@ type APIMethod struct {
 // GetAPIMethods returns all API methods for documentation/routing
 func GetAPIMethods() []APIMethod {
 	return []APIMethod{
-{{range .Methods}}
+{{- range .Methods}}
 		{
-			Method:      "{{.Method}}",
-			Path:        "{{.Path}}",
-			HandlerName: "{{.HandlerName}}",
+			Method:      {{goString .Method}},
+			Path:        {{goString .Path}},
+			HandlerName: {{goString .HandlerName}},
 		},
-{{end}}
+{{- end}}
 	}
 }
 `
 
-	tmpl, err := template.New("interface").Parse(interfaceTemplate)
+	tmpl, err := template.New("interface").Funcs(templateFuncs).Parse(interfaceTemplate)
 	if err != nil {
 		return err
 	}
 
-	// Generate methods from OpenAPI spec
-	var methods []struct {
+	type interfaceMethod struct {
 		Method      string
 		Path        string
 		HandlerName string
 		Comment     string
 		Parameters  string
 	}
+	type interfaceGroup struct {
+		tagGroup
+		Methods []interfaceMethod
+	}
+
+	// Generate methods from OpenAPI spec
+	var methods []interfaceMethod
 
 	for path, operations := range spec.Paths {
 		for method, op := range operations {
-			handlerName := utils.ToCamelCase(op.OperationID)
+			handlerName := utils.ToGoIdentifier(op.OperationID)
 
 			// Build comment
 			comment := "handles " + strings.ToUpper(method) + " " + path
@@ -260,8 +398,7 @@ func GetAPIMethods() []APIMethod {
 			var params []string
 			for _, param := range op.Parameters {
 				if param.In == pathParameterType {
-					paramType := utils.GetGoType(param.Schema)
-					params = append(params, param.Name+" "+paramType)
+					params = append(params, goParamName(param.Name)+" "+goTypeOf(param.Schema))
 				}
 			}
 
@@ -270,13 +407,7 @@ func GetAPIMethods() []APIMethod {
 				paramStr = ", " + strings.Join(params, ", ")
 			}
 
-			methods = append(methods, struct {
-				Method      string
-				Path        string
-				HandlerName string
-				Comment     string
-				Parameters  string
-			}{
+			methods = append(methods, interfaceMethod{
 				Method:      strings.ToUpper(method),
 				Path:        path,
 				HandlerName: handlerName,
@@ -286,264 +417,150 @@ func GetAPIMethods() []APIMethod {
 		}
 	}
 
-	data := struct {
-		Methods []struct {
-			Method      string
-			Path        string
-			HandlerName string
-			Comment     string
-			Parameters  string
+	var groups []interfaceGroup
+	if splitByTag {
+		byName := map[string]interfaceMethod{}
+		for _, method := range methods {
+			byName[method.HandlerName] = method
+		}
+		for _, group := range tagGroups(spec) {
+			g := interfaceGroup{tagGroup: group}
+			for _, name := range group.Handlers {
+				g.Methods = append(g.Methods, byName[name])
+			}
+			groups = append(groups, g)
 		}
+	}
+
+	data := struct {
+		ContextParams string
+		HandlerResult string
+		Imports       []string
+		Methods       []interfaceMethod
+		Groups        []interfaceGroup
 	}{
-		Methods: methods,
+		ContextParams: backend.ContextParams,
+		HandlerResult: backend.HandlerResult,
+		Imports:       backend.Imports,
+		Methods:       methods,
+		Groups:        groups,
 	}
 
-	f, err := os.Create(filepath.Join(baseDir, "generated", "api", "interfaces.go"))
+	err = writeGoFile(filepath.Join(baseDir, "generated", "api", "interfaces.go"), tmpl, data, moduleName)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
 
-	return tmpl.Execute(f, data)
+	return generateResponseHelpers(baseDir, moduleName, backend)
 }
 
-// GenerateRouter generates the HTTP router in generated/server/
-func GenerateRouter(spec *models.OpenAPISpec, baseDir string, moduleName string) error {
-	routerTemplate := `// Code generated by gopenapi. DO NOT EDIT.
-
-package server
-
-import (
-	"context"
-	"net/http"
-	"time"
-
-	"github.com/gin-gonic/gin"
-	"{{.ModuleName}}/generated/api"
-)
-
-// Server wraps the HTTP server
-type Server struct {
-	router   *gin.Engine
-	server   *http.Server
-	handlers api.APIHandlers
-}
-
-// NewServer creates a new HTTP server with the provided handlers
-func NewServer(handlers api.APIHandlers) *Server {
-	router := gin.Default()
-	
-	s := &Server{
-		router:   router,
-		handlers: handlers,
-	}
-	
-	s.setupRoutes()
-	s.setupServer()
-	
-	return s
-}
-
-// Start starts the HTTP server
-func (s *Server) Start(addr string) error {
-	s.server.Addr = addr
-	return s.server.ListenAndServe()
-}
-
-// Shutdown gracefully shuts down the server
-func (s *Server) Shutdown(ctx context.Context) error {
-	return s.server.Shutdown(ctx)
-}
-
-// GetRouter returns the underlying Gin router for advanced configuration
-func (s *Server) GetRouter() *gin.Engine {
-	return s.router
-}
-
-// setupServer configures the HTTP server
-func (s *Server) setupServer() {
-	s.server = &http.Server{
-		Handler:      s.router,
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
-		IdleTimeout:  120 * time.Second,
+// GenerateRouter generates the HTTP router in generated/server/ from the
+// framework backend's router template
+func GenerateRouter(spec *models.OpenAPISpec, baseDir string, moduleName string, framework string) error {
+	server, err := resolveServer(spec, "", nil)
+	if err != nil {
+		return err
 	}
+	return generateRouter(spec, baseDir, moduleName, framework, server)
 }
 
-// setupRoutes configures all API routes
-func (s *Server) setupRoutes() {
-{{range .Routes}}
-	// {{.Comment}}
-	s.router.{{.Method}}("{{.GinPath}}", func(c *gin.Context) {
-		{{if .HasPathParams}}{{range .PathParams}}{{.Name}} := c.Param("{{.Name}}")
-		{{end}}s.handlers.{{.HandlerName}}(c{{range .PathParams}}, {{.Name}}{{end}}){{else}}s.handlers.{{.HandlerName}}(c){{end}}
-	})
-{{end}}
-}
-`
-
-	tmpl, err := template.New("router").Parse(routerTemplate)
+// generateRouter is GenerateRouter for the given server
+func generateRouter(spec *models.OpenAPISpec, baseDir string, moduleName string, framework string, server serverInfo) error {
+	backend, err := LookupBackend(framework)
 	if err != nil {
 		return err
 	}
 
-	var routes []struct {
-		Method        string
-		Path          string
-		GinPath       string
-		HandlerName   string
-		Comment       string
-		HasPathParams bool
-		PathParams    []struct {
-			Name string
-			Type string
-		}
+	tmpl, err := backend.routerTemplate()
+	if err != nil {
+		return err
 	}
 
-	for path, operations := range spec.Paths {
-		for method, op := range operations {
-			ginPath := utils.ConvertPathToGin(path)
-			handlerName := utils.ToCamelCase(op.OperationID)
-
-			comment := strings.ToUpper(method) + " " + path
-			if op.Summary != "" {
-				comment += " - " + op.Summary
-			}
-
-			var pathParams []struct {
-				Name string
-				Type string
-			}
-			for _, param := range op.Parameters {
-				if param.In == pathParameterType {
-					pathParams = append(pathParams, struct {
-						Name string
-						Type string
-					}{
-						Name: param.Name,
-						Type: utils.GetGoType(param.Schema),
-					})
-				}
-			}
-
-			routes = append(routes, struct {
-				Method        string
-				Path          string
-				GinPath       string
-				HandlerName   string
-				Comment       string
-				HasPathParams bool
-				PathParams    []struct {
-					Name string
-					Type string
-				}
-			}{
-				Method:        strings.ToUpper(method),
-				Path:          path,
-				GinPath:       ginPath,
-				HandlerName:   handlerName,
-				Comment:       comment,
-				HasPathParams: len(pathParams) > 0,
-				PathParams:    pathParams,
-			})
-		}
+	routes, err := backend.buildRoutes(spec)
+	if err != nil {
+		return err
 	}
 
-	data := struct {
-		ModuleName string
-		Routes     []struct {
-			Method        string
-			Path          string
-			GinPath       string
-			HandlerName   string
-			Comment       string
-			HasPathParams bool
-			PathParams    []struct {
-				Name string
-				Type string
-			}
-		}
-	}{
+	data := routerData{
 		ModuleName: moduleName,
 		Routes:     routes,
 	}
 
-	f, err := os.Create(filepath.Join(baseDir, "generated", "server", "router.go"))
+	err = writeGoFile(filepath.Join(baseDir, "generated", "server", "router.go"), tmpl, data, moduleName)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
 
-	return tmpl.Execute(f, data)
+	err = generateServer(baseDir, moduleName, backend)
+	if err != nil {
+		return err
+	}
+
+	err = generateOperations(spec, baseDir, moduleName, server)
+	if err != nil {
+		return err
+	}
+
+	return generateServe(baseDir, moduleName)
 }
 
-// GenerateHandlerTemplates generates handler templates ONLY if they don't exist
-func GenerateHandlerTemplates(spec *models.OpenAPISpec, baseDir string, moduleName string) error {
+// GenerateHandlerTemplates generates handler templates in handlers/. If the
+// user already has handlers, only stubs for missing methods are added.
+func GenerateHandlerTemplates(spec *models.OpenAPISpec, baseDir string, moduleName string, framework string) error {
+	return generateHandlerTemplates(spec, baseDir, moduleName, framework, false)
+}
+
+// generateHandlerTemplates generates handler templates in handlers/. With
+// splitByTag, each tag's handlers get a type and file of their own.
+func generateHandlerTemplates(spec *models.OpenAPISpec, baseDir string, moduleName string, framework string, splitByTag bool) error {
+	backend, err := LookupBackend(framework)
+	if err != nil {
+		return err
+	}
+
 	handlerTemplate := `package handlers
 
 import (
 	"net/http"
+{{- range .Imports}}
+	{{goString .}}
+{{- end}}
 
-	"github.com/gin-gonic/gin"
-	"{{.ModuleName}}/generated/api"{{if .HasModels}}
-	"{{.ModuleName}}/generated/models"{{end}}
+	"{{.ModuleName}}/generated/api"
+	"{{.ModuleName}}/generated/models"
 )
 
-// APIHandlers implements the api.APIHandlers interface
-type APIHandlers struct {
+// {{.TypeName}} implements the api.{{.TypeName}} interface
+type {{.TypeName}} struct {
 	// Add your dependencies here:
 	// db     *sql.DB
 	// logger *slog.Logger
 	// cache  redis.Client
 }
 
-// NewAPIHandlers creates a new APIHandlers instance
-func NewAPIHandlers() api.APIHandlers {
-	return &APIHandlers{
+// New{{.TypeName}} creates a new {{.TypeName}} instance
+func New{{.TypeName}}() api.{{.TypeName}} {
+	return &{{.TypeName}}{
 		// Initialize your dependencies here
 	}
 }
 
-{{range .Methods}}
-// {{.HandlerName}} {{.Comment}}
-func (h *APIHandlers) {{.HandlerName}}(c *gin.Context{{.Parameters}}) {
-	// TODO: Implement your business logic here
-	
+{{- range .Methods}}
+
+// {{.HandlerName}} {{comment .Comment}}
+func (h *{{$.TypeName}}) {{.HandlerName}}({{$.ContextParams}}{{.Parameters}}){{if $.HandlerResult}} {{$.HandlerResult}}{{end}} {
 	{{.ExampleCode}}
 }
-
-{{end}}`
-
-	// Check if handlers directory has any .go files
-	handlersDir := filepath.Join(baseDir, "handlers")
-	entries, err := os.ReadDir(handlersDir)
-	if err != nil {
-		return err
-	}
-
-	// If any .go files exist, don't generate
-	for _, entry := range entries {
-		if strings.HasSuffix(entry.Name(), ".go") {
-			return nil // User already has handlers, don't overwrite
-		}
-	}
-
-	tmpl, err := template.New("handlers").Parse(handlerTemplate)
-	if err != nil {
-		return err
-	}
+{{- end}}
+`
 
 	// Generate methods
-	var methods []struct {
-		HandlerName string
-		Comment     string
-		Parameters  string
-		ExampleCode string
-	}
+	var methods []handlerStub
+	gen := examples.New(spec.Components.Schemas, exampleSeed)
 
 	for path, operations := range spec.Paths {
 		for method, op := range operations {
-			handlerName := utils.ToCamelCase(op.OperationID)
+			handlerName := utils.ToGoIdentifier(op.OperationID)
 
 			comment := "handles " + strings.ToUpper(method) + " " + path
 			if op.Summary != "" {
@@ -554,8 +571,7 @@ func (h *APIHandlers) {{.HandlerName}}(c *gin.Context{{.Parameters}}) {
 			var params []string
 			for _, param := range op.Parameters {
 				if param.In == pathParameterType {
-					paramType := utils.GetGoType(param.Schema)
-					params = append(params, param.Name+" "+paramType)
+					params = append(params, goParamName(param.Name)+" "+goTypeOf(param.Schema))
 				}
 			}
 
@@ -564,85 +580,10 @@ func (h *APIHandlers) {{.HandlerName}}(c *gin.Context{{.Parameters}}) {
 				paramStr = ", " + strings.Join(params, ", ")
 			}
 
-			// Generate example code based on method
-			var exampleCode string
-			hasModels := len(spec.Components.Schemas) > 0
-
-			switch strings.ToUpper(method) {
-			case "GET":
-				if hasModels && (strings.Contains(path, "user") || strings.Contains(handlerName, "User")) {
-					exampleCode = `// TODO: Implement your business logic here
-	// Example with sample data
-	users := []models.User{
-		{
-			Id:    "1",
-			Name:  "John Doe", 
-			Email: "john@example.com",
-		},
-	}
-	
-	c.JSON(http.StatusOK, users)`
-				} else {
-					exampleCode = `// TODO: Implement your business logic here
-	
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Success",
-		"data":    nil, // Replace with your data
-	})`
-				}
-			case "POST":
-				if hasModels && (strings.Contains(path, "user") || strings.Contains(handlerName, "User")) {
-					exampleCode = `// TODO: Implement your business logic here
-	
-	// Parse request body
-	var user models.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	
-	// Generate ID for new user (in real app, use UUID or database ID)
-	user.Id = "generated-id-123"
-	
-	c.JSON(http.StatusCreated, user)`
-				} else {
-					exampleCode = `// TODO: Implement your business logic here
-	
-	// Example: Parse request body
-	// var request models.SomeModel
-	// if err := c.ShouldBindJSON(&request); err != nil {
-	//     c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-	//     return
-	// }
-	
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Created successfully",
-	})`
-				}
-			case "PUT":
-				exampleCode = `// TODO: Implement your business logic here
-	
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Updated successfully",
-	})`
-			case "DELETE":
-				exampleCode = `// TODO: Implement your business logic here
-	
-	c.JSON(http.StatusNoContent, nil)`
-			default:
-				exampleCode = `// TODO: Implement your business logic here
-	
-	c.JSON(http.StatusNotImplemented, gin.H{
-		"error": "Not implemented yet",
-	})`
-			}
+			// Stubs write the operation's example response
+			exampleCode := backend.ExampleCode(strings.ToUpper(method), handlerExample(gen, op))
 
-			methods = append(methods, struct {
-				HandlerName string
-				Comment     string
-				Parameters  string
-				ExampleCode string
-			}{
+			methods = append(methods, handlerStub{
 				HandlerName: handlerName,
 				Comment:     comment,
 				Parameters:  paramStr,
@@ -651,26 +592,132 @@ func (h *APIHandlers) {{.HandlerName}}(c *gin.Context{{.Parameters}}) {
 		}
 	}
 
-	data := struct {
-		ModuleName string
-		HasModels  bool
-		Methods    []struct {
-			HandlerName string
-			Comment     string
-			Parameters  string
-			ExampleCode string
+	var groups []tagGroup
+	if splitByTag {
+		groups = tagGroups(spec)
+	}
+
+	// Check if handlers directory has any .go files
+	handlersDir := filepath.Join(baseDir, "handlers")
+	entries, err := os.ReadDir(handlersDir)
+	if err != nil {
+		return err
+	}
+
+	// If any .go files exist, only add stubs for handlers the user doesn't have yet
+	hasHandlers := false
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".go") && !strings.HasSuffix(entry.Name(), "_test.go") {
+			hasHandlers = true
+			break
 		}
-	}{
-		ModuleName: moduleName,
-		HasModels:  len(spec.Components.Schemas) > 0,
-		Methods:    methods,
+	}
+	if hasHandlers && len(groups) == 0 {
+		return addMissingHandlerStubs(handlersDir, methods, moduleName, backend)
 	}
 
-	f, err := os.Create(filepath.Join(baseDir, "handlers", "api.go"))
+	tmpl, err := template.New("handlers").Funcs(templateFuncs).Parse(handlerTemplate)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
 
-	return tmpl.Execute(f, data)
+	type handlerFile struct {
+		ModuleName    string
+		TypeName      string
+		ContextParams string
+		HandlerResult string
+		Imports       []string
+		Methods       []handlerStub
+	}
+
+	if len(groups) == 0 {
+		data := handlerFile{
+			ModuleName:    moduleName,
+			TypeName:      "APIHandlers",
+			ContextParams: backend.ContextParams,
+			HandlerResult: backend.HandlerResult,
+			Imports:       backend.Imports,
+			Methods:       methods,
+		}
+		return writeGoFile(filepath.Join(handlersDir, "api.go"), tmpl, data, moduleName)
+	}
+
+	if hasHandlers {
+		// Tags the user has no handlers type for yet get a file of their own
+		groups, err = addMissingTagHandlerStubs(handlersDir, groups, methods, moduleName, backend)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, group := range groups {
+		data := handlerFile{
+			ModuleName:    moduleName,
+			TypeName:      group.Name,
+			ContextParams: backend.ContextParams,
+			HandlerResult: backend.HandlerResult,
+			Imports:       backend.Imports,
+			Methods:       groupStubs(group, methods),
+		}
+		err = writeGoFile(newStubsPath(handlersDir, group.FileBase()), tmpl, data, moduleName)
+		if err != nil {
+			return err
+		}
+	}
+
+	if hasHandlers {
+		return nil
+	}
+	return generateHandlersConstructor(handlersDir, groups, moduleName)
+}
+
+// generateHandlersConstructor writes handlers/handlers.go, which combines
+// the implementation of each tag's interface into the api.APIHandlers that
+// main.go passes to server.NewServer
+func generateHandlersConstructor(handlersDir string, groups []tagGroup, moduleName string) error {
+	constructorTemplate := `package handlers
+
+import (
+	"{{.ModuleName}}/generated/api"
+)
+
+// NewAPIHandlers combines the handlers of every tag into an api.APIHandlers.
+// A tag added to the spec adds a parameter to api.NewHandlers, in the order
+// of the tags' interface names; pass New<Tag>Handlers() for it.
+func NewAPIHandlers() api.APIHandlers {
+	return api.NewHandlers(
+{{- range .Groups}}
+		New{{.Name}}(),
+{{- end}}
+	)
+}
+`
+
+	tmpl, err := template.New("constructor").Parse(constructorTemplate)
+	if err != nil {
+		return err
+	}
+
+	data := struct {
+		ModuleName string
+		Groups     []tagGroup
+	}{
+		ModuleName: moduleName,
+		Groups:     groups,
+	}
+
+	return writeGoFile(filepath.Join(handlersDir, "handlers.go"), tmpl, data, moduleName)
+}
+
+// groupStubs returns the stubs in methods handled by group, in its order
+func groupStubs(group tagGroup, methods []handlerStub) []handlerStub {
+	byName := map[string]handlerStub{}
+	for _, method := range methods {
+		byName[method.HandlerName] = method
+	}
+	stubs := make([]handlerStub, 0, len(group.Handlers))
+	for _, name := range group.Handlers {
+		stubs = append(stubs, byName[name])
+	}
+	return stubs
 }
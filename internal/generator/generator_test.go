@@ -1,10 +1,19 @@
 package generator
 
 import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"text/template"
 
 	"github.com/shubhamku044/gopenapi/internal/models"
 )
@@ -49,9 +58,7 @@ func TestGenerateCode(t *testing.T) {
 				},
 			},
 		},
-		Components: struct {
-			Schemas map[string]models.Schema `json:"schemas" yaml:"schemas"`
-		}{
+		Components: models.Components{
 			Schemas: map[string]models.Schema{
 				"User": {
 					Type: "object",
@@ -155,7 +162,7 @@ func TestGenerateCode(t *testing.T) {
 		}
 
 		handlerPath := filepath.Join(handlersDir, "api.go")
-		customContent := "// Custom handler implementation content"
+		customContent := "package handlers\n\n// Custom handler implementation content\ntype APIHandlers struct{}\n"
 		err = os.WriteFile(handlerPath, []byte(customContent), 0600)
 		if err != nil {
 			t.Fatalf("Failed to create custom api.go: %v", err)
@@ -224,7 +231,7 @@ func TestGenerateGoMod(t *testing.T) {
 	tempDir := t.TempDir()
 	moduleName := testModule
 
-	err := GenerateGoModIfNotExists(tempDir, moduleName)
+	err := GenerateGoModIfNotExists(tempDir, moduleName, FrameworkGin)
 	if err != nil {
 		t.Fatalf("GenerateGoMod failed: %v", err)
 	}
@@ -283,6 +290,11 @@ func TestGenerateHandlerTemplates(t *testing.T) {
 				"get": models.Operation{
 					OperationID: "list_users",
 					Summary:     "List all users",
+					Responses: map[string]models.Response{
+						"200": {Content: map[string]models.MediaType{"application/json": {
+							Schema: models.Schema{Type: "array", Items: &models.Schema{Ref: "#/components/schemas/User"}},
+						}}},
+					},
 				},
 				"post": models.Operation{
 					OperationID: "create_user",
@@ -290,9 +302,7 @@ func TestGenerateHandlerTemplates(t *testing.T) {
 				},
 			},
 		},
-		Components: struct {
-			Schemas map[string]models.Schema `json:"schemas" yaml:"schemas"`
-		}{
+		Components: models.Components{
 			Schemas: map[string]models.Schema{
 				"User": {
 					Type: "object",
@@ -314,7 +324,7 @@ func TestGenerateHandlerTemplates(t *testing.T) {
 		t.Fatalf("Failed to create project structure: %v", err)
 	}
 
-	err = GenerateHandlerTemplates(spec, tempDir, moduleName)
+	err = GenerateHandlerTemplates(spec, tempDir, moduleName, FrameworkGin)
 	if err != nil {
 		t.Fatalf("GenerateHandlerTemplates failed: %v", err)
 	}
@@ -338,7 +348,8 @@ func TestGenerateHandlerTemplates(t *testing.T) {
 		"CreateUser",
 		"TODO: Implement your business logic",
 		moduleName + "/generated/api",
-		moduleName + "/generated/models",
+		`"encoding/json"`,
+		"c.JSON(http.StatusOK, json.RawMessage(`[",
 	}
 
 	for _, expected := range expectedContent {
@@ -369,7 +380,7 @@ func TestGenerateInterfaces(t *testing.T) {
 		t.Fatalf("Failed to create project structure: %v", err)
 	}
 
-	err = GenerateInterfaces(spec, tempDir, moduleName)
+	err = GenerateInterfaces(spec, tempDir, moduleName, FrameworkGin)
 	if err != nil {
 		t.Fatalf("GenerateInterfaces failed: %v", err)
 	}
@@ -611,7 +622,7 @@ func TestGenerateGoModIfNotExistsEdgeCases(t *testing.T) {
 			t.Fatalf("Failed to create existing go.mod: %v", err)
 		}
 
-		err = GenerateGoModIfNotExists(tempDir, "newmodule")
+		err = GenerateGoModIfNotExists(tempDir, "newmodule", FrameworkGin)
 		if err != nil {
 			t.Fatalf("GenerateGoModIfNotExists failed: %v", err)
 		}
@@ -626,6 +637,69 @@ func TestGenerateGoModIfNotExistsEdgeCases(t *testing.T) {
 			t.Errorf("Expected existing go.mod content to be preserved")
 		}
 	})
+
+	t.Run("RaisesGoVersion", func(t *testing.T) {
+		require := "\nrequire github.com/gin-gonic/gin v1.10.0\n"
+		tests := []struct {
+			name      string
+			existing  string
+			framework string
+			expected  string
+		}{
+			{
+				name:      "OlderForNetHTTP",
+				existing:  "module existing/module\n\ngo 1.21" + require,
+				framework: FrameworkNetHTTP,
+				expected:  "module existing/module\n\ngo 1.22" + require,
+			},
+			{
+				name:      "PatchVersionForNetHTTP",
+				existing:  "module existing/module\n\ngo 1.21.5" + require,
+				framework: FrameworkNetHTTP,
+				expected:  "module existing/module\n\ngo 1.22" + require,
+			},
+			{
+				name:      "Missing",
+				existing:  "module existing/module\n" + require,
+				framework: FrameworkNetHTTP,
+				expected:  "module existing/module\n\ngo 1.22\n" + require,
+			},
+			{
+				name:      "RecentEnough",
+				existing:  "module existing/module\n\ngo 1.23.1" + require,
+				framework: FrameworkNetHTTP,
+				expected:  "module existing/module\n\ngo 1.23.1" + require,
+			},
+			{
+				name:      "GinKeepsGo121",
+				existing:  "module existing/module\n\ngo 1.21" + require,
+				framework: FrameworkGin,
+				expected:  "module existing/module\n\ngo 1.21" + require,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				tempDir := t.TempDir()
+				goModPath := filepath.Join(tempDir, "go.mod")
+				if err := os.WriteFile(goModPath, []byte(tt.existing), 0600); err != nil {
+					t.Fatalf("Failed to create existing go.mod: %v", err)
+				}
+
+				if err := GenerateGoModIfNotExists(tempDir, "newmodule", tt.framework); err != nil {
+					t.Fatalf("GenerateGoModIfNotExists failed: %v", err)
+				}
+
+				content, err := os.ReadFile(goModPath)
+				if err != nil {
+					t.Fatalf("Failed to read go.mod: %v", err)
+				}
+				if string(content) != tt.expected {
+					t.Errorf("Expected go.mod:\n%s\ngot:\n%s", tt.expected, content)
+				}
+			})
+		}
+	})
 }
 
 func TestGenerateUserMainIfNotExistsEdgeCases(t *testing.T) {
@@ -671,7 +745,7 @@ func TestGenerateHandlerTemplatesEdgeCases(t *testing.T) {
 		}
 
 		existingFile := filepath.Join(handlerDir, "existing.go")
-		err = os.WriteFile(existingFile, []byte("// existing handler"), 0600)
+		err = os.WriteFile(existingFile, []byte("package handlers\n\n// existing handler\ntype APIHandlers struct{}\n"), 0600)
 		if err != nil {
 			t.Fatalf("Failed to create existing handler: %v", err)
 		}
@@ -684,7 +758,7 @@ func TestGenerateHandlerTemplatesEdgeCases(t *testing.T) {
 			},
 		}
 
-		err = GenerateHandlerTemplates(spec, tempDir, testModule)
+		err = GenerateHandlerTemplates(spec, tempDir, testModule, FrameworkGin)
 		if err != nil {
 			t.Fatalf("GenerateHandlerTemplates failed: %v", err)
 		}
@@ -720,7 +794,7 @@ func TestGenerateHandlerTemplatesEdgeCases(t *testing.T) {
 			},
 		}
 
-		err = GenerateHandlerTemplates(spec, tempDir, testModule)
+		err = GenerateHandlerTemplates(spec, tempDir, testModule, FrameworkGin)
 		if err != nil {
 			t.Fatalf("GenerateHandlerTemplates failed: %v", err)
 		}
@@ -747,71 +821,6 @@ func TestGenerateHandlerTemplatesEdgeCases(t *testing.T) {
 	})
 }
 
-func TestHasTimeFieldsFunction(t *testing.T) {
-	t.Run("SchemaWithTimeFields", func(t *testing.T) {
-		schema := models.Schema{
-			Type:   "string",
-			Format: "date-time",
-		}
-
-		result := hasTimeFields(schema)
-		if !result {
-			t.Errorf("Expected hasTimeFields to return true for schema with date-time format")
-		}
-	})
-
-	t.Run("SchemaWithDateFields", func(t *testing.T) {
-		schema := models.Schema{
-			Type:   "string",
-			Format: "date",
-		}
-
-		result := hasTimeFields(schema)
-		if !result {
-			t.Errorf("Expected hasTimeFields to return true for schema with date format")
-		}
-	})
-
-	t.Run("SchemaWithTimeFieldsInProperties", func(t *testing.T) {
-		schema := models.Schema{
-			Type: "object",
-			Properties: map[string]models.Schema{
-				"created_at": {Type: "string", Format: "date-time"},
-				"name":       {Type: "string"},
-			},
-		}
-
-		result := hasTimeFields(schema)
-		if !result {
-			t.Errorf("Expected hasTimeFields to return true for schema with date-time fields in properties")
-		}
-	})
-
-	t.Run("SchemaWithoutTimeFields", func(t *testing.T) {
-		schema := models.Schema{
-			Type: "object",
-			Properties: map[string]models.Schema{
-				"name":  {Type: "string"},
-				"email": {Type: "string"},
-			},
-		}
-
-		result := hasTimeFields(schema)
-		if result {
-			t.Errorf("Expected hasTimeFields to return false for schema without date/date-time fields")
-		}
-	})
-
-	t.Run("EmptySchema", func(t *testing.T) {
-		schema := models.Schema{}
-
-		result := hasTimeFields(schema)
-		if result {
-			t.Errorf("Expected hasTimeFields to return false for empty schema")
-		}
-	})
-}
-
 func TestGenerateInterfacesEdgeCases(t *testing.T) {
 	t.Run("SpecWithComplexOperations", func(t *testing.T) {
 		tempDir := t.TempDir()
@@ -837,7 +846,7 @@ func TestGenerateInterfacesEdgeCases(t *testing.T) {
 			},
 		}
 
-		err = GenerateInterfaces(spec, tempDir, "complex/module")
+		err = GenerateInterfaces(spec, tempDir, "complex/module", FrameworkGin)
 		if err != nil {
 			t.Fatalf("GenerateInterfaces failed: %v", err)
 		}
@@ -874,7 +883,7 @@ func TestGenerateRouterEdgeCases(t *testing.T) {
 			},
 		}
 
-		err = GenerateRouter(spec, tempDir, "testmodule")
+		err = GenerateRouter(spec, tempDir, "testmodule", FrameworkGin)
 		if err != nil {
 			t.Fatalf("GenerateRouter failed: %v", err)
 		}
@@ -949,7 +958,7 @@ func TestGenerateReadme(t *testing.T) {
 	spec.Info.Version = testAPIVersion
 	spec.Info.Description = "A test API for demonstration"
 
-	err := GenerateReadme(spec, tempDir, testModule)
+	err := GenerateReadme(spec, tempDir, testModule, FrameworkGin)
 	if err != nil {
 		t.Fatalf("GenerateReadme failed: %v", err)
 	}
@@ -972,11 +981,77 @@ func TestGenerateReadme(t *testing.T) {
 	}
 }
 
+func TestGenerateReadmeExamples(t *testing.T) {
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/pets": {
+				"post": {
+					OperationID: "create_pet",
+					RequestBody: &models.RequestBody{Content: map[string]models.MediaType{
+						"application/json": {Schema: models.Schema{Ref: "#/components/schemas/Pet"}},
+					}},
+					Responses: map[string]models.Response{
+						"201": {Content: map[string]models.MediaType{
+							"application/json": {Example: map[string]interface{}{"id": 7, "name": "Rex"}},
+						}},
+					},
+				},
+			},
+			"/pets/{id}": {
+				"delete": {OperationID: "delete_pet", Responses: map[string]models.Response{"204": {}}},
+			},
+		},
+	}
+	spec.Components.Schemas = map[string]models.Schema{
+		"Pet": {
+			Type:     "object",
+			Required: []string{"name"},
+			Properties: map[string]models.Schema{
+				"name":    {Type: "string", Example: "Rex"},
+				"species": {Type: "string", Enum: []interface{}{"dog"}},
+			},
+		},
+	}
+
+	generate := func() string {
+		tempDir := t.TempDir()
+		if err := GenerateReadme(spec, tempDir, testModule, FrameworkGin); err != nil {
+			t.Fatalf("GenerateReadme failed: %v", err)
+		}
+		content, err := os.ReadFile(filepath.Join(tempDir, "README.md"))
+		if err != nil {
+			t.Fatalf("Failed to read README.md: %v", err)
+		}
+		return string(content)
+	}
+
+	readme := generate()
+	for _, snippet := range []string{
+		`-d '{"name":"Rex","species":"dog"}'`,
+		"{\n  \"name\": \"Rex\",\n  \"species\": \"dog\"\n}",
+		"{\n  \"id\": 7,\n  \"name\": \"Rex\"\n}",
+		"c.JSON(http.StatusCreated, json.RawMessage(`{",
+		"c.Status(http.StatusNoContent)",
+	} {
+		if !strings.Contains(readme, snippet) {
+			t.Errorf("Expected README.md to contain %q", snippet)
+		}
+	}
+	for _, snippet := range []string{`"key": "value"`, "John Doe", "models.SomeModel"} {
+		if strings.Contains(readme, snippet) {
+			t.Errorf("Expected README.md not to contain %q", snippet)
+		}
+	}
+	if generate() != readme {
+		t.Error("Expected README.md to be the same when generated again")
+	}
+}
+
 func TestGenerateGoModIfNotExists(t *testing.T) {
 	t.Run("WithoutExistingGoMod", func(t *testing.T) {
 		tempDir := t.TempDir()
 
-		err := GenerateGoModIfNotExists(tempDir, testModule)
+		err := GenerateGoModIfNotExists(tempDir, testModule, FrameworkGin)
 		if err != nil {
 			t.Fatalf("GenerateGoModIfNotExists failed: %v", err)
 		}
@@ -1010,7 +1085,7 @@ func TestGenerateGoModIfNotExists(t *testing.T) {
 			t.Fatalf("Failed to create existing go.mod: %v", err)
 		}
 
-		err = GenerateGoModIfNotExists(tempDir, "newmodule")
+		err = GenerateGoModIfNotExists(tempDir, "newmodule", FrameworkGin)
 		if err != nil {
 			t.Fatalf("GenerateGoModIfNotExists failed: %v", err)
 		}
@@ -1026,3 +1101,3162 @@ func TestGenerateGoModIfNotExists(t *testing.T) {
 		}
 	})
 }
+
+func TestFixImports(t *testing.T) {
+	t.Run("DropsUnusedAndAddsMissing", func(t *testing.T) {
+		src := `package handlers
+
+import (
+	"net/http"
+
+	"test/module/generated/models"
+)
+
+func Now() time.Time {
+	return time.Now()
+}
+`
+		out, err := fixImports("handlers.go", []byte(src), testModule)
+		if err != nil {
+			t.Fatalf("fixImports failed: %v", err)
+		}
+
+		contentStr := string(out)
+		if !strings.Contains(contentStr, `"time"`) {
+			t.Errorf("Expected missing time import to be added")
+		}
+		if strings.Contains(contentStr, `"net/http"`) || strings.Contains(contentStr, "generated/models") {
+			t.Errorf("Expected unused imports to be removed, got:\n%s", contentStr)
+		}
+	})
+
+	t.Run("AddsImportBlockWhenMissing", func(t *testing.T) {
+		src := "package api\n\ntype Handler func(c *gin.Context)\n"
+		out, err := fixImports("api.go", []byte(src), testModule)
+		if err != nil {
+			t.Fatalf("fixImports failed: %v", err)
+		}
+		if !strings.Contains(string(out), "import (\n\t\"github.com/gin-gonic/gin\"\n)") {
+			t.Errorf("Expected gin import block to be added, got:\n%s", out)
+		}
+	})
+
+	t.Run("KeepsMajorVersionImports", func(t *testing.T) {
+		src := "package api\n\nimport \"github.com/labstack/echo/v4\"\n\ntype Handler func(c echo.Context) error\n"
+		out, err := fixImports("api.go", []byte(src), testModule)
+		if err != nil {
+			t.Fatalf("fixImports failed: %v", err)
+		}
+		if !strings.Contains(string(out), `"github.com/labstack/echo/v4"`) {
+			t.Errorf("Expected echo/v4 import to be kept, got:\n%s", out)
+		}
+	})
+
+	t.Run("IgnoresLocalIdentifiers", func(t *testing.T) {
+		src := "package api\n\nfunc f() {\n\thttp := struct{ Get int }{}\n\t_ = http.Get\n}\n"
+		out, err := fixImports("api.go", []byte(src), testModule)
+		if err != nil {
+			t.Fatalf("fixImports failed: %v", err)
+		}
+		if strings.Contains(string(out), "net/http") {
+			t.Errorf("Expected local variable not to be treated as a package")
+		}
+	})
+}
+
+func TestGenerateHandlerTemplatesWithoutModelUsage(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := createProjectStructure(tempDir); err != nil {
+		t.Fatalf("Failed to create project structure: %v", err)
+	}
+
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/health": {
+				"get": {OperationID: "health_check"},
+			},
+		},
+	}
+	spec.Components.Schemas = map[string]models.Schema{
+		"Status": {Type: "object"},
+	}
+
+	if err := GenerateHandlerTemplates(spec, tempDir, testModule, FrameworkGin); err != nil {
+		t.Fatalf("GenerateHandlerTemplates failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "handlers", "api.go"))
+	if err != nil {
+		t.Fatalf("Failed to read handlers/api.go: %v", err)
+	}
+	if strings.Contains(string(content), testModule+"/generated/models") {
+		t.Errorf("handlers/api.go imports models although no handler uses them")
+	}
+}
+
+func TestGeneratedFilesAreGofmtClean(t *testing.T) {
+	tempDir := t.TempDir()
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/users": {
+				"get":  {OperationID: "list_users", Summary: "List users"},
+				"post": {OperationID: "create_user"},
+			},
+			"/users/{id}": {
+				"get": {
+					OperationID: "get_user",
+					Parameters:  []models.Parameter{{Name: "id", In: "path", Schema: models.Schema{Type: "string"}}},
+				},
+			},
+		},
+	}
+	spec.Components.Schemas = map[string]models.Schema{
+		"User": {
+			Type: "object",
+			Properties: map[string]models.Schema{
+				"id":         {Type: "string"},
+				"created_at": {Type: "string", Format: "date-time"},
+			},
+		},
+	}
+
+	config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	files := []string{
+		"main.go",
+		"handlers/api.go",
+		"generated/api/interfaces.go",
+		"generated/models/models.go",
+		"generated/server/router.go",
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(tempDir, file))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		formatted, err := format.Source(content)
+		if err != nil {
+			t.Fatalf("%s is not valid Go: %v", file, err)
+		}
+		if !bytes.Equal(formatted, content) {
+			t.Errorf("%s is not gofmt-clean", file)
+		}
+	}
+}
+
+func TestWriteGoFileReportsTemplateErrors(t *testing.T) {
+	tmpl := template.Must(template.New("broken").Parse("package broken\n\nfunc {{.Name}}( {\n}\n"))
+	filename := filepath.Join(t.TempDir(), "broken.go")
+
+	err := writeGoFile(filename, tmpl, struct{ Name string }{Name: "Broken"}, testModule)
+	if err == nil {
+		t.Fatalf("Expected error for template producing invalid Go")
+	}
+	if !strings.Contains(err.Error(), "func Broken( {") {
+		t.Errorf("Expected error to quote the offending source, got: %v", err)
+	}
+	if _, statErr := os.Stat(filename); !os.IsNotExist(statErr) {
+		t.Errorf("Expected broken source not to be written")
+	}
+}
+
+func TestGenerateCodeWithHostileSpec(t *testing.T) {
+	injection := "x\")\n}\n\nfunc init() { panic(\"pwned\") }\n/* */ //"
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/users/{user-id}\"/*": {
+				"get": {
+					OperationID: "get user */ ; os.Exit(1)",
+					Summary:     "Summary with newline\nfunc init() {}\n*/ and \"quotes\"",
+					Description: "100% `raw` <script>alert(1)</script>",
+					Parameters: []models.Parameter{
+						{Name: "user-id", In: "path", Schema: models.Schema{Type: "string"}},
+						{Name: "c", In: "path", Schema: models.Schema{Type: "string"}},
+					},
+				},
+			},
+			"/type": {
+				"post": {OperationID: "type", Summary: injection},
+			},
+		},
+	}
+	spec.Info.Title = "# Title\n<h1>injected</h1>"
+	spec.Components.Schemas = map[string]models.Schema{
+		"Bad-Name": {
+			Type: "object",
+			Properties: map[string]models.Schema{
+				"weird`\"name": {Type: "string", Description: "desc */\nfunc init() {}"},
+				"func":         {Ref: "#/components/schemas/Bad-Name"},
+			},
+		},
+	}
+
+	tempDir := t.TempDir()
+	config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	if err := GenerateMainFile(spec, tempDir, testModule); err != nil {
+		t.Fatalf("GenerateMainFile failed: %v", err)
+	}
+
+	files := []string{
+		"main.go",
+		"handlers/api.go",
+		"generated/api/interfaces.go",
+		"generated/models/models.go",
+		"generated/server/router.go",
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(filepath.Join(tempDir, file))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+
+		fset := token.NewFileSet()
+		parsed, err := parser.ParseFile(fset, file, content, 0)
+		if err != nil {
+			t.Fatalf("%s is not valid Go: %v", file, err)
+		}
+		for _, decl := range parsed.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "init" {
+				t.Errorf("%s contains an injected init function", file)
+			}
+		}
+	}
+
+	readme, err := os.ReadFile(filepath.Join(tempDir, "README.md"))
+	if err != nil {
+		t.Fatalf("Failed to read README.md: %v", err)
+	}
+	if strings.Contains(string(readme), "<h1>") || strings.Contains(string(readme), "<script>") {
+		t.Errorf("README.md contains unescaped HTML from the spec")
+	}
+}
+
+func TestGenerateHandlerTemplatesAddsMissingStubs(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := createProjectStructure(tempDir); err != nil {
+		t.Fatalf("Failed to create project structure: %v", err)
+	}
+
+	existing := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+type APIHandlers struct{}
+
+func (a *APIHandlers) ListUsers(c *gin.Context) {
+	c.Status(200)
+}
+`
+	existingPath := filepath.Join(tempDir, "handlers", "api.go")
+	if err := os.WriteFile(existingPath, []byte(existing), 0600); err != nil {
+		t.Fatalf("Failed to create existing handlers: %v", err)
+	}
+
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/users": {
+				"get":  {OperationID: "list_users"},
+				"post": {OperationID: "create_user", Summary: "Create a user"},
+			},
+			"/users/{id}": {
+				"get": {
+					OperationID: "get_user",
+					Parameters:  []models.Parameter{{Name: "id", In: "path", Schema: models.Schema{Type: "string"}}},
+				},
+			},
+		},
+	}
+
+	if err := GenerateHandlerTemplates(spec, tempDir, testModule, FrameworkGin); err != nil {
+		t.Fatalf("GenerateHandlerTemplates failed: %v", err)
+	}
+
+	content, err := os.ReadFile(existingPath)
+	if err != nil {
+		t.Fatalf("Failed to read handlers/api.go: %v", err)
+	}
+	if string(content) != existing {
+		t.Errorf("Existing handlers were modified")
+	}
+
+	stubs, err := os.ReadFile(filepath.Join(tempDir, "handlers", "stubs.go"))
+	if err != nil {
+		t.Fatalf("Expected stubs.go to be created: %v", err)
+	}
+	stubsStr := string(stubs)
+	for _, expected := range []string{
+		"func (a *APIHandlers) CreateUser(c *gin.Context)",
+		"func (a *APIHandlers) GetUser(c *gin.Context, id string)",
+		"http.StatusNotImplemented",
+	} {
+		if !strings.Contains(stubsStr, expected) {
+			t.Errorf("stubs.go does not contain expected content: %s", expected)
+		}
+	}
+	if strings.Contains(stubsStr, "ListUsers") {
+		t.Errorf("stubs.go redeclares an existing handler")
+	}
+
+	// A second run finds every method implemented and adds nothing
+	if err := GenerateHandlerTemplates(spec, tempDir, testModule, FrameworkGin); err != nil {
+		t.Fatalf("GenerateHandlerTemplates failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "handlers", "stubs_2.go")); !os.IsNotExist(err) {
+		t.Errorf("Expected no further stubs when all handlers exist")
+	}
+}
+
+func TestMissingStubsMatchFreshStubs(t *testing.T) {
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/users": {
+				"get": {OperationID: "list_users"},
+				"post": {
+					OperationID: "create_user",
+					Responses: map[string]models.Response{
+						"201": {Content: map[string]models.MediaType{
+							"application/json": {Example: map[string]interface{}{"id": "u1"}},
+						}},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("ReceiverCollidingWithContext", func(t *testing.T) {
+		tempDir := t.TempDir()
+		if err := createProjectStructure(tempDir); err != nil {
+			t.Fatalf("Failed to create project structure: %v", err)
+		}
+		existing := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+type APIHandlers struct{}
+
+func (c *APIHandlers) ListUsers(ctx *gin.Context) {
+	ctx.Status(200)
+}
+`
+		if err := os.WriteFile(filepath.Join(tempDir, "handlers", "api.go"), []byte(existing), 0600); err != nil {
+			t.Fatalf("Failed to create existing handlers: %v", err)
+		}
+
+		if err := GenerateHandlerTemplates(spec, tempDir, testModule, FrameworkGin); err != nil {
+			t.Fatalf("GenerateHandlerTemplates failed: %v", err)
+		}
+		stubs, err := os.ReadFile(filepath.Join(tempDir, "handlers", "stubs.go"))
+		if err != nil {
+			t.Fatalf("Expected stubs.go to be created: %v", err)
+		}
+		for _, expected := range []string{
+			"func (h *APIHandlers) CreateUser(c *gin.Context)",
+			"// Example response from the spec",
+			"c.JSON(http.StatusCreated",
+		} {
+			if !strings.Contains(string(stubs), expected) {
+				t.Errorf("Expected stubs.go to contain %q, got:\n%s", expected, stubs)
+			}
+		}
+		if strings.Contains(string(stubs), "StatusNotImplemented") {
+			t.Errorf("Expected the stub to respond with the spec's example, got:\n%s", stubs)
+		}
+	})
+
+	t.Run("UnparsableHandlers", func(t *testing.T) {
+		tempDir := t.TempDir()
+		if err := createProjectStructure(tempDir); err != nil {
+			t.Fatalf("Failed to create project structure: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(tempDir, "handlers", "api.go"), []byte("package handlers\n\nfunc broken( {\n"), 0600); err != nil {
+			t.Fatalf("Failed to create existing handlers: %v", err)
+		}
+
+		err := GenerateHandlerTemplates(spec, tempDir, testModule, FrameworkGin)
+		if err == nil || !strings.Contains(err.Error(), "api.go") {
+			t.Errorf("Expected an error locating the unparsable handlers, got %v", err)
+		}
+	})
+
+	t.Run("NoImplementation", func(t *testing.T) {
+		tempDir := t.TempDir()
+		if err := createProjectStructure(tempDir); err != nil {
+			t.Fatalf("Failed to create project structure: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(tempDir, "handlers", "doc.go"), []byte("package handlers\n"), 0600); err != nil {
+			t.Fatalf("Failed to create existing handlers: %v", err)
+		}
+
+		err := GenerateHandlerTemplates(spec, tempDir, testModule, FrameworkGin)
+		if err == nil || !strings.Contains(err.Error(), "no type implementing api.APIHandlers") {
+			t.Errorf("Expected an error about the missing implementation, got %v", err)
+		}
+	})
+}
+
+func TestCheckHandlers(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := createProjectStructure(tempDir); err != nil {
+		t.Fatalf("Failed to create project structure: %v", err)
+	}
+
+	existing := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+type APIHandlers struct{}
+
+func (h *APIHandlers) GetUser(c *gin.Context, userID int) {
+	c.JSON(200, userID)
+}
+
+func (h *APIHandlers) DeleteUser(c *gin.Context, id string) {}
+
+func (h *APIHandlers) helper(id string) string { return id }
+`
+	handlerPath := filepath.Join(tempDir, "handlers", "api.go")
+	if err := os.WriteFile(handlerPath, []byte(existing), 0600); err != nil {
+		t.Fatalf("Failed to create existing handlers: %v", err)
+	}
+
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/users": {
+				"get": {OperationID: "list_users"},
+			},
+			"/users/{id}": {
+				"get": {
+					OperationID: "get_user",
+					Parameters:  []models.Parameter{{Name: "id", In: "path", Schema: models.Schema{Type: "string"}}},
+				},
+			},
+		},
+	}
+
+	report, err := CheckHandlers(spec, tempDir)
+	if err != nil {
+		t.Fatalf("CheckHandlers failed: %v", err)
+	}
+	if !report.HasIssues() {
+		t.Fatalf("Expected issues to be reported")
+	}
+	if len(report.Missing) != 1 || report.Missing[0] != "ListUsers" {
+		t.Errorf("Expected ListUsers to be missing, got %v", report.Missing)
+	}
+	if len(report.Obsolete) != 1 || report.Obsolete[0].Method != "DeleteUser" {
+		t.Errorf("Expected DeleteUser to be obsolete, got %+v", report.Obsolete)
+	}
+	if len(report.Drifted) != 1 {
+		t.Fatalf("Expected one drifted handler, got %+v", report.Drifted)
+	}
+	drifted := report.Drifted[0]
+	// The user's parameter name is kept, only its type follows the spec
+	if drifted.Have != "GetUser(c *gin.Context, userID int)" || drifted.Want != "GetUser(c *gin.Context, userID string)" {
+		t.Errorf("Unexpected signature diff: have %q, want %q", drifted.Have, drifted.Want)
+	}
+
+	if err := FixHandlerSignatures(report); err != nil {
+		t.Fatalf("FixHandlerSignatures failed: %v", err)
+	}
+
+	content, err := os.ReadFile(handlerPath)
+	if err != nil {
+		t.Fatalf("Failed to read handlers/api.go: %v", err)
+	}
+	expected := strings.Replace(existing, "GetUser(c *gin.Context, userID int)", "GetUser(c *gin.Context, userID string)", 1)
+	if string(content) != expected {
+		t.Errorf("Expected only the signature to change, got:\n%s", content)
+	}
+
+	report, err = CheckHandlers(spec, tempDir)
+	if err != nil {
+		t.Fatalf("CheckHandlers failed: %v", err)
+	}
+	if len(report.Drifted) != 0 {
+		t.Errorf("Expected no drift after fixing, got %+v", report.Drifted)
+	}
+}
+
+func TestGenerateClient(t *testing.T) {
+	tempDir := t.TempDir()
+	required := true
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/users": {
+				"get": {
+					OperationID: "list_users",
+					Parameters: []models.Parameter{
+						{Name: "limit", In: "query", Schema: models.Schema{Type: "integer"}},
+						{Name: "X-Request-ID", In: "header", Required: required, Schema: models.Schema{Type: "string"}},
+					},
+					Responses: map[string]models.Response{
+						"200": {Content: jsonContent(models.Schema{Type: "array", Items: &models.Schema{Ref: "#/components/schemas/User"}})},
+					},
+				},
+			},
+			"/users/{id}": {
+				"get": {
+					OperationID: "get_user",
+					Parameters:  []models.Parameter{{Name: "id", In: "path", Required: true, Schema: models.Schema{Type: "string"}}},
+					Responses: map[string]models.Response{
+						"200": {Content: jsonContent(models.Schema{Ref: "#/components/schemas/User"})},
+						"404": {Content: jsonContent(models.Schema{Ref: "#/components/schemas/Error"})},
+					},
+				},
+			},
+		},
+	}
+	spec.Components.Schemas = map[string]models.Schema{
+		"User":  {Type: "object", Properties: map[string]models.Schema{"id": {Type: "string"}}},
+		"Error": {Type: "object", Properties: map[string]models.Schema{"message": {Type: "string"}}},
+	}
+
+	if err := os.MkdirAll(filepath.Join(tempDir, "generated", "client"), 0750); err != nil {
+		t.Fatalf("Failed to create client directory: %v", err)
+	}
+	if err := GenerateClient(spec, tempDir, testModule, nil); err != nil {
+		t.Fatalf("GenerateClient failed: %v", err)
+	}
+
+	for _, file := range []string{"client.go", "operations.go"} {
+		if _, err := parser.ParseFile(token.NewFileSet(), filepath.Join(tempDir, "generated", "client", file), nil, 0); err != nil {
+			t.Fatalf("%s is not valid Go: %v", file, err)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "generated", "client", "operations.go"))
+	if err != nil {
+		t.Fatalf("Failed to read operations.go: %v", err)
+	}
+	expected := []string{
+		"func (c *Client) GetUser(ctx context.Context, id string) (*models.User, error)",
+		"func (c *Client) ListUsers(ctx context.Context, params *ListUsersParams) ([]models.User, error)",
+		"type ListUsersParams struct",
+		"Limit      *int",
+		"XRequestId string",
+		"case status == 404:",
+		"return new(models.Error)",
+		`"test/module/generated/models"`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(string(content), exp) {
+			t.Errorf("Expected operations.go to contain %q", exp)
+		}
+	}
+
+	for title, want := range map[string]string{
+		"":              "// Client calls the generated API over HTTP",
+		"Pet Store":     "// Client calls the Pet Store API over HTTP",
+		"Pet Store API": "// Client calls the Pet Store API over HTTP",
+	} {
+		spec.Info.Title = title
+		if err := GenerateClient(spec, tempDir, testModule, nil); err != nil {
+			t.Fatalf("GenerateClient failed: %v", err)
+		}
+		content, err := os.ReadFile(filepath.Join(tempDir, "generated", "client", "client.go"))
+		if err != nil {
+			t.Fatalf("Failed to read client.go: %v", err)
+		}
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Title %q: expected client.go to contain %q", title, want)
+		}
+	}
+}
+
+// jsonContent returns response content with schema as its application/json body
+func jsonContent(schema models.Schema) map[string]models.MediaType {
+	return map[string]models.MediaType{"application/json": {Schema: schema}}
+}
+
+func TestGeneratedClientRetries(t *testing.T) {
+	retryable := true
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/jobs": {
+				"post": {OperationID: "create_job"},
+			},
+			"/jobs/retryable": {
+				"post": {OperationID: "create_job_retryable", Retryable: &retryable},
+			},
+			"/jobs/{id}": {
+				"get": {
+					OperationID: "get_job",
+					Timeout:     "50ms",
+					Parameters:  []models.Parameter{{Name: "id", In: "path", Required: true, Schema: models.Schema{Type: "string"}}},
+				},
+			},
+		},
+	}
+
+	clientTest := `package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failing returns a server failing the first n requests with status
+func failing(n int32, status int, retryAfter string) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= n {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(status)
+		}
+	}))
+	return srv, &calls
+}
+
+func fastRetry() Option {
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	return WithRetry(policy)
+}
+
+func TestRetriesIdempotentOperations(t *testing.T) {
+	srv, calls := failing(2, http.StatusServiceUnavailable, "")
+	defer srv.Close()
+	if err := NewClient(srv.URL, fastRetry()).GetJob(context.Background(), "1"); err != nil || *calls != 3 {
+		t.Fatalf("err = %v, calls = %d", err, *calls)
+	}
+}
+
+func TestDoesNotRetryNonIdempotentOperations(t *testing.T) {
+	srv, calls := failing(1, http.StatusServiceUnavailable, "")
+	defer srv.Close()
+	if err := NewClient(srv.URL, fastRetry()).CreateJob(context.Background()); err == nil || *calls != 1 {
+		t.Fatalf("err = %v, calls = %d", err, *calls)
+	}
+}
+
+func TestRetriesOperationsMarkedRetryable(t *testing.T) {
+	srv, calls := failing(1, http.StatusTooManyRequests, "0")
+	defer srv.Close()
+	if err := NewClient(srv.URL, fastRetry()).CreateJobRetryable(context.Background()); err != nil || *calls != 2 {
+		t.Fatalf("err = %v, calls = %d", err, *calls)
+	}
+}
+
+func TestGivesUpWhenRetryAfterExceedsTimeout(t *testing.T) {
+	srv, calls := failing(1, http.StatusServiceUnavailable, "10")
+	defer srv.Close()
+	if err := NewClient(srv.URL, fastRetry()).GetJob(context.Background(), "1"); err == nil || *calls != 1 {
+		t.Fatalf("err = %v, calls = %d", err, *calls)
+	}
+}
+
+func TestOperationTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, WithOperationTimeout("CreateJob", 20*time.Millisecond))
+	if err := c.CreateJob(context.Background()); err == nil {
+		t.Fatalf("Expected CreateJob to time out")
+	}
+}
+`
+	runGeneratedClientTests(t, spec, nil, clientTest)
+}
+
+// runGeneratedClientTests generates the client for spec into a module of its
+// own and runs clientTest against it with the go toolchain
+func runGeneratedClientTests(t *testing.T, spec *models.OpenAPISpec, heuristic *PaginationHeuristic, clientTest string) {
+	t.Helper()
+	tempDir := t.TempDir()
+	for _, dir := range []string{"client", "models"} {
+		if err := os.MkdirAll(filepath.Join(tempDir, "generated", dir), 0750); err != nil {
+			t.Fatalf("Failed to create %s directory: %v", dir, err)
+		}
+	}
+	if err := GenerateModels(spec, filepath.Join(tempDir, "generated")); err != nil {
+		t.Fatalf("GenerateModels failed: %v", err)
+	}
+	if err := GenerateClient(spec, tempDir, testModule, heuristic); err != nil {
+		t.Fatalf("GenerateClient failed: %v", err)
+	}
+
+	files := map[string]string{
+		"go.mod":                          "module " + testModule + "\n\ngo 1.21\n",
+		"generated/client/client_test.go": clientTest,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	runGo(t, tempDir, "test", "./generated/client/")
+}
+
+// runGo runs the go command in dir, skipping the test when the toolchain
+// isn't available
+func runGo(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("Skipping build of generated code in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	cmd := exec.Command(goBin, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off", "GOTOOLCHAIN=local")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go %s failed: %v\n%s", strings.Join(args, " "), err, output)
+	}
+}
+
+// runGoFailing runs the go command like runGo, expecting it to fail, and
+// returns its output
+func runGoFailing(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("Skipping build of generated code in short mode")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not available")
+	}
+
+	cmd := exec.Command(goBin, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off", "GOTOOLCHAIN=local")
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected go %s to fail", strings.Join(args, " "))
+	}
+	return string(output)
+}
+
+func TestGeneratedClientPagination(t *testing.T) {
+	query := func(name string) models.Parameter {
+		return models.Parameter{Name: name, In: "query", Schema: models.Schema{Type: "integer"}}
+	}
+	stringList := models.Schema{Type: "array", Items: &models.Schema{Type: "string"}}
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/events": {
+				"get": {
+					OperationID: "list_events",
+					Parameters:  []models.Parameter{{Name: "cursor", In: "query", Schema: models.Schema{Type: "string"}}},
+					Responses: map[string]models.Response{
+						"200": {Content: jsonContent(models.Schema{Ref: "#/components/schemas/EventPage"})},
+					},
+				},
+			},
+			"/names": {
+				"get": {
+					OperationID: "list_names",
+					Parameters:  []models.Parameter{query("offset"), query("limit")},
+					Responses:   map[string]models.Response{"200": {Content: jsonContent(stringList)}},
+				},
+			},
+			"/tags": {
+				"get": {
+					OperationID: "list_tags",
+					Parameters:  []models.Parameter{query("p")},
+					Pagination:  &models.Pagination{PageParam: "p"},
+					Responses:   map[string]models.Response{"200": {Content: jsonContent(stringList)}},
+				},
+			},
+		},
+	}
+	spec.Components.Schemas = map[string]models.Schema{
+		"EventPage": {
+			Type: "object",
+			Properties: map[string]models.Schema{
+				"data":        stringList,
+				"next_cursor": {Type: "string"},
+			},
+		},
+	}
+
+	clientTest := `//go:build go1.23
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+var names = []string{"a", "b", "c", "d", "e"}
+
+func serve(t *testing.T) *Client {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		pages := map[string]string{"": ` + "`" + `{"data":["a","b"],"next_cursor":"2"}` + "`" + `, "2": ` + "`" + `{"data":["c"]}` + "`" + `}
+		w.Write([]byte(pages[r.URL.Query().Get("cursor")]))
+	})
+	mux.HandleFunc("/names", func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		json.NewEncoder(w).Encode(names[min(offset, len(names)):min(offset+limit, len(names))])
+	})
+	mux.HandleFunc("/tags", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("p"))
+		if page > 2 {
+			w.Write([]byte("[]"))
+			return
+		}
+		json.NewEncoder(w).Encode([]string{"page" + strconv.Itoa(page)})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL)
+}
+
+func collect(t *testing.T, seq func(func(string, error) bool)) []string {
+	var result []string
+	for item, err := range seq {
+		if err != nil {
+			t.Fatalf("Iteration failed: %v", err)
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+func TestIterators(t *testing.T) {
+	c := serve(t)
+	limit := 2
+	tests := map[string]struct {
+		got  []string
+		want string
+	}{
+		"cursor": {collect(t, c.ListEventsIter(context.Background(), nil)), "a b c"},
+		"offset": {collect(t, c.ListNamesIter(context.Background(), &ListNamesParams{Limit: &limit})), "a b c d e"},
+		"page":   {collect(t, c.ListTagsIter(context.Background(), nil)), "page1 page2"},
+	}
+	for name, tt := range tests {
+		if got := fmt.Sprint(tt.got); got != "["+tt.want+"]" {
+			t.Errorf("%s: got %s, want [%s]", name, got, tt.want)
+		}
+	}
+}
+
+func TestIteratorStopsEarly(t *testing.T) {
+	c := serve(t)
+	for item := range c.ListEventsIter(context.Background(), nil) {
+		if item != "a" {
+			t.Fatalf("Expected to stop after the first item, got %q", item)
+		}
+		break
+	}
+}
+`
+	runGeneratedClientTests(t, spec, DefaultPaginationHeuristic(), clientTest)
+}
+
+func TestGenerateCodeNetHTTP(t *testing.T) {
+	tempDir := t.TempDir()
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/users": {
+				"get": {
+					OperationID: "list_users",
+					Summary:     "List users",
+					Responses: map[string]models.Response{
+						"200": {Content: map[string]models.MediaType{"application/json": {
+							Schema: models.Schema{Type: "array", Items: &models.Schema{Ref: "#/components/schemas/User"}},
+						}}},
+					},
+				},
+				"post": {
+					OperationID: "create_user",
+					Responses: map[string]models.Response{
+						"201": {Content: map[string]models.MediaType{"application/json": {
+							Example: map[string]interface{}{"id": "u1"},
+						}}},
+					},
+				},
+			},
+			"/users/{user-id}": {
+				"get": {
+					OperationID: "get_user",
+					Parameters:  []models.Parameter{{Name: "user-id", In: "path", Schema: models.Schema{Type: "string"}}},
+				},
+				"delete": {
+					OperationID: "delete_user",
+					Parameters:  []models.Parameter{{Name: "user-id", In: "path", Schema: models.Schema{Type: "string"}}},
+					Responses:   map[string]models.Response{"204": {Description: "Deleted"}},
+				},
+			},
+			"/items/": {
+				"get": {OperationID: "list_items"},
+			},
+		},
+	}
+	spec.Components.Schemas = map[string]models.Schema{
+		"User": {
+			Type: "object",
+			Properties: map[string]models.Schema{
+				"id":    {Type: "string"},
+				"name":  {Type: "string"},
+				"email": {Type: "string"},
+			},
+		},
+	}
+
+	config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule, Framework: FrameworkNetHTTP}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	expected := map[string][]string{
+		"generated/server/router.go": {
+			`s.router.HandleFunc("GET /users/{user_id}", s.operation("GetUser", func(w http.ResponseWriter, r *http.Request) {`,
+			`user_id := r.PathValue("user_id")`,
+			`s.handlers.GetUser(w, r, user_id)`,
+			`s.router.HandleFunc("GET /items/{$}"`,
+		},
+		"generated/api/interfaces.go": {
+			"DeleteUser(w http.ResponseWriter, r *http.Request, user_id string)",
+		},
+		"handlers/api.go": {
+			"func (h *APIHandlers) ListUsers(w http.ResponseWriter, r *http.Request) {",
+			"// Example response synthesized from the response schema\n\tapi.WriteJSON(w, http.StatusOK, json.RawMessage(`[",
+			"// Example response from the spec\n\tapi.WriteJSON(w, http.StatusCreated, json.RawMessage(`{",
+			"w.WriteHeader(http.StatusNoContent)",
+			// get_user declares no response to write
+			"api.WriteJSON(w, http.StatusNotImplemented, map[string]interface{}{",
+		},
+		"go.mod": {"go 1.22"},
+	}
+	for file, snippets := range expected {
+		content, err := os.ReadFile(filepath.Join(tempDir, file))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		for _, snippet := range snippets {
+			if !strings.Contains(string(content), snippet) {
+				t.Errorf("Expected %s to contain %q", file, snippet)
+			}
+		}
+		if strings.Contains(string(content), "gin") {
+			t.Errorf("Expected %s not to reference gin", file)
+		}
+		for _, payload := range []string{`"Success"`, "models.SomeModel"} {
+			if strings.Contains(string(content), payload) {
+				t.Errorf("Expected %s not to contain the placeholder %s", file, payload)
+			}
+		}
+	}
+
+	runGo(t, tempDir, "vet", "./...")
+}
+
+func TestServeMuxPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"/users", "/users", false},
+		{"/", "/{$}", false},
+		{"/users/{id}/", "/users/{id}/{$}", false},
+		{"/orgs/{org-id}/users/{type}", "/orgs/{org_id}/users/{type_}", false},
+		{"/files/{name}.json", "", true},
+		{"/a/{b-c}/{b_c}", "", true},
+	}
+
+	for _, tt := range tests {
+		got, _, err := serveMuxPath(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("serveMuxPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("serveMuxPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateCodeUnsupportedFramework(t *testing.T) {
+	config := Config{OutputDir: t.TempDir(), PackageName: "testapi", Framework: "martini"}
+	err := GenerateCode(&models.OpenAPISpec{}, config)
+	if err == nil || !strings.Contains(err.Error(), "unsupported framework") {
+		t.Errorf("Expected unsupported framework error, got %v", err)
+	}
+}
+
+func TestCheckHandlersNetHTTP(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := createProjectStructure(tempDir); err != nil {
+		t.Fatalf("Failed to create project structure: %v", err)
+	}
+
+	existing := `package handlers
+
+import "net/http"
+
+type APIHandlers struct{}
+
+func (h *APIHandlers) GetUser(rw http.ResponseWriter, req *http.Request, id int) {}
+
+func (h *APIHandlers) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+`
+	handlerPath := filepath.Join(tempDir, "handlers", "api.go")
+	if err := os.WriteFile(handlerPath, []byte(existing), 0600); err != nil {
+		t.Fatalf("Failed to create existing handlers: %v", err)
+	}
+
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/users/{id}": {
+				"get": {
+					OperationID: "get_user",
+					Parameters:  []models.Parameter{{Name: "id", In: "path", Schema: models.Schema{Type: "string"}}},
+				},
+			},
+		},
+	}
+
+	report, err := CheckHandlers(spec, tempDir)
+	if err != nil {
+		t.Fatalf("CheckHandlers failed: %v", err)
+	}
+	if len(report.Drifted) != 1 {
+		t.Fatalf("Expected one drifted handler, got %+v", report.Drifted)
+	}
+	if want := "GetUser(rw http.ResponseWriter, req *http.Request, id string)"; report.Drifted[0].Want != want {
+		t.Errorf("Expected wanted signature %q, got %q", want, report.Drifted[0].Want)
+	}
+	if len(report.Obsolete) != 1 || report.Obsolete[0].Method != "ServeHTTP" {
+		t.Errorf("Expected ServeHTTP to be reported as obsolete, got %+v", report.Obsolete)
+	}
+}
+
+func TestCheckHandlersUnnamedContext(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := createProjectStructure(tempDir); err != nil {
+		t.Fatalf("Failed to create project structure: %v", err)
+	}
+
+	existing := `package handlers
+
+import "github.com/gin-gonic/gin"
+
+type APIHandlers struct{}
+
+func (h *APIHandlers) GetPet(*gin.Context) {}
+
+func (h *APIHandlers) GetOwner(*gin.Context, string) {}
+`
+	handlerPath := filepath.Join(tempDir, "handlers", "api.go")
+	if err := os.WriteFile(handlerPath, []byte(existing), 0600); err != nil {
+		t.Fatalf("Failed to create existing handlers: %v", err)
+	}
+
+	petID := models.Parameter{Name: "petId", In: "path", Schema: models.Schema{Type: "integer", Format: "int64"}}
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/pets/{petId}":         {"get": {OperationID: "get_pet", Parameters: []models.Parameter{petID}}},
+			"/owners/{petId}/owner": {"get": {OperationID: "get_owner", Parameters: []models.Parameter{petID}}},
+		},
+	}
+
+	report, err := CheckHandlers(spec, tempDir)
+	if err != nil {
+		t.Fatalf("CheckHandlers failed: %v", err)
+	}
+	want := map[string]string{
+		// The context parameter is named so that the new parameter can be
+		"GetPet": "GetPet(c *gin.Context, petId int64)",
+		// Unnamed parameters stay unnamed when only their types drifted
+		"GetOwner": "GetOwner(*gin.Context, int64)",
+	}
+	if len(report.Drifted) != len(want) {
+		t.Fatalf("Expected %d drifted handlers, got %+v", len(want), report.Drifted)
+	}
+	for _, issue := range report.Drifted {
+		if issue.Want != want[issue.Method] {
+			t.Errorf("Expected wanted signature %q, got %q", want[issue.Method], issue.Want)
+		}
+	}
+
+	if err := FixHandlerSignatures(report); err != nil {
+		t.Fatalf("FixHandlerSignatures failed: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), handlerPath, nil, 0); err != nil {
+		t.Errorf("Fixed handlers are not valid Go: %v", err)
+	}
+}
+
+func TestGenerateCodeBackends(t *testing.T) {
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/users/{user-id}": {
+				"get": {
+					OperationID: "get_user",
+					Parameters:  []models.Parameter{{Name: "user-id", In: "path", Schema: models.Schema{Type: "string"}}},
+				},
+				"delete": {
+					OperationID: "delete_user",
+					Parameters:  []models.Parameter{{Name: "user-id", In: "path", Schema: models.Schema{Type: "string"}}},
+					Responses:   map[string]models.Response{"204": {}},
+				},
+			},
+			"/orders/{orderId}": {
+				"get": {
+					OperationID: "get_order",
+					Parameters:  []models.Parameter{{Name: "orderId", In: "path", Schema: models.Schema{Type: "integer"}}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		framework string
+		expected  map[string][]string
+	}{
+		{
+			framework: FrameworkChi,
+			expected: map[string][]string{
+				"generated/server/router.go": {
+					`"github.com/go-chi/chi/v5"`,
+					`s.router.MethodFunc("GET", "/users/{user-id}", s.operation("GetUser", func(w http.ResponseWriter, r *http.Request) {`,
+					`user_id := chi.URLParam(r, "user-id")`,
+					`orderId, ok := pathParam[int](w, "orderId", chi.URLParam(r, "orderId"))`,
+				},
+				"generated/server/server.go":  {"func (s *Server) GetRouter() *chi.Mux {"},
+				"generated/api/interfaces.go": {"GetUser(w http.ResponseWriter, r *http.Request, user_id string)"},
+				"generated/api/responses.go":  {"func WriteJSON("},
+				"handlers/api.go":             {"w.WriteHeader(http.StatusNoContent)"},
+				"go.mod":                      {"github.com/go-chi/chi/v5"},
+			},
+		},
+		{
+			framework: FrameworkEcho,
+			expected: map[string][]string{
+				"generated/server/router.go": {
+					`"github.com/labstack/echo/v4"`,
+					`s.router.Add("GET", "/users/:user-id", s.operation("GetUser", func(c echo.Context) error {`,
+					`return s.handlers.GetUser(c, user_id)`,
+					`orderId, ok := pathParam[int](c.Response(), "orderId", c.Param("orderId"))`,
+				},
+				"generated/server/server.go":  {"func (s *Server) GetRouter() *echo.Echo {"},
+				"generated/api/interfaces.go": {`"github.com/labstack/echo/v4"`, "GetUser(c echo.Context, user_id string) error"},
+				"handlers/api.go": {
+					`"github.com/labstack/echo/v4"`,
+					"func (h *APIHandlers) DeleteUser(c echo.Context, user_id string) error {",
+					"return c.NoContent(http.StatusNoContent)",
+				},
+				"go.mod": {"github.com/labstack/echo/v4"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.framework, func(t *testing.T) {
+			tempDir := t.TempDir()
+			config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule, Framework: tt.framework}
+			if err := GenerateCode(spec, config); err != nil {
+				t.Fatalf("GenerateCode failed: %v", err)
+			}
+
+			for file, snippets := range tt.expected {
+				content, err := os.ReadFile(filepath.Join(tempDir, file))
+				if err != nil {
+					t.Fatalf("Failed to read %s: %v", file, err)
+				}
+				for _, snippet := range snippets {
+					if !strings.Contains(string(content), snippet) {
+						t.Errorf("Expected %s to contain %q", file, snippet)
+					}
+				}
+				if strings.Contains(string(content), "gin") {
+					t.Errorf("Expected %s not to reference gin", file)
+				}
+			}
+
+			runGo(t, tempDir, "vet", "./...")
+		})
+	}
+}
+
+func TestGeneratedParamsShadowingPackages(t *testing.T) {
+	var params []models.Parameter
+	for _, name := range []string{"http", "gin", "json", "models", "api", "-"} {
+		params = append(params, models.Parameter{Name: name, In: "path", Required: true, Schema: models.Schema{Type: "string"}})
+	}
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/things/{http}/{gin}/{json}/{models}/{api}/{-}": {
+				"get": {
+					OperationID: "get_thing",
+					Parameters:  params,
+					Responses: map[string]models.Response{
+						"200": {Content: jsonContent(models.Schema{Type: "object", Properties: map[string]models.Schema{"id": {Type: "string"}}})},
+					},
+				},
+			},
+		},
+	}
+
+	for _, framework := range []string{FrameworkGin, FrameworkNetHTTP} {
+		t.Run(framework, func(t *testing.T) {
+			tempDir := t.TempDir()
+			config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule, Framework: framework}
+			if err := GenerateCode(spec, config); err != nil {
+				t.Fatalf("GenerateCode failed: %v", err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(tempDir, "generated", "api", "interfaces.go"))
+			if err != nil {
+				t.Fatalf("Failed to read interfaces.go: %v", err)
+			}
+			want := "httpParam string, ginParam string, jsonParam string, modelsParam string, apiParam string, param string)"
+			if !strings.Contains(string(content), want) {
+				t.Errorf("Expected interfaces.go to contain %q", want)
+			}
+
+			runGo(t, tempDir, "vet", "./...")
+		})
+	}
+}
+
+func TestLoadBackendFile(t *testing.T) {
+	dir := t.TempDir()
+	definition := `name: test-mux
+pathStyle: braces
+contextParams: "w http.ResponseWriter, r *http.Request"
+contextTypes: ["http.ResponseWriter", "*http.Request"]
+goVersion: "1.22"
+routerType: "*http.ServeMux"
+routerTemplate: router.tmpl
+responseHelpers: true
+notImplemented: |
+  http.Error(w, "not implemented", http.StatusNotImplemented)
+`
+	routerTemplate := `// Code generated by gopenapi. DO NOT EDIT.
+
+package server
+
+import "{{.ModuleName}}/generated/api"
+
+// Server serves the API
+type Server struct {
+	router   *http.ServeMux
+	handlers api.APIHandlers
+}
+
+// NewServer creates a new server with the provided handlers
+func NewServer(handlers api.APIHandlers) *Server {
+	s := &Server{router: http.NewServeMux(), handlers: handlers}
+{{- range .Routes}}
+	s.router.HandleFunc({{goString (print .Method " " .Route)}}, func(w http.ResponseWriter, r *http.Request) {
+		s.handlers.{{.HandlerName}}(w, r{{range .PathParams}}, r.PathValue({{goString .Key}}){{end}})
+	})
+{{- end}}
+	return s
+}
+
+// Start starts the HTTP server
+func (s *Server) Start(addr string) error {
+	return http.ListenAndServe(addr, s.router)
+}
+
+// Shutdown is a no-op
+func (s *Server) Shutdown(ctx context.Context) error {
+	return nil
+}
+`
+	backendPath := filepath.Join(dir, "backend.yaml")
+	if err := os.WriteFile(backendPath, []byte(definition), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "router.tmpl"), []byte(routerTemplate), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	backend, err := LoadBackendFile(backendPath)
+	if err != nil {
+		t.Fatalf("LoadBackendFile failed: %v", err)
+	}
+	// The registry is global, so the backend may be left over from an earlier run
+	if _, err := LookupBackend(backend.Name); err != nil {
+		if err := RegisterBackend(backend); err != nil {
+			t.Fatalf("RegisterBackend failed: %v", err)
+		}
+	}
+	if err := RegisterBackend(backend); err == nil {
+		t.Errorf("Expected registering a backend twice to fail")
+	}
+
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/users/{id}": {
+				"get": {
+					OperationID: "get_user",
+					Parameters:  []models.Parameter{{Name: "id", In: "path", Schema: models.Schema{Type: "string"}}},
+				},
+			},
+		},
+	}
+
+	tempDir := t.TempDir()
+	config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule, Framework: "test-mux"}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	router, err := os.ReadFile(filepath.Join(tempDir, "generated", "server", "router.go"))
+	if err != nil {
+		t.Fatalf("Failed to read router: %v", err)
+	}
+	if !strings.Contains(string(router), `s.router.HandleFunc("GET /users/{id}"`) {
+		t.Errorf("Expected router generated from the backend template, got:\n%s", router)
+	}
+
+	handlers, err := os.ReadFile(filepath.Join(tempDir, "handlers", "api.go"))
+	if err != nil {
+		t.Fatalf("Failed to read handlers: %v", err)
+	}
+	if !strings.Contains(string(handlers), `http.Error(w, "not implemented", http.StatusNotImplemented)`) {
+		t.Errorf("Expected handlers to use the backend's notImplemented code, got:\n%s", handlers)
+	}
+
+	runGo(t, tempDir, "vet", "./...")
+}
+
+func TestRegisterBackendValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend Backend
+	}{
+		{"NoName", Backend{}},
+		{"NoPathConversion", Backend{Name: "incomplete"}},
+		{"Builtin", *chiBackend()},
+		{"BadTemplate", Backend{
+			Name:           "broken",
+			ConvertPath:    chiPath,
+			ContextParams:  "c *Context",
+			ContextTypes:   []string{"*Context"},
+			RouterType:     "*Router",
+			ExampleCode:    netHTTPExampleCode,
+			RouterTemplate: "{{range}}",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := tt.backend
+			if err := RegisterBackend(&backend); err == nil {
+				t.Errorf("Expected RegisterBackend to fail")
+			}
+		})
+	}
+}
+
+func TestSpecDocuments(t *testing.T) {
+	t.Run("FromYAML", func(t *testing.T) {
+		spec := &models.OpenAPISpec{Raw: []byte(`openapi: 3.0.0
+info: {title: Pets, version: 2024-01-01}
+paths:
+  /pets:
+    get:
+      responses:
+        200: {description: ok}
+`)}
+		jsonDoc, yamlDoc, err := specDocuments(spec)
+		if err != nil {
+			t.Fatalf("specDocuments failed: %v", err)
+		}
+
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(jsonDoc, &decoded); err != nil {
+			t.Fatalf("Expected valid JSON, got %v:\n%s", err, jsonDoc)
+		}
+		if !strings.HasPrefix(string(jsonDoc), "{\n  \"openapi\": \"3.0.0\",\n  \"info\"") {
+			t.Errorf("Expected JSON to keep the document's key order, got:\n%s", jsonDoc)
+		}
+		for _, snippet := range []string{`"version": "2024-01-01"`, `"200": {`} {
+			if !strings.Contains(string(jsonDoc), snippet) {
+				t.Errorf("Expected JSON to contain %q, got:\n%s", snippet, jsonDoc)
+			}
+		}
+		if !strings.Contains(string(yamlDoc), "info:\n  title: Pets\n") {
+			t.Errorf("Expected YAML in block style, got:\n%s", yamlDoc)
+		}
+	})
+
+	t.Run("FromJSON", func(t *testing.T) {
+		spec := &models.OpenAPISpec{Raw: []byte(`{"openapi": "3.0.0", "info": {"title": "Pets", "version": "1"}, "paths": {}}`)}
+		_, yamlDoc, err := specDocuments(spec)
+		if err != nil {
+			t.Fatalf("specDocuments failed: %v", err)
+		}
+		want := "openapi: 3.0.0\ninfo:\n  title: Pets\n  version: \"1\"\npaths: {}\n"
+		if string(yamlDoc) != want {
+			t.Errorf("specDocuments YAML = %q, want %q", yamlDoc, want)
+		}
+	})
+
+	t.Run("FromModel", func(t *testing.T) {
+		spec := &models.OpenAPISpec{}
+		spec.Info.Title = "Pets"
+		jsonDoc, _, err := specDocuments(spec)
+		if err != nil {
+			t.Fatalf("specDocuments failed: %v", err)
+		}
+		if !strings.Contains(string(jsonDoc), `"title": "Pets"`) || strings.Contains(string(jsonDoc), "Raw") {
+			t.Errorf("Expected the document rendered from the model, got:\n%s", jsonDoc)
+		}
+	})
+}
+
+func TestGeneratedSpecRoutes(t *testing.T) {
+	tempDir := t.TempDir()
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/pets": {"get": {OperationID: "list_pets"}},
+		},
+		Raw: []byte("openapi: 3.0.0\ninfo:\n  title: Pets\n  version: '1'\npaths:\n  /pets:\n    get:\n      operationId: list_pets\n"),
+	}
+
+	config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule, Framework: FrameworkNetHTTP}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	for _, name := range []string{"openapi.json", "openapi.yaml", "docs.html", "spec.go"} {
+		if _, err := os.Stat(filepath.Join(tempDir, "generated", "server", name)); err != nil {
+			t.Errorf("Expected generated/server/%s: %v", name, err)
+		}
+	}
+
+	serverTest := `package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSpecRoutes(t *testing.T) {
+	srv := httptest.NewServer(NewServer(nil, WithSpecRoutes()).GetRouter())
+	defer srv.Close()
+
+	for path, contentType := range map[string]string{
+		"/openapi.json": "application/json",
+		"/openapi.yaml": "application/yaml",
+		"/docs":         "text/html; charset=utf-8",
+	} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != contentType {
+			t.Errorf("GET %s = %d %s", path, resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+	}
+	if !strings.Contains(string(SpecJSON()), "\"operationId\": \"list_pets\"") {
+		t.Errorf("unexpected spec: %s", SpecJSON())
+	}
+
+	plain := httptest.NewServer(NewServer(nil).GetRouter())
+	defer plain.Close()
+	resp, err := http.Get(plain.URL + "/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected spec routes to be opt-in, got %d", resp.StatusCode)
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "generated", "server", "server_test.go"), []byte(serverTest), 0600); err != nil {
+		t.Fatalf("Failed to write server test: %v", err)
+	}
+
+	runGo(t, tempDir, "test", "./generated/server/")
+}
+
+func TestSchemaLiteral(t *testing.T) {
+	minimum, maxLength := 1.5, 10
+	additional := false
+	schema := models.Schema{
+		Type:                 "object",
+		Required:             []string{"kind"},
+		AdditionalProperties: &additional,
+		Properties: map[string]models.Schema{
+			"kind":  {Type: "string", Enum: []interface{}{"a", 2, 2.5, true, nil}},
+			"owner": {Ref: "#/components/schemas/Owner"},
+			"size":  {Type: "number", Minimum: &minimum},
+			"name":  {Type: "string", MaxLength: &maxLength, Pattern: `^\w+$`},
+		},
+	}
+
+	got, err := schemaLiteral(&schema)
+	if err != nil {
+		t.Fatalf("schemaLiteral failed: %v", err)
+	}
+	want := `&validate.Schema{Type: "object", Properties: map[string]*validate.Schema{` +
+		`"kind": &validate.Schema{Type: "string", Enum: []interface{}{"a", 2, float64(2.5), true, nil}}, ` +
+		`"name": &validate.Schema{Type: "string", MaxLength: validate.Int(10), Pattern: "^\\w+$"}, ` +
+		`"owner": &validate.Schema{Ref: "Owner"}, ` +
+		`"size": &validate.Schema{Type: "number", Minimum: validate.Float(1.5)}}, ` +
+		`Required: []string{"kind"}, AdditionalProperties: validate.Bool(false)}`
+	if got != want {
+		t.Errorf("schemaLiteral =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := schemaLiteral(&models.Schema{Enum: []interface{}{struct{}{}}}); err == nil {
+		t.Error("expected an error for an unsupported enum value")
+	}
+}
+
+func TestGeneratedRequestValidation(t *testing.T) {
+	tempDir := t.TempDir()
+	limitMax := 100.0
+	nameMin := 1
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/pets": {
+				"get": {
+					OperationID: "list_pets",
+					Parameters: []models.Parameter{
+						{Name: "limit", In: "query", Schema: models.Schema{Type: "integer", Maximum: &limitMax}},
+					},
+				},
+				"post": {
+					OperationID: "create_pet",
+					RequestBody: &models.RequestBody{
+						Required: true,
+						Content:  jsonContent(models.Schema{Ref: "#/components/schemas/Pet"}),
+					},
+				},
+			},
+			"/pets/{petId}": {
+				"get": {
+					OperationID: "get_pet",
+					Parameters:  []models.Parameter{{Name: "petId", In: "path", Required: true, Schema: models.Schema{Type: "string", Pattern: "^[0-9]+$"}}},
+				},
+			},
+		},
+	}
+	spec.Components.Schemas = map[string]models.Schema{
+		"Pet": {
+			Type:     "object",
+			Required: []string{"name", "kind"},
+			Properties: map[string]models.Schema{
+				"name": {Type: "string", MinLength: &nameMin},
+				"kind": {Type: "string", Enum: []interface{}{"cat", "dog"}},
+			},
+		},
+	}
+
+	config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule, Framework: FrameworkNetHTTP}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	validation, err := os.ReadFile(filepath.Join(tempDir, "generated", "server", "validation.go"))
+	if err != nil {
+		t.Fatalf("Failed to read validation.go: %v", err)
+	}
+	for _, want := range []string{
+		`"Pet": &validate.Schema{`,
+		`Enum: []interface{}{"cat", "dog"}`,
+		`{Name: "limit", In: "query", Explode: true, Schema: &validate.Schema{Type: "integer", Maximum: validate.Float(100)}}`,
+		`Body: &validate.Body{Required: true, ContentTypes: []string{"application/json"}, Schema: &validate.Schema{Ref: "Pet"}}`,
+	} {
+		if !strings.Contains(string(validation), want) {
+			t.Errorf("Expected validation.go to contain %s, got:\n%s", want, validation)
+		}
+	}
+
+	serverTest := `package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestValidation(t *testing.T) {
+	// Invalid requests never reach the nil handlers
+	s := NewServer(nil, WithRequestValidation())
+	s.registerRoutes()
+	srv := httptest.NewServer(s.server.Handler)
+	defer srv.Close()
+
+	tests := []struct {
+		method, path, body string
+		status             int
+		field              string
+	}{
+		{"GET", "/pets?limit=500", "", http.StatusBadRequest, "limit"},
+		{"GET", "/pets/abc", "", http.StatusBadRequest, "petId"},
+		{"POST", "/pets", "", http.StatusBadRequest, ""},
+		{"POST", "/pets", "{\"name\":\"Rex\",\"kind\":\"fish\"}", http.StatusUnprocessableEntity, "kind"},
+		{"POST", "/pets", "{\"kind\":\"cat\"}", http.StatusUnprocessableEntity, "name"},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body struct {
+			Errors []struct{ In, Field, Message string }
+		}
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("%s %s: %v", tt.method, tt.path, err)
+		}
+		if resp.StatusCode != tt.status || len(body.Errors) != 1 || body.Errors[0].Field != tt.field {
+			t.Errorf("%s %s = %d %+v, want %d for %q", tt.method, tt.path, resp.StatusCode, body.Errors, tt.status, tt.field)
+		}
+	}
+
+	v := Validator()
+	req := httptest.NewRequest("POST", "/pets", strings.NewReader("{\"name\":\"Rex\",\"kind\":\"dog\"}"))
+	req.Header.Set("Content-Type", "application/json")
+	if err := v.ValidateRequest(req); err != nil {
+		t.Errorf("valid request rejected: %v", err)
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "generated", "server", "server_test.go"), []byte(serverTest), 0600); err != nil {
+		t.Fatalf("Failed to write server test: %v", err)
+	}
+
+	runGo(t, tempDir, "test", "./generated/server/", "./generated/validate/")
+}
+
+func TestGeneratedResponseValidation(t *testing.T) {
+	tempDir := t.TempDir()
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/pets": {
+				"get": {
+					OperationID: "list_pets",
+					Responses: map[string]models.Response{
+						"200": {Content: jsonContent(models.Schema{Type: "array", Items: &models.Schema{Ref: "#/components/schemas/Pet"}})},
+					},
+				},
+				"post": {
+					OperationID: "create_pet",
+					Responses: map[string]models.Response{
+						"201": {Content: jsonContent(models.Schema{Ref: "#/components/schemas/Pet"})},
+					},
+				},
+			},
+		},
+	}
+	spec.Components.Schemas = map[string]models.Schema{
+		"Pet": {
+			Type:       "object",
+			Required:   []string{"name"},
+			Properties: map[string]models.Schema{"name": {Type: "string"}},
+		},
+	}
+
+	config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule, Framework: FrameworkNetHTTP}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	validation, err := os.ReadFile(filepath.Join(tempDir, "generated", "server", "validation.go"))
+	if err != nil {
+		t.Fatalf("Failed to read validation.go: %v", err)
+	}
+	want := `"201": {ContentTypes: []string{"application/json"}, Schema: &validate.Schema{Ref: "Pet"}}`
+	if !strings.Contains(string(validation), want) {
+		t.Errorf("Expected validation.go to contain %s, got:\n%s", want, validation)
+	}
+
+	serverTest := `package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"test/module/generated/validate"
+)
+
+// driftingHandlers responds with a status and body the spec doesn't declare
+type driftingHandlers struct{}
+
+func (driftingHandlers) ListPets(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = io.WriteString(w, "[{\"name\":\"Rex\"},{\"id\":2}]")
+}
+
+func (driftingHandlers) CreatePet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = io.WriteString(w, "{\"name\":\"Rex\"}")
+}
+
+func get(t *testing.T, srv *Server, method string) (int, string) {
+	t.Helper()
+	srv.registerRoutes()
+	w := httptest.NewRecorder()
+	srv.server.Handler.ServeHTTP(w, httptest.NewRequest(method, "/pets", nil))
+	return w.Code, w.Body.String()
+}
+
+func TestResponseValidation(t *testing.T) {
+	strict := NewServer(driftingHandlers{}, WithResponseValidation(validate.RejectViolations))
+	if status, body := get(t, strict, "GET"); status != http.StatusInternalServerError || !strings.Contains(body, "[1].name") {
+		t.Errorf("GET /pets = %d %s", status, body)
+	}
+	if status, body := get(t, strict, "POST"); status != http.StatusInternalServerError || !strings.Contains(body, "200 is not declared, want 201") {
+		t.Errorf("POST /pets = %d %s", status, body)
+	}
+
+	logging := NewServer(driftingHandlers{}, WithResponseValidation(validate.LogViolations))
+	if status, body := get(t, logging, "POST"); status != http.StatusOK || body != "{\"name\":\"Rex\"}" {
+		t.Errorf("POST /pets = %d %s", status, body)
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "generated", "server", "server_test.go"), []byte(serverTest), 0600); err != nil {
+		t.Fatalf("Failed to write server test: %v", err)
+	}
+
+	runGo(t, tempDir, "test", "./generated/server/")
+}
+
+func TestGeneratedSecurity(t *testing.T) {
+	tempDir := t.TempDir()
+	public := []models.SecurityRequirement{}
+	keyOrAdmin := []models.SecurityRequirement{{"apiKey": {}}, {"bearerAuth": {"admin"}}}
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/pets": {
+				"get": {OperationID: "list_pets"},
+				"post": {OperationID: "create_pet", Security: &keyOrAdmin, RequestBody: &models.RequestBody{
+					Required: true,
+					Content: jsonContent(models.Schema{
+						Type:       "object",
+						Required:   []string{"name"},
+						Properties: map[string]models.Schema{"name": {Type: "string"}},
+					}),
+				}},
+			},
+			"/health": {
+				"get": {OperationID: "health", Security: &public},
+			},
+		},
+		Security: []models.SecurityRequirement{{"bearerAuth": {}}},
+	}
+	spec.Components.SecuritySchemes = map[string]models.SecurityScheme{
+		"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+		"apiKey":     {Type: "apiKey", In: "header", Name: "X-API-Key"},
+	}
+
+	config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule, Framework: FrameworkNetHTTP}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	security, err := os.ReadFile(filepath.Join(tempDir, "generated", "server", "security.go"))
+	if err != nil {
+		t.Fatalf("Failed to read security.go: %v", err)
+	}
+	for _, want := range []string{
+		`"apiKey":     &auth.Scheme{Name: "apiKey", Type: "apiKey", In: "header", ParamName: "X-API-Key"}`,
+		`"CreatePet": {
+		{{Scheme: securitySchemes["apiKey"]}},
+		{{Scheme: securitySchemes["bearerAuth"], Scopes: []string{"admin"}}},
+	},`,
+		`"ListPets": {
+		{{Scheme: securitySchemes["bearerAuth"]}},
+	},`,
+	} {
+		if !strings.Contains(string(security), want) {
+			t.Errorf("Expected security.go to contain %s, got:\n%s", want, security)
+		}
+	}
+	if strings.Contains(string(security), `"Health"`) {
+		t.Errorf("Expected the health operation to be public, got:\n%s", security)
+	}
+
+	serverTest := `package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"test/module/generated/auth"
+)
+
+type userKey struct{}
+
+type petHandlers struct{}
+
+func (petHandlers) ListPets(w http.ResponseWriter, r *http.Request) {
+	user, _ := r.Context().Value(userKey{}).(string)
+	w.Header().Set("X-User", user)
+}
+
+func (petHandlers) CreatePet(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (petHandlers) Health(w http.ResponseWriter, r *http.Request) {}
+
+// tokens maps bearer tokens to users and their scopes
+var tokens = map[string][]string{"alice": {"admin"}, "bob": nil}
+
+var authenticator = auth.AuthenticatorFunc(func(r *http.Request, scheme *auth.Scheme, scopes []string) (*http.Request, error) {
+	credential, ok := auth.Credential(r, scheme)
+	if !ok {
+		return nil, auth.ErrUnauthenticated
+	}
+	if scheme.Type == "apiKey" {
+		if credential != "secret" {
+			return nil, auth.ErrUnauthenticated
+		}
+		return r, nil
+	}
+	granted, ok := tokens[credential]
+	if !ok {
+		return nil, auth.ErrUnauthenticated
+	}
+	for _, scope := range scopes {
+		if len(granted) == 0 || granted[0] != scope {
+			return nil, auth.ErrForbidden
+		}
+	}
+	return r.WithContext(context.WithValue(r.Context(), userKey{}, credential)), nil
+})
+
+func TestSecurity(t *testing.T) {
+	srv := NewServer(petHandlers{}, WithAuthenticator(authenticator))
+
+	tests := []struct {
+		method, path string
+		header       map[string]string
+		status       int
+		user         string
+	}{
+		{"GET", "/health", nil, http.StatusOK, ""},
+		{"GET", "/pets", nil, http.StatusUnauthorized, ""},
+		{"GET", "/pets", map[string]string{"Authorization": "Bearer nobody"}, http.StatusUnauthorized, ""},
+		{"GET", "/pets", map[string]string{"Authorization": "Bearer bob"}, http.StatusOK, "bob"},
+		{"POST", "/pets", map[string]string{"Authorization": "Bearer bob"}, http.StatusForbidden, ""},
+		{"POST", "/pets", map[string]string{"Authorization": "Bearer alice"}, http.StatusCreated, ""},
+		{"POST", "/pets", map[string]string{"X-API-Key": "secret"}, http.StatusCreated, ""},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, tt.path, strings.NewReader("{\"name\": \"Rex\"}"))
+		for key, value := range tt.header {
+			r.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		srv.GetRouter().ServeHTTP(w, r)
+		if w.Code != tt.status || w.Header().Get("X-User") != tt.user {
+			t.Errorf("%s %s %v = %d %q, want %d %q", tt.method, tt.path, tt.header, w.Code, w.Header().Get("X-User"), tt.status, tt.user)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	NewServer(petHandlers{}).GetRouter().ServeHTTP(w, httptest.NewRequest("GET", "/pets", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected secured operations to fail closed without an Authenticator, got %d", w.Code)
+	}
+
+	// Requests are authenticated before they are validated
+	validating := NewServer(petHandlers{}, WithAuthenticator(authenticator), WithRequestValidation())
+	validating.registerRoutes()
+	for _, tt := range []struct {
+		header map[string]string
+		status int
+	}{
+		{nil, http.StatusUnauthorized},
+		{map[string]string{"X-API-Key": "secret"}, http.StatusUnprocessableEntity},
+	} {
+		r := httptest.NewRequest("POST", "/pets", strings.NewReader("{}"))
+		r.Header.Set("Content-Type", "application/json")
+		for key, value := range tt.header {
+			r.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		validating.server.Handler.ServeHTTP(w, r)
+		if w.Code != tt.status {
+			t.Errorf("POST /pets %v with an invalid body = %d, want %d", tt.header, w.Code, tt.status)
+		}
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "generated", "server", "server_test.go"), []byte(serverTest), 0600); err != nil {
+		t.Fatalf("Failed to write server test: %v", err)
+	}
+
+	runGo(t, tempDir, "test", "./generated/server/")
+
+	undefined := []models.SecurityRequirement{{"missing": {}}}
+	spec.Paths["/pets"]["get"] = models.Operation{OperationID: "list_pets", Security: &undefined}
+	err = GenerateCode(spec, Config{OutputDir: t.TempDir(), PackageName: "testapi", ModuleName: testModule})
+	if err == nil || !strings.Contains(err.Error(), `undefined scheme "missing"`) {
+		t.Errorf("Expected an undefined scheme error, got %v", err)
+	}
+}
+
+func TestGeneratedSplitByTag(t *testing.T) {
+	tempDir := t.TempDir()
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/users": {
+				"get": {OperationID: "list_users", Tags: []string{"users"}},
+			},
+			"/users/{id}": {
+				"get": {OperationID: "get_user", Tags: []string{"users"}, Parameters: []models.Parameter{
+					{Name: "id", In: "path", Required: true, Schema: models.Schema{Type: "string"}},
+				}},
+			},
+			"/billing/accounts": {
+				"get": {OperationID: "list_accounts", Tags: []string{"Billing Accounts", "users"}},
+			},
+			"/health": {
+				"get": {OperationID: "health"},
+			},
+		},
+	}
+
+	config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule, Framework: FrameworkNetHTTP, SplitByTag: true}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	interfaces, err := os.ReadFile(filepath.Join(tempDir, "generated", "api", "interfaces.go"))
+	if err != nil {
+		t.Fatalf("Failed to read interfaces.go: %v", err)
+	}
+	for _, want := range []string{
+		"// BillingAccountsHandlers handles the operations tagged Billing Accounts\ntype BillingAccountsHandlers interface {",
+		"type DefaultHandlers interface {",
+		"type UsersHandlers interface {",
+		"type APIHandlers interface {\n\tBillingAccountsHandlers\n\tDefaultHandlers\n\tUsersHandlers\n}",
+		"func NewHandlers(\n\tbillingAccountsHandlers BillingAccountsHandlers,\n\tdefaultHandlers DefaultHandlers,\n\tusersHandlers UsersHandlers,\n) APIHandlers {",
+	} {
+		if !strings.Contains(string(interfaces), want) {
+			t.Errorf("Expected interfaces.go to contain %q, got:\n%s", want, interfaces)
+		}
+	}
+
+	for file, want := range map[string]string{
+		"billing_accounts_handlers.go": "func (h *BillingAccountsHandlers) ListAccounts(",
+		"default_handlers.go":          "func NewDefaultHandlers() api.DefaultHandlers {",
+		"users_handlers.go":            "func (h *UsersHandlers) GetUser(w http.ResponseWriter, r *http.Request, id string) {",
+		"handlers.go":                  "return api.NewHandlers(\n\t\tNewBillingAccountsHandlers(),\n\t\tNewDefaultHandlers(),\n\t\tNewUsersHandlers(),\n\t)",
+	} {
+		content, err := os.ReadFile(filepath.Join(tempDir, "handlers", file))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Expected %s to contain %q, got:\n%s", file, want, content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "handlers", "api.go")); !os.IsNotExist(err) {
+		t.Errorf("Expected no handlers/api.go when splitting by tag, got %v", err)
+	}
+
+	runGo(t, tempDir, "build", "./...")
+
+	// New operations land in a stubs file per tag, new tags in a file of their own
+	spec.Paths["/users/me"] = map[string]models.Operation{
+		"get": {OperationID: "get_me", Tags: []string{"users"}},
+	}
+	spec.Paths["/orders"] = map[string]models.Operation{
+		"get": {OperationID: "list_orders", Tags: []string{"orders"}},
+	}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	stubs, err := os.ReadFile(filepath.Join(tempDir, "handlers", "users_stubs.go"))
+	if err != nil {
+		t.Fatalf("Failed to read users_stubs.go: %v", err)
+	}
+	if !strings.Contains(string(stubs), "func (h *UsersHandlers) GetMe(") || strings.Contains(string(stubs), "ListOrders") {
+		t.Errorf("Expected users_stubs.go to hold only the new users handler, got:\n%s", stubs)
+	}
+	orders, err := os.ReadFile(filepath.Join(tempDir, "handlers", "orders_handlers.go"))
+	if err != nil {
+		t.Fatalf("Failed to read orders_handlers.go: %v", err)
+	}
+	if !strings.Contains(string(orders), "func NewOrdersHandlers() api.OrdersHandlers {") {
+		t.Errorf("Expected orders_handlers.go to declare OrdersHandlers, got:\n%s", orders)
+	}
+
+	// NewAPIHandlers doesn't compile until it passes the new tag's handlers
+	// instead of leaving them nil
+	output := runGoFailing(t, tempDir, "build", "./...")
+	if !strings.Contains(output, "handlers.go") || !strings.Contains(output, "api.NewHandlers") {
+		t.Errorf("Expected the build to fail on the call to api.NewHandlers, got:\n%s", output)
+	}
+	constructorPath := filepath.Join(tempDir, "handlers", "handlers.go")
+	constructor, err := os.ReadFile(constructorPath)
+	if err != nil {
+		t.Fatalf("Failed to read handlers.go: %v", err)
+	}
+	constructor = bytes.Replace(constructor, []byte("NewDefaultHandlers(),"), []byte("NewDefaultHandlers(),\n\t\tNewOrdersHandlers(),"), 1)
+	if err := os.WriteFile(constructorPath, constructor, 0600); err != nil {
+		t.Fatalf("Failed to update handlers.go: %v", err)
+	}
+
+	runGo(t, tempDir, "build", "./...")
+
+	report, err := CheckHandlers(spec, tempDir)
+	if err != nil {
+		t.Fatalf("CheckHandlers failed: %v", err)
+	}
+	if report.HasIssues() {
+		t.Errorf("Expected split handlers to match the spec, got %+v", report)
+	}
+
+	if err := os.Remove(filepath.Join(tempDir, "handlers", "users_stubs.go")); err != nil {
+		t.Fatalf("Failed to remove users_stubs.go: %v", err)
+	}
+	report, err = CheckHandlers(spec, tempDir)
+	if err != nil {
+		t.Fatalf("CheckHandlers failed: %v", err)
+	}
+	if !slices.Equal(report.Missing, []string{"UsersHandlers.GetMe"}) {
+		t.Errorf("Expected UsersHandlers.GetMe to be missing, got %v", report.Missing)
+	}
+}
+
+func TestTagGroups(t *testing.T) {
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/a": {"get": {OperationID: "a", Tags: []string{"user-admin"}}},
+			"/b": {"get": {OperationID: "b", Tags: []string{"user_admin"}}},
+			"/c": {"get": {OperationID: "c", Tags: []string{"a_p_i"}}},
+			"/d": {"get": {OperationID: "d"}},
+		},
+	}
+
+	var got []string
+	for _, group := range tagGroups(spec) {
+		got = append(got, group.Name+" "+group.FileBase()+" "+strings.Join(group.Tags, ",")+" "+strings.Join(group.Handlers, ","))
+	}
+	want := []string{
+		"APITagHandlers api_tag_handlers a_p_i C",
+		"DefaultHandlers default_handlers default D",
+		"UserAdminHandlers user_admin_handlers user-admin,user_admin A,B",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("tagGroups() = %q, want %q", got, want)
+	}
+}
+
+func TestGeneratedMiddleware(t *testing.T) {
+	tempDir := t.TempDir()
+	apiKey := []models.SecurityRequirement{{"apiKey": {}}}
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/users": {
+				"get":  {OperationID: "list_users", Tags: []string{"users"}},
+				"post": {OperationID: "create_user", Tags: []string{"users"}},
+			},
+			"/users/{id}": {
+				"delete": {OperationID: "delete_user", Tags: []string{"users", "admin"}, Security: &apiKey, Parameters: []models.Parameter{
+					{Name: "id", In: "path", Required: true, Schema: models.Schema{Type: "string"}},
+				}},
+			},
+		},
+	}
+	spec.Components.SecuritySchemes = map[string]models.SecurityScheme{
+		"apiKey": {Type: "apiKey", In: "header", Name: "X-API-Key"},
+	}
+
+	config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule, Framework: FrameworkNetHTTP}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	operations, err := os.ReadFile(filepath.Join(tempDir, "generated", "server", "operations.go"))
+	if err != nil {
+		t.Fatalf("Failed to read operations.go: %v", err)
+	}
+	want := `"DeleteUser": {
+		ID:          "delete_user",
+		HandlerName: "DeleteUser",
+		Method:      "DELETE",
+		Path:        "/users/{id}",
+		Tags:        []string{"users", "admin"},
+	},`
+	if !strings.Contains(string(operations), want) {
+		t.Errorf("Expected operations.go to contain %s, got:\n%s", want, operations)
+	}
+
+	serverTest := `package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"test/module/generated/auth"
+)
+
+type userHandlers struct{}
+
+func (userHandlers) ListUsers(w http.ResponseWriter, r *http.Request)  {}
+func (userHandlers) CreateUser(w http.ResponseWriter, r *http.Request) {}
+
+func (userHandlers) DeleteUser(w http.ResponseWriter, r *http.Request, id string) {
+	op, _ := OperationFromContext(r.Context())
+	w.Header().Set("X-Operation", op.ID+" "+op.Path)
+}
+
+var authenticator = auth.AuthenticatorFunc(func(r *http.Request, scheme *auth.Scheme, scopes []string) (*http.Request, error) {
+	if credential, _ := auth.Credential(r, scheme); credential != "secret" {
+		return nil, auth.ErrUnauthenticated
+	}
+	return r, nil
+})
+
+func TestMiddleware(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				op, ok := OperationFromContext(r.Context())
+				if !ok {
+					t.Errorf("%s: no operation in the request context", name)
+				}
+				calls = append(calls, name+":"+op.ID)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	srv := NewServer(userHandlers{}, WithAuthenticator(authenticator))
+	srv.Use(record("all"))
+	srv.UseForTag("admin", record("admin"))
+	srv.UseForTag("users", record("users"))
+	srv.UseForOperation("create_user", record("create"))
+
+	tests := []struct {
+		method, path, key string
+		status            int
+		calls             []string
+	}{
+		{"GET", "/users", "", http.StatusOK, []string{"all:list_users", "users:list_users"}},
+		{"POST", "/users", "", http.StatusOK, []string{"all:create_user", "users:create_user", "create:create_user"}},
+		{"DELETE", "/users/1", "secret", http.StatusOK, []string{"all:delete_user", "users:delete_user", "admin:delete_user"}},
+		// Middleware runs after the security requirements are checked
+		{"DELETE", "/users/1", "", http.StatusUnauthorized, nil},
+	}
+	for _, tt := range tests {
+		calls = nil
+		r := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.key != "" {
+			r.Header.Set("X-API-Key", tt.key)
+		}
+		w := httptest.NewRecorder()
+		srv.GetRouter().ServeHTTP(w, r)
+		if w.Code != tt.status || !slices.Equal(calls, tt.calls) {
+			t.Errorf("%s %s = %d %v, want %d %v", tt.method, tt.path, w.Code, calls, tt.status, tt.calls)
+		}
+	}
+
+	r := httptest.NewRequest("DELETE", "/users/1", nil)
+	r.Header.Set("X-API-Key", "secret")
+	w := httptest.NewRecorder()
+	srv.GetRouter().ServeHTTP(w, r)
+	if got := w.Header().Get("X-Operation"); got != "delete_user /users/{id}" {
+		t.Errorf("handler read operation %q from the context, want %q", got, "delete_user /users/{id}")
+	}
+
+	for name, use := range map[string]func(){
+		"after routing":     func() { srv.Use(record("late")) },
+		"unknown tag":       func() { NewServer(userHandlers{}).UseForTag("billing", record("billing")) },
+		"unknown operation": func() { NewServer(userHandlers{}).UseForOperation("get_user", record("get")) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected a panic", name)
+				}
+			}()
+			use()
+		}()
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "generated", "server", "server_test.go"), []byte(serverTest), 0600); err != nil {
+		t.Fatalf("Failed to write server test: %v", err)
+	}
+
+	runGo(t, tempDir, "test", "./generated/server/")
+}
+
+func TestGeneratedServerOptions(t *testing.T) {
+	tempDir := t.TempDir()
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/pets": {
+				"get": {OperationID: "list_pets"},
+			},
+		},
+	}
+
+	config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule, Framework: FrameworkNetHTTP}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	serverTest := `package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type petHandlers struct{}
+
+func (petHandlers) ListPets(w http.ResponseWriter, r *http.Request) {
+	_, _ = io.WriteString(w, "pets")
+}
+
+func TestServerOptions(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var logs bytes.Buffer
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {})
+	srv := NewServer(petHandlers{},
+		WithRouter(mux),
+		WithListener(l),
+		WithBasePath("/v2/"),
+		WithLogger(log.New(&logs, "", 0)),
+		WithReadTimeout(5*time.Second),
+		WithWriteTimeout(6*time.Second),
+		WithIdleTimeout(7*time.Second),
+		WithMaxHeaderBytes(4096),
+	)
+	if srv.GetRouter() != mux {
+		t.Errorf("expected the routes on the router given with WithRouter")
+	}
+	if srv.server.ReadTimeout != 5*time.Second || srv.server.WriteTimeout != 6*time.Second ||
+		srv.server.IdleTimeout != 7*time.Second || srv.server.MaxHeaderBytes != 4096 {
+		t.Errorf("unexpected server settings: %+v", srv.server)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- srv.Start("ignored:0") }()
+
+	base := "http://" + l.Addr().String()
+	for path, want := range map[string]int{
+		"/v2/pets":    http.StatusOK,
+		"/v2/healthz": http.StatusOK,
+		"/pets":       http.StatusNotFound,
+		"/v2pets":     http.StatusNotFound,
+	} {
+		resp, err := http.Get(base + path)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("GET %s = %d, want %d", path, resp.StatusCode, want)
+		}
+	}
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != http.ErrServerClosed {
+		t.Errorf("Start returned %v, want http.ErrServerClosed", err)
+	}
+	if !strings.Contains(logs.String(), "\"/v2/pets\"") {
+		t.Errorf("expected requests to be logged to the logger, got %q", logs.String())
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "generated", "server", "server_test.go"), []byte(serverTest), 0600); err != nil {
+		t.Fatalf("Failed to write server test: %v", err)
+	}
+
+	runGo(t, tempDir, "test", "./generated/server/")
+}
+
+func TestResolveServer(t *testing.T) {
+	spec := &models.OpenAPISpec{
+		Servers: []models.Server{
+			{
+				URL:         "https://{region}.api.example.com/{version}/",
+				Description: "Production",
+				Variables: map[string]models.ServerVariable{
+					"region":  {Default: "eu", Enum: []string{"eu", "us"}},
+					"version": {Default: "v2"},
+				},
+			},
+			{URL: "/internal/api", Description: "Internal"},
+			{URL: "http://localhost:8080"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		selector string
+		vars     map[string]string
+		want     serverInfo
+		baseURL  string
+		wantErr  string
+	}{
+		{
+			name:    "FirstByDefault",
+			want:    serverInfo{URL: "https://eu.api.example.com/v2/", BasePath: "/v2"},
+			baseURL: "https://eu.api.example.com/v2",
+		},
+		{
+			name:    "Variables",
+			vars:    map[string]string{"region": "us", "version": "v3"},
+			want:    serverInfo{URL: "https://us.api.example.com/v3/", BasePath: "/v3"},
+			baseURL: "https://us.api.example.com/v3",
+		},
+		{
+			name:     "ByDescription",
+			selector: "Internal",
+			want:     serverInfo{URL: "/internal/api", BasePath: "/internal/api"},
+		},
+		{
+			name:     "ByIndex",
+			selector: "2",
+			want:     serverInfo{URL: "http://localhost:8080"},
+			baseURL:  "http://localhost:8080",
+		},
+		{name: "IndexOutOfRange", selector: "3", wantErr: "out of range"},
+		{name: "UnknownServer", selector: "Staging", wantErr: `no server has the URL or description "Staging"`},
+		{name: "ValueNotInEnum", vars: map[string]string{"region": "ap"}, wantErr: "want one of eu, us"},
+		{name: "UnknownVariable", vars: map[string]string{"port": "443"}, wantErr: `has no variable "port"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveServer(spec, tt.selector, tt.vars)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveServer failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+			if got.BaseURL() != tt.baseURL {
+				t.Errorf("Expected base URL %q, got %q", tt.baseURL, got.BaseURL())
+			}
+		})
+	}
+
+	t.Run("UndeclaredVariable", func(t *testing.T) {
+		spec := &models.OpenAPISpec{Servers: []models.Server{{URL: "https://{tenant}.example.com"}}}
+		if _, err := resolveServer(spec, "", nil); err == nil || !strings.Contains(err.Error(), `variable "tenant" without declaring it`) {
+			t.Errorf("Expected an undeclared variable error, got %v", err)
+		}
+	})
+
+	t.Run("NoServers", func(t *testing.T) {
+		got, err := resolveServer(&models.OpenAPISpec{}, "", nil)
+		if err != nil || got != (serverInfo{}) {
+			t.Errorf("Expected the root without servers, got %+v, %v", got, err)
+		}
+		if _, err := resolveServer(&models.OpenAPISpec{}, "0", nil); err == nil {
+			t.Error("Expected an error selecting a server the spec doesn't declare")
+		}
+	})
+}
+
+func TestGeneratedServerBasePath(t *testing.T) {
+	tempDir := t.TempDir()
+	spec := &models.OpenAPISpec{
+		Servers: []models.Server{
+			{
+				URL: "https://api.example.com/{version}",
+				Variables: map[string]models.ServerVariable{
+					"version": {Default: "v2"},
+				},
+			},
+		},
+		Paths: map[string]map[string]models.Operation{
+			"/pets": {
+				"get": {OperationID: "list_pets"},
+			},
+		},
+	}
+
+	config := Config{
+		OutputDir:       tempDir,
+		PackageName:     "testapi",
+		ModuleName:      testModule,
+		Framework:       FrameworkNetHTTP,
+		ServerVariables: map[string]string{"version": "v3"},
+	}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	operations, err := os.ReadFile(filepath.Join(tempDir, "generated", "server", "operations.go"))
+	if err != nil {
+		t.Fatalf("Failed to read operations.go: %v", err)
+	}
+	if !strings.Contains(string(operations), `const BasePath = "/v3"`) {
+		t.Errorf("Expected BasePath from the server URL, got:\n%s", operations)
+	}
+
+	client, err := os.ReadFile(filepath.Join(tempDir, "generated", "client", "client.go"))
+	if err != nil {
+		t.Fatalf("Failed to read client.go: %v", err)
+	}
+	if !strings.Contains(string(client), `const DefaultBaseURL = "https://api.example.com/v3"`) {
+		t.Errorf("Expected DefaultBaseURL from the server URL, got:\n%s", client)
+	}
+
+	serverTest := `package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type petHandlers struct{}
+
+func (petHandlers) ListPets(w http.ResponseWriter, r *http.Request) {
+	_, _ = io.WriteString(w, "pets")
+}
+
+func TestBasePath(t *testing.T) {
+	for _, tt := range []struct {
+		opts []Option
+		path string
+		want int
+	}{
+		{nil, "/v3/pets", http.StatusOK},
+		{nil, "/pets", http.StatusNotFound},
+		{[]Option{WithBasePath("/")}, "/pets", http.StatusOK},
+		{[]Option{WithBasePath("/api")}, "/api/pets", http.StatusOK},
+		{[]Option{WithBasePath("/api")}, "/v3/pets", http.StatusNotFound},
+	} {
+		srv := NewServer(petHandlers{}, tt.opts...)
+		srv.registerRoutes()
+		rec := httptest.NewRecorder()
+		srv.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rec.Code != tt.want {
+			t.Errorf("GET %s with %d options = %d, want %d", tt.path, len(tt.opts), rec.Code, tt.want)
+		}
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "generated", "server", "server_test.go"), []byte(serverTest), 0600); err != nil {
+		t.Fatalf("Failed to write server test: %v", err)
+	}
+
+	clientTest := `package client
+
+import "testing"
+
+func TestDefaultBaseURL(t *testing.T) {
+	if c := NewClient(""); c.baseURL != DefaultBaseURL {
+		t.Errorf("NewClient(\"\") calls %q, want %q", c.baseURL, DefaultBaseURL)
+	}
+	if c := NewClient("http://localhost:8080/"); c.baseURL != "http://localhost:8080" {
+		t.Errorf("unexpected base URL %q", c.baseURL)
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "generated", "client", "client_test.go"), []byte(clientTest), 0600); err != nil {
+		t.Fatalf("Failed to write client test: %v", err)
+	}
+
+	runGo(t, tempDir, "test", "./generated/server/", "./generated/client/")
+}
+
+func TestGeneratedServe(t *testing.T) {
+	tempDir := t.TempDir()
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/pets": {
+				"get": {OperationID: "list_pets"},
+			},
+		},
+	}
+
+	config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule, Framework: FrameworkNetHTTP}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	for _, name := range []string{"serve.go", "h2c.go", "h2c_fallback.go"} {
+		if _, err := os.Stat(filepath.Join(tempDir, "generated", "server", name)); err != nil {
+			t.Errorf("Expected generated/server/%s: %v", name, err)
+		}
+	}
+
+	serverTest := `//go:build go1.24
+
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type petHandlers struct{}
+
+func (petHandlers) ListPets(w http.ResponseWriter, r *http.Request) {
+	_, _ = io.WriteString(w, r.Proto)
+}
+
+// writeCert writes a self-signed certificate for 127.0.0.1 with serial to
+// certFile and keyFile, and returns it
+func writeCert(t *testing.T, certFile, keyFile string, serial int64) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// get requests /pets through transport and returns the response body and
+// the serial of the server certificate, if any
+func get(t *testing.T, transport *http.Transport, url string) (string, int64) {
+	t.Helper()
+	resp, err := (&http.Client{Transport: transport}).Get(url + "/pets")
+	if err != nil {
+		t.Fatalf("GET %s/pets: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	var serial int64
+	if resp.TLS != nil {
+		serial = resp.TLS.PeerCertificates[0].SerialNumber.Int64()
+	}
+	return string(body), serial
+}
+
+func shutdown(t *testing.T, srv *Server, done chan error) {
+	t.Helper()
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != http.ErrServerClosed {
+		t.Errorf("server returned %v, want http.ErrServerClosed", err)
+	}
+}
+
+func TestStartTLSReloadsCertificates(t *testing.T) {
+	defer func(interval time.Duration) { certCheckInterval = interval }(certCheckInterval)
+	certCheckInterval = 0
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	first := writeCert(t, certFile, keyFile, 1)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(petHandlers{}, WithListener(l), WithLogger(log.New(io.Discard, "", 0)))
+	done := make(chan error, 1)
+	go func() { done <- srv.StartTLS("", certFile, keyFile) }()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(first)
+	transport := &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}, ForceAttemptHTTP2: true}
+	url := "https://" + l.Addr().String()
+	if proto, serial := get(t, transport, url); proto != "HTTP/2.0" || serial != 1 {
+		t.Errorf("got %s with certificate %d, want HTTP/2.0 with certificate 1", proto, serial)
+	}
+
+	second := writeCert(t, certFile, keyFile, 2)
+	roots.AddCert(second)
+	transport.CloseIdleConnections()
+	if _, serial := get(t, transport, url); serial != 2 {
+		t.Errorf("got certificate %d after renewal, want 2", serial)
+	}
+
+	// A broken file keeps the previous certificate
+	if err := os.WriteFile(keyFile, []byte("not a key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	transport.CloseIdleConnections()
+	if _, serial := get(t, transport, url); serial != 2 {
+		t.Errorf("got certificate %d with a broken key file, want 2", serial)
+	}
+
+	shutdown(t, srv, done)
+}
+
+func TestStartTLSMissingCertificate(t *testing.T) {
+	srv := NewServer(petHandlers{})
+	if err := srv.StartTLS("127.0.0.1:0", "missing.pem", "missing-key.pem"); err == nil {
+		t.Error("expected an error for missing certificate files")
+	}
+}
+
+func TestCertificateChecksThrottled(t *testing.T) {
+	defer func(interval time.Duration) { certCheckInterval = interval }(certCheckInterval)
+	certCheckInterval = time.Hour
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeCert(t, certFile, keyFile, 1)
+	config, err := tlsConfig(certFile, keyFile, log.New(io.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial := func() int64 {
+		cert, err := config.GetCertificate(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return leaf.SerialNumber.Int64()
+	}
+
+	writeCert(t, certFile, keyFile, 2)
+	if got := serial(); got != 1 {
+		t.Errorf("got certificate %d before the next check, want 1", got)
+	}
+	certCheckInterval = 0
+	if got := serial(); got != 2 {
+		t.Errorf("got certificate %d after the next check, want 2", got)
+	}
+}
+
+func TestH2C(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(petHandlers{}, WithH2C())
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(l) }()
+
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	transport := &http.Transport{Protocols: protocols}
+	if proto, _ := get(t, transport, "http://"+l.Addr().String()); proto != "HTTP/2.0" {
+		t.Errorf("got %s, want HTTP/2.0", proto)
+	}
+	if proto, _ := get(t, &http.Transport{}, "http://"+l.Addr().String()); proto != "HTTP/1.1" {
+		t.Errorf("got %s, want HTTP/1.1", proto)
+	}
+
+	shutdown(t, srv, done)
+}
+
+func TestServeUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "api.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(petHandlers{})
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(l) }()
+
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}
+	if proto, _ := get(t, transport, "http://api"); proto != "HTTP/1.1" {
+		t.Errorf("got %s, want HTTP/1.1", proto)
+	}
+
+	shutdown(t, srv, done)
+}
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "generated", "server", "server_test.go"), []byte(serverTest), 0600); err != nil {
+		t.Fatalf("Failed to write server test: %v", err)
+	}
+
+	runGo(t, tempDir, "test", "./generated/server/")
+
+	// The toolchain running the tests is too recent for the h2c fallback, so
+	// test it on its own without its build constraint
+	fallback, err := os.ReadFile(filepath.Join(tempDir, "generated", "server", "h2c_fallback.go"))
+	if err != nil {
+		t.Fatalf("Failed to read h2c_fallback.go: %v", err)
+	}
+	fallbackDir := filepath.Join(tempDir, "h2cfallback")
+	if err := os.MkdirAll(fallbackDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	source := strings.Replace(string(fallback), "//go:build !go1.24", "", 1)
+	if err := os.WriteFile(filepath.Join(fallbackDir, "h2c.go"), []byte(source), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// The test's client needs http.Protocols
+	fallbackTest := `//go:build go1.24
+
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestH2CFallback(t *testing.T) {
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	})}
+	enableH2C(srv)
+	ts := httptest.NewServer(srv.Handler)
+	defer ts.Close()
+
+	protocols := new(http.Protocols)
+	protocols.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if proto, _ := io.ReadAll(resp.Body); string(proto) != "HTTP/2.0" {
+		t.Errorf("got %s, want HTTP/2.0", proto)
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(fallbackDir, "h2c_test.go"), []byte(fallbackTest), 0600); err != nil {
+		t.Fatal(err)
+	}
+	runGo(t, tempDir, "test", "./h2cfallback/")
+}
+
+func TestGeneratedContract(t *testing.T) {
+	tempDir := t.TempDir()
+	pet := map[string]models.MediaType{
+		"application/json": {Schema: models.Schema{Ref: "#/components/schemas/Pet"}},
+	}
+	spec := &models.OpenAPISpec{
+		Servers: []models.Server{{URL: "/v1"}},
+		Paths: map[string]map[string]models.Operation{
+			"/pets/{id}": {
+				"get": {
+					OperationID: "get_pet",
+					Parameters: []models.Parameter{
+						{Name: "id", In: "path", Required: true, Style: "label", Schema: models.Schema{Type: "string", Example: "rex 1"}},
+						{Name: "tags", In: "query", Required: true, Schema: models.Schema{Type: "array", Example: []interface{}{"a", "b"}, Items: &models.Schema{Type: "string"}}},
+						{Name: "X-Tenant", In: "header", Required: true, Schema: models.Schema{Type: "string", Example: "acme"}},
+						{Name: "debug", In: "query", Schema: models.Schema{Type: "boolean"}},
+					},
+					Responses: map[string]models.Response{"200": {Content: pet}},
+				},
+			},
+			"/pets": {
+				"post": {
+					OperationID: "create_pet",
+					RequestBody: &models.RequestBody{Required: true, Content: pet},
+					Responses:   map[string]models.Response{"201": {Content: pet}},
+				},
+			},
+			"/search": {
+				"get": {
+					OperationID: "search_pets",
+					Parameters: []models.Parameter{
+						{Name: "filter", In: "query", Required: true, Style: "deepObject", Schema: models.Schema{Type: "object", Properties: map[string]models.Schema{"name": {Type: "string"}}}},
+					},
+					Responses: map[string]models.Response{"200": {}},
+				},
+			},
+			"/admin": {
+				"get": {
+					OperationID: "get_admin",
+					Security:    &[]models.SecurityRequirement{{"bearerAuth": {}}},
+					Responses:   map[string]models.Response{"200": {Content: pet}},
+				},
+			},
+			"/status": {
+				"get": {
+					OperationID: "get_status",
+					Responses: map[string]models.Response{"503": {Content: map[string]models.MediaType{
+						"application/json": {Schema: models.Schema{
+							Type:       "object",
+							Required:   []string{"error"},
+							Properties: map[string]models.Schema{"error": {Type: "string"}},
+						}},
+					}}},
+				},
+			},
+		},
+	}
+	spec.Components.SecuritySchemes = map[string]models.SecurityScheme{
+		"bearerAuth": {Type: "http", Scheme: "bearer"},
+	}
+	spec.Components.Schemas = map[string]models.Schema{
+		"Pet": {
+			Type:       "object",
+			Required:   []string{"name"},
+			Properties: map[string]models.Schema{"name": {Type: "string", Example: "Rex"}},
+		},
+	}
+
+	config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule, Framework: FrameworkNetHTTP}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	casesTest := `package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"` + testModule + `/generated/contract"
+)
+
+func TestCases(t *testing.T) {
+	want := []contract.Case{
+		{
+			Operation: "GetAdmin",
+			Method:    "GET",
+			Path:      "/admin",
+			Secured:   true,
+		},
+		{
+			Operation:   "CreatePet",
+			Method:      "POST",
+			Path:        "/pets",
+			ContentType: "application/json",
+			Body:        ` + "`" + `{"name":"Rex"}` + "`" + `,
+		},
+		{
+			Operation: "GetPet",
+			Method:    "GET",
+			Path:      "/pets/.rex%201",
+			Query:     "tags=a&tags=b",
+			Header:    http.Header{"X-Tenant": {"acme"}},
+		},
+		{
+			Operation: "SearchPets",
+			Method:    "GET",
+			Path:      "/search",
+			Skip:      "no example of the query parameter filter can be sent",
+		},
+		{
+			Operation: "GetStatus",
+			Method:    "GET",
+			Path:      "/status",
+		},
+	}
+	if !reflect.DeepEqual(contract.Cases, want) {
+		t.Errorf("Expected cases %#v, got %#v", want, contract.Cases)
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "handlers", "cases_test.go"), []byte(casesTest), 0600); err != nil {
+		t.Fatalf("Failed to write cases_test.go: %v", err)
+	}
+
+	// The scaffolded TestContract runs the stubs, which answer with the
+	// examples, against the spec. The stub of get_status answers with its
+	// 503, and get_admin, secured without an authenticator, is skipped.
+	runGo(t, tempDir, "test", "./handlers")
+
+	// Regenerating keeps the user's contract test
+	custom := []byte("package handlers\n")
+	if err := os.WriteFile(filepath.Join(tempDir, "handlers", "contract_test.go"), custom, 0600); err != nil {
+		t.Fatalf("Failed to write contract_test.go: %v", err)
+	}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+	if content, _ := os.ReadFile(filepath.Join(tempDir, "handlers", "contract_test.go")); string(content) != string(custom) {
+		t.Errorf("Expected contract_test.go to be kept, got:\n%s", content)
+	}
+}
+
+func TestGeneratedAPITest(t *testing.T) {
+	tempDir := t.TempDir()
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/pets/{id}": {
+				"get": {
+					OperationID: "get_pet",
+					Parameters:  []models.Parameter{{Name: "id", In: "path", Required: true, Schema: models.Schema{Type: "string"}}},
+					Responses: map[string]models.Response{"200": {Content: map[string]models.MediaType{
+						"application/json": {Schema: models.Schema{Ref: "#/components/schemas/Pet"}},
+					}}},
+				},
+				"put": {
+					OperationID: "update_pet",
+					Parameters: []models.Parameter{
+						{Name: "id", In: "path", Required: true, Schema: models.Schema{Type: "string"}},
+						{Name: "force", In: "query", Schema: models.Schema{Type: "boolean"}},
+					},
+					RequestBody: &models.RequestBody{Content: map[string]models.MediaType{
+						"application/json": {Schema: models.Schema{Ref: "#/components/schemas/Pet"}},
+					}},
+					Responses: map[string]models.Response{"204": {}},
+				},
+				"delete": {
+					OperationID: "delete_pet",
+					Parameters:  []models.Parameter{{Name: "id", In: "path", Required: true, Schema: models.Schema{Type: "string"}}},
+					Responses:   map[string]models.Response{"204": {}},
+				},
+			},
+		},
+	}
+	spec.Components.Schemas = map[string]models.Schema{
+		"Pet": {Type: "object", Properties: map[string]models.Schema{"name": {Type: "string"}}},
+	}
+
+	config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule, Framework: FrameworkNetHTTP}
+	if err := GenerateCode(spec, config); err != nil {
+		t.Fatalf("GenerateCode failed: %v", err)
+	}
+
+	apitestTest := `package apitest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"` + testModule + `/generated/apitest"
+	"` + testModule + `/generated/client"
+	"` + testModule + `/generated/models"
+	"` + testModule + `/generated/server"
+)
+
+func TestFakeHandlers(t *testing.T) {
+	fake := &apitest.FakeHandlers{}
+	fake.DeletePetFunc = func(w http.ResponseWriter, r *http.Request, id string) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+	router := server.NewServer(fake).GetRouter()
+
+	for path, method := range map[string]string{"/pets/7": http.MethodDelete, "/pets/8": http.MethodGet} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		want := http.StatusNoContent
+		if method == http.MethodGet {
+			want = http.StatusNotImplemented
+		}
+		if rec.Code != want {
+			t.Errorf("%s %s: expected %d, got %d", method, path, want, rec.Code)
+		}
+	}
+
+	fake.AssertCalled(t, "DeletePet", "7")
+	fake.AssertCalled(t, "GetPet", "8")
+	fake.AssertCalledTimes(t, "GetPet", 1)
+	if calls := fake.Calls(""); len(calls) != 2 {
+		t.Errorf("Expected 2 calls, got %v", calls)
+	}
+	fake.Reset()
+	fake.AssertNotCalled(t, "GetPet")
+}
+
+func TestFakeClient(t *testing.T) {
+	var api client.API = &apitest.FakeClient{
+		GetPetFunc: func(ctx context.Context, id string) (*models.Pet, error) {
+			return &models.Pet{Name: "Rex"}, nil
+		},
+	}
+
+	pet, err := api.GetPet(context.Background(), "7")
+	if err != nil || pet == nil || pet.Name != "Rex" {
+		t.Errorf("Expected the pet from GetPetFunc, got %v, %v", pet, err)
+	}
+
+	var apiErr *client.APIError
+	if err := api.DeletePet(context.Background(), "7"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotImplemented {
+		t.Errorf("Expected a 501 APIError, got %v", err)
+	}
+
+	force := true
+	params := &client.UpdatePetParams{Force: &force}
+	if err := api.UpdatePet(context.Background(), "7", params, models.Pet{Name: "Rex"}); !errors.As(err, &apiErr) {
+		t.Errorf("Expected a 501 APIError, got %v", err)
+	}
+
+	fake := api.(*apitest.FakeClient)
+	fake.AssertCalled(t, "GetPet", "7")
+	fake.AssertCalled(t, "DeletePet", "7")
+	fake.AssertCalled(t, "UpdatePet", "7", params, models.Pet{Name: "Rex"})
+}
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "generated", "apitest", "apitest_test.go"), []byte(apitestTest), 0600); err != nil {
+		t.Fatalf("Failed to write apitest_test.go: %v", err)
+	}
+
+	runGo(t, tempDir, "test", "./generated/apitest")
+}
+
+func TestGeneratedPathParamTypes(t *testing.T) {
+	spec := &models.OpenAPISpec{
+		Paths: map[string]map[string]models.Operation{
+			"/pets/{petId}/visits/{day}": {
+				"get": {
+					OperationID: "get_visit",
+					Parameters: []models.Parameter{
+						{Name: "petId", In: "path", Required: true, Schema: models.Schema{Type: "integer", Format: "int64"}},
+						{Name: "day", In: "path", Required: true, Schema: models.Schema{Type: "string", Format: "date"}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, framework := range []string{FrameworkGin, FrameworkNetHTTP} {
+		t.Run(framework, func(t *testing.T) {
+			tempDir := t.TempDir()
+			config := Config{OutputDir: tempDir, PackageName: "testapi", ModuleName: testModule, Framework: framework}
+			if err := GenerateCode(spec, config); err != nil {
+				t.Fatalf("GenerateCode failed: %v", err)
+			}
+			if framework != FrameworkNetHTTP {
+				runGo(t, tempDir, "vet", "./...")
+				return
+			}
+
+			serverTest := `package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type visitHandlers struct{}
+
+func (visitHandlers) GetVisit(w http.ResponseWriter, r *http.Request, petId int64, day time.Time) {
+	fmt.Fprintf(w, "%d %s", petId, day.Format(time.DateOnly))
+}
+
+func TestPathParamTypes(t *testing.T) {
+	router := NewServer(visitHandlers{}).GetRouter()
+	for path, want := range map[string]int{
+		"/pets/42/visits/2024-05-01":           http.StatusOK,
+		"/pets/42/visits/2024-05-01T10:00:00Z": http.StatusOK,
+		"/pets/rex/visits/2024-05-01":          http.StatusBadRequest,
+		"/pets/42/visits/tuesday":              http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("GET %s = %d, want %d: %s", path, rec.Code, want, rec.Body)
+		}
+		if want == http.StatusOK && rec.Body.String() != "42 2024-05-01" {
+			t.Errorf("GET %s: handler got %q", path, rec.Body)
+		}
+	}
+}
+`
+			if err := os.WriteFile(filepath.Join(tempDir, "generated", "server", "params_test.go"), []byte(serverTest), 0600); err != nil {
+				t.Fatalf("Failed to write server test: %v", err)
+			}
+			runGo(t, tempDir, "test", "./...")
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package generator
+
+import (
+	"bytes"
+	"embed"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// stdImports maps the standard library package names referenced by generated
+// code to their import paths
+var stdImports = map[string]string{
+	"bytes":    "bytes",
+	"context":  "context",
+	"tls":      "crypto/tls",
+	"errors":   "errors",
+	"fmt":      "fmt",
+	"io":       "io",
+	"iter":     "iter",
+	"json":     "encoding/json",
+	"log":      "log",
+	"net":      "net",
+	"http":     "net/http",
+	"url":      "net/url",
+	"os":       "os",
+	"reflect":  "reflect",
+	"signal":   "os/signal",
+	"strconv":  "strconv",
+	"strings":  "strings",
+	"sync":     "sync",
+	"atomic":   "sync/atomic",
+	"syscall":  "syscall",
+	"testing":  "testing",
+	"time":     "time",
+	"httptest": "net/http/httptest",
+}
+
+// knownImports returns every package name the generator knows how to import,
+// including the generated packages of the user's module
+func knownImports(moduleName string) map[string]string {
+	known := make(map[string]string, len(stdImports)+8)
+	for name, importPath := range stdImports {
+		known[name] = importPath
+	}
+	known["gin"] = "github.com/gin-gonic/gin"
+	known["chi"] = "github.com/go-chi/chi/v5"
+	known["echo"] = "github.com/labstack/echo/v4"
+	known["h2c"] = "golang.org/x/net/http2/h2c"
+	known["http2"] = "golang.org/x/net/http2"
+	if moduleName != "" {
+		known["api"] = moduleName + "/generated/api"
+		known["models"] = moduleName + "/generated/models"
+		known["server"] = moduleName + "/generated/server"
+		known["client"] = moduleName + "/generated/client"
+		known["validate"] = moduleName + "/generated/validate"
+		known["auth"] = moduleName + "/generated/auth"
+		known["contract"] = moduleName + "/generated/contract"
+		known["handlers"] = moduleName + "/handlers"
+	}
+	return known
+}
+
+// writeGoFile executes tmpl with data and writes the gofmt-formatted result to
+// filename with exactly the imports the generated source references
+func writeGoFile(filename string, tmpl *template.Template, data interface{}, moduleName string) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	src, err := fixImports(filename, buf.Bytes(), moduleName)
+	if err != nil {
+		return err
+	}
+
+	src, err = formatGoSource(filename, src)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, src, 0600)
+}
+
+// writeRuntimeFiles copies the source files of a runtime package, such as
+// pkg/validate, into dir of a generated project
+func writeRuntimeFiles(files embed.FS, dir string) error {
+	entries, err := files.ReadDir(".")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		src, err := files.ReadFile(entry.Name())
+		if err != nil {
+			return err
+		}
+		src = append([]byte("// Code generated by gopenapi. DO NOT EDIT.\n\n"), src...)
+		if err := os.WriteFile(filepath.Join(dir, entry.Name()), src, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fixImports rewrites the import declarations of src so that unused imports
+// are dropped and referenced packages known to the generator are added
+func fixImports(filename string, src []byte, moduleName string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, templateError(filename, src, err)
+	}
+
+	used := usedPackageNames(file)
+	known := knownImports(moduleName)
+
+	// Keep the imports that are still referenced
+	imports := map[string]string{} // import path -> explicit name
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return nil, err
+		}
+		name := ""
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		switch {
+		case name == "_" || name == ".":
+			imports[importPath] = name
+		case name != "" && used[name]:
+			imports[importPath] = name
+		case name == "" && used[importName(importPath)]:
+			imports[importPath] = ""
+		}
+		if name == "" {
+			name = importName(importPath)
+		}
+		delete(used, name)
+	}
+
+	// Add the missing imports the generator knows about
+	for name := range used {
+		if importPath, ok := known[name]; ok {
+			if importName(importPath) == name {
+				imports[importPath] = ""
+			} else {
+				imports[importPath] = name
+			}
+		}
+	}
+
+	block := formatImportBlock(imports, moduleName)
+
+	// Splice the new block in place of the existing import declarations
+	start, end := -1, -1
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		if start < 0 {
+			start = fset.Position(gen.Pos()).Offset
+		}
+		end = fset.Position(gen.End()).Offset
+	}
+	if start < 0 {
+		start = fset.Position(file.Name.End()).Offset
+		end = start
+		if block != "" {
+			block = "\n\n" + block
+		}
+	}
+
+	var out bytes.Buffer
+	out.Write(src[:start])
+	out.WriteString(block)
+	out.Write(src[end:])
+	return out.Bytes(), nil
+}
+
+// importName returns the name a package imported without an explicit name is
+// referenced by, assuming it matches the last path element that isn't a major
+// version suffix like /v5
+func importName(importPath string) string {
+	name := path.Base(importPath)
+	if len(name) > 1 && name[0] == 'v' && path.Dir(importPath) != "." {
+		if _, err := strconv.Atoi(name[1:]); err == nil {
+			return path.Base(path.Dir(importPath))
+		}
+	}
+	return name
+}
+
+// usedPackageNames collects the identifiers used as package qualifiers in file
+func usedPackageNames(file *ast.File) map[string]bool {
+	unresolved := map[*ast.Ident]bool{}
+	for _, ident := range file.Unresolved {
+		unresolved[ident] = true
+	}
+
+	used := map[string]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok && unresolved[ident] {
+			used[ident.Name] = true
+		}
+		return true
+	})
+	return used
+}
+
+// formatImportBlock renders imports grouped like goimports: standard library
+// first, then third-party and module packages
+func formatImportBlock(imports map[string]string, moduleName string) string {
+	if len(imports) == 0 {
+		return ""
+	}
+
+	var std, other []string
+	for importPath, name := range imports {
+		line := strconv.Quote(importPath)
+		if name != "" {
+			line = name + " " + line
+		}
+		if isStdImport(importPath, moduleName) {
+			std = append(std, line)
+		} else {
+			other = append(other, line)
+		}
+	}
+	sort.Slice(std, func(i, j int) bool { return importSortKey(std[i]) < importSortKey(std[j]) })
+	sort.Slice(other, func(i, j int) bool { return importSortKey(other[i]) < importSortKey(other[j]) })
+
+	var b strings.Builder
+	b.WriteString("import (\n")
+	for _, line := range std {
+		b.WriteString("\t" + line + "\n")
+	}
+	if len(std) > 0 && len(other) > 0 {
+		b.WriteString("\n")
+	}
+	for _, line := range other {
+		b.WriteString("\t" + line + "\n")
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// isStdImport reports whether importPath belongs to the standard library
+func isStdImport(importPath, moduleName string) bool {
+	if moduleName != "" && (importPath == moduleName || strings.HasPrefix(importPath, moduleName+"/")) {
+		return false
+	}
+	return !strings.Contains(strings.SplitN(importPath, "/", 2)[0], ".")
+}
+
+// importSortKey orders import lines by path, ignoring any explicit name
+func importSortKey(line string) string {
+	return line[strings.Index(line, `"`):]
+}
@@ -1,7 +1,6 @@
 package generator
 
 import (
-	"os"
 	"path/filepath"
 	"text/template"
 
@@ -16,7 +15,6 @@ import (
 	"context"
 	"log"
 	"net/http"
-	"os"
 	"os/signal"
 	"syscall"
 	"time"
@@ -77,9 +75,9 @@ func main() {
 	// Start server
 	log.Printf("Starting server on :%s", port)
 	log.Printf("API endpoints:")
-	{{range .Endpoints}}
-	log.Printf("  {{.Method}} {{.Path}} - {{.Description}}")
-	{{end}}
+	{{- range .Endpoints}}
+	log.Printf("  %s %s - %s", {{goString .Method}}, {{goString .Path}}, {{goString .Description}})
+	{{- end}}
 	log.Printf("Press Ctrl+C to stop the server")
 
 	err := httpServer.ListenAndServe()
@@ -122,7 +120,7 @@ func main() {
 		}
 	}
 
-	tmpl, err := template.New("main").Parse(mainTemplate)
+	tmpl, err := template.New("main").Funcs(templateFuncs).Parse(mainTemplate)
 	if err != nil {
 		return err
 	}
@@ -139,11 +137,5 @@ func main() {
 		Endpoints:  endpoints,
 	}
 
-	f, err := os.Create(filepath.Join(baseDir, "main.go"))
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	return tmpl.Execute(f, data)
+	return writeGoFile(filepath.Join(baseDir, "main.go"), tmpl, data, moduleName)
 }
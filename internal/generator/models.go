@@ -9,6 +9,7 @@ import (
 	"github.com/shubhamku044/gopenapi/pkg/utils"
 )
 
+// GenerateModels generates the data models in models/ under baseDir
 func GenerateModels(spec *models.OpenAPISpec, baseDir string) error {
 	// Create models directory if it doesn't exist
 	modelsDir := filepath.Join(baseDir, "models")
@@ -16,25 +17,9 @@ func GenerateModels(spec *models.OpenAPISpec, baseDir string) error {
 		return err
 	}
 
-	needsTimeImport := false
-	for _, schema := range spec.Components.Schemas {
-		if hasTimeFields(schema) {
-			needsTimeImport = true
-			break
-		}
-	}
-
-	imports := ""
-	if needsTimeImport {
-		imports = `import (
-	"time"
-)`
-	}
-
+	// Imports such as "time" are added by writeGoFile from the field types
 	modelsTemplate := `package models
 
-` + imports + `
-
 // This file contains the data models for the API
 // In a real implementation, these would be fully generated from the schema definitions
 
@@ -49,22 +34,23 @@ type ErrorResponse struct {
 	Message string ` + "`json:\"message\"`" + `
 }
 
-{{range $name, $schema := .Schemas}}
-// {{$name}} represents a {{$name}} model
-type {{$name}} struct {
-	{{range $propName, $propSchema := $schema.Properties}}
-	{{toCamelCase $propName}} {{getGoType $propSchema}} ` + "`json:\"{{$propName}}\"`" + `
-	{{end}}
+{{- range $name, $schema := .Schemas}}
+
+// {{goIdent $name}} represents a {{comment $name}} model
+type {{goIdent $name}} struct {
+{{- range $propName, $propSchema := $schema.Properties}}
+	{{toGoIdentifier $propName}} {{getGoType $propSchema}} {{jsonTag $propName}}
+{{- end}}
 }
-{{end}}
+{{- end}}
 `
 
 	funcMap := template.FuncMap{
-		"toCamelCase": utils.ToCamelCase,
-		"getGoType":   func(s models.Schema) string { return utils.GetGoType(s) },
+		"toGoIdentifier": utils.ToGoIdentifier,
+		"getGoType":      goTypeOf,
 	}
 
-	tmpl, err := template.New("models").Funcs(funcMap).Parse(modelsTemplate)
+	tmpl, err := template.New("models").Funcs(templateFuncs).Funcs(funcMap).Parse(modelsTemplate)
 	if err != nil {
 		return err
 	}
@@ -75,29 +61,5 @@ type {{$name}} struct {
 		Schemas: spec.Components.Schemas,
 	}
 
-	f, err := os.Create(filepath.Join(baseDir, "models", "models.go"))
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	return tmpl.Execute(f, data)
-}
-
-func hasTimeFields(schema models.Schema) bool {
-	if schema.Type == "string" && (schema.Format == "date" || schema.Format == "date-time") {
-		return true
-	}
-
-	for _, propSchema := range schema.Properties {
-		if hasTimeFields(propSchema) {
-			return true
-		}
-	}
-
-	if schema.Items != nil && hasTimeFields(*schema.Items) {
-		return true
-	}
-
-	return false
+	return writeGoFile(filepath.Join(baseDir, "models", "models.go"), tmpl, data, "")
 }
@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+)
+
+// operationEntry is an operation together with the path and method it is served at
+type operationEntry struct {
+	Path      string
+	Method    string // upper case
+	Operation models.Operation
+}
+
+// sortedOperations returns every operation in spec ordered by path and method,
+// so generated code doesn't depend on map iteration order
+func sortedOperations(spec *models.OpenAPISpec) []operationEntry {
+	var entries []operationEntry
+	for path, operations := range spec.Paths {
+		for method, op := range operations {
+			entries = append(entries, operationEntry{
+				Path:      path,
+				Method:    strings.ToUpper(method),
+				Operation: op,
+			})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Path != entries[j].Path {
+			return entries[i].Path < entries[j].Path
+		}
+		return entries[i].Method < entries[j].Method
+	})
+	return entries
+}
+
+// successResponse returns the status code and JSON schema of the first 2xx
+// response of op that has a JSON body
+func successResponse(op models.Operation) (string, *models.Schema) {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		if schema := jsonSchema(op.Responses[code].Content); schema != nil {
+			return code, schema
+		}
+	}
+	return "", nil
+}
+
+// jsonSchema returns the schema of the JSON media type in content, if any
+func jsonSchema(content map[string]models.MediaType) *models.Schema {
+	mediaType, ok := jsonMediaType(content)
+	if !ok {
+		return nil
+	}
+	schema := content[mediaType].Schema
+	return &schema
+}
+
+// isJSONMediaType reports whether mediaType carries JSON
+func isJSONMediaType(mediaType string) bool {
+	mediaType = strings.ToLower(strings.TrimSpace(strings.SplitN(mediaType, ";", 2)[0]))
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// modelType returns the Go type for schema as referenced from outside the
+// models package
+func modelType(schema models.Schema) string {
+	if schema.Ref != "" {
+		return "models." + goTypeOf(schema)
+	}
+	if schema.Type == "array" && schema.Items != nil {
+		return "[]" + modelType(*schema.Items)
+	}
+	return goTypeOf(schema)
+}
@@ -0,0 +1,407 @@
+package generator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+)
+
+// Pagination styles
+const (
+	paginationCursor = "cursor"
+	paginationOffset = "offset"
+	paginationPage   = "page"
+	paginationNone   = "none"
+)
+
+// PaginationHeuristic recognizes paginated GET operations that have no
+// x-pagination extension by the names of their parameters and response
+// properties. A heuristic with no names recognizes nothing.
+type PaginationHeuristic struct {
+	CursorParams     []string // query parameters taking a cursor
+	NextCursorFields []string // response properties holding the next cursor
+	OffsetParams     []string
+	LimitParams      []string
+	PageParams       []string
+	ItemsFields      []string // response properties holding the items of a page
+}
+
+// DefaultPaginationHeuristic returns the heuristic used when none is configured
+func DefaultPaginationHeuristic() *PaginationHeuristic {
+	return &PaginationHeuristic{
+		CursorParams:     []string{"cursor", "page_token", "pageToken", "after", "starting_after"},
+		NextCursorFields: []string{"next_cursor", "nextCursor", "next_page_token", "nextPageToken", "next"},
+		OffsetParams:     []string{"offset", "skip"},
+		LimitParams:      []string{"limit", "page_size", "pageSize", "per_page", "perPage"},
+		PageParams:       []string{"page", "page_number", "pageNumber"},
+		ItemsFields:      []string{"items", "data", "results"},
+	}
+}
+
+// paginatedOperation is the data a client iterator is generated from
+type paginatedOperation struct {
+	Name       string
+	IterName   string
+	PathArgs   string
+	BodyArg    string
+	CallArgs   string // arguments passed on to the operation
+	ParamsType string
+	ItemType   string
+	ItemsField string // empty when the response is the list of items
+	Style      string
+
+	// Cursor pagination
+	CursorField    string
+	CursorOptional bool
+	NextCursor     string
+	CursorZero     string
+
+	// Offset and page pagination
+	CounterField    string
+	CounterOptional bool
+	CounterType     string
+	LimitField      string
+	LimitOptional   bool
+}
+
+// generatePagination writes iterators for the paginated operations to
+// generated/client/pagination.go, removing the file if there are none
+func generatePagination(clientDir string, operations []paginatedOperation, moduleName string) error {
+	// The build constraint lets the file use range-over-func iterators in
+	// modules declaring an older Go version
+	paginationTemplate := `//go:build go1.23
+
+// Code generated by gopenapi. DO NOT EDIT.
+
+package client
+{{range .Operations}}
+// {{.IterName}} iterates over the items of every page of {{.Name}}, fetching
+// each page when the loop reaches it. Iteration stops after the first error.
+func (c *Client) {{.IterName}}(ctx context.Context{{.PathArgs}}, params *{{.ParamsType}}{{.BodyArg}}) iter.Seq2[{{.ItemType}}, error] {
+	return func(yield func({{.ItemType}}, error) bool) {
+		var p {{.ParamsType}}
+		if params != nil {
+			p = *params
+		}
+{{- if and (eq .Style "page") .CounterOptional}}
+		if p.{{.CounterField}} == nil {
+			first := {{.CounterType}}(1)
+			p.{{.CounterField}} = &first
+		}
+{{- end}}
+		for {
+			page, err := c.{{.Name}}(ctx{{.CallArgs}})
+			if err != nil {
+				var zero {{.ItemType}}
+				yield(zero, err)
+				return
+			}
+{{- if .ItemsField}}
+			if page == nil {
+				return
+			}
+			items := page.{{.ItemsField}}
+{{- else}}
+			items := page
+{{- end}}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+{{- if eq .Style "cursor"}}
+
+			next := page.{{.NextCursor}}
+{{- if .CursorOptional}}
+			if next == {{.CursorZero}} || (p.{{.CursorField}} != nil && *p.{{.CursorField}} == next) {
+				return
+			}
+			p.{{.CursorField}} = &next
+{{- else}}
+			if next == {{.CursorZero}} || p.{{.CursorField}} == next {
+				return
+			}
+			p.{{.CursorField}} = next
+{{- end}}
+{{- else}}
+{{- if .LimitField}}
+{{- if .LimitOptional}}
+			if len(items) == 0 || (p.{{.LimitField}} != nil && len(items) < int(*p.{{.LimitField}})) {
+				return
+			}
+{{- else}}
+			if len(items) == 0 || len(items) < int(p.{{.LimitField}}) {
+				return
+			}
+{{- end}}
+{{- else}}
+			if len(items) == 0 {
+				return
+			}
+{{- end}}
+{{- if .CounterOptional}}
+
+			var next {{.CounterType}}
+			if p.{{.CounterField}} != nil {
+				next = *p.{{.CounterField}}
+			}
+			{{advance "next" .}}
+			p.{{.CounterField}} = &next
+{{- else}}
+			{{advance (print "p." .CounterField) .}}
+{{- end}}
+{{- end}}
+		}
+	}
+}
+{{- end}}
+`
+
+	filename := filepath.Join(clientDir, "pagination.go")
+	if len(operations) == 0 {
+		if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	funcMap := template.FuncMap{
+		"advance": advanceStatement,
+	}
+
+	tmpl, err := template.New("pagination").Funcs(templateFuncs).Funcs(funcMap).Parse(paginationTemplate)
+	if err != nil {
+		return err
+	}
+
+	data := struct {
+		Operations []paginatedOperation
+	}{
+		Operations: operations,
+	}
+
+	return writeGoFile(filename, tmpl, data, moduleName)
+}
+
+// operationPagination returns how entry paginates: as declared by its
+// x-pagination extension, or as recognized by heuristic. It returns nil for
+// operations that don't paginate.
+func operationPagination(spec *models.OpenAPISpec, entry operationEntry, heuristic *PaginationHeuristic) *models.Pagination {
+	op := entry.Operation
+	if op.Pagination != nil {
+		p := *op.Pagination
+		if p.Style == "" {
+			switch {
+			case p.CursorParam != "":
+				p.Style = paginationCursor
+			case p.OffsetParam != "":
+				p.Style = paginationOffset
+			case p.PageParam != "":
+				p.Style = paginationPage
+			}
+		}
+		if p.Style == paginationNone {
+			return nil
+		}
+		return &p
+	}
+
+	if heuristic == nil || entry.Method != "GET" {
+		return nil
+	}
+	_, schema := successResponse(op)
+	if schema == nil {
+		return nil
+	}
+	response := resolveSchema(spec, *schema)
+
+	p := &models.Pagination{}
+	if response.Type != "array" {
+		p.Items = firstName(heuristic.ItemsFields, func(name string) bool {
+			prop, ok := response.Properties[name]
+			return ok && resolveSchema(spec, prop).Type == "array"
+		})
+		if p.Items == "" {
+			return nil
+		}
+	}
+
+	query := map[string]bool{}
+	for _, param := range op.Parameters {
+		if param.In == "query" {
+			query[param.Name] = true
+		}
+	}
+	inQuery := func(name string) bool { return query[name] }
+
+	p.LimitParam = firstName(heuristic.LimitParams, inQuery)
+	if p.CursorParam = firstName(heuristic.CursorParams, inQuery); p.CursorParam != "" {
+		p.NextCursor = firstName(heuristic.NextCursorFields, func(name string) bool {
+			_, ok := response.Properties[name]
+			return ok
+		})
+		if p.NextCursor != "" {
+			p.Style = paginationCursor
+			return p
+		}
+	}
+	if p.OffsetParam = firstName(heuristic.OffsetParams, inQuery); p.OffsetParam != "" {
+		p.Style = paginationOffset
+		return p
+	}
+	if p.PageParam = firstName(heuristic.PageParams, inQuery); p.PageParam != "" {
+		p.Style = paginationPage
+		return p
+	}
+	return nil
+}
+
+// buildPaginatedOperation collects what the iterator over clientOp's pages needs
+func buildPaginatedOperation(spec *models.OpenAPISpec, entry operationEntry, clientOp clientOperation, p *models.Pagination) (paginatedOperation, error) {
+	where := entry.Method + " " + entry.Path
+	result := paginatedOperation{
+		Name:       clientOp.Name,
+		IterName:   clientOp.Name + "Iter",
+		PathArgs:   clientOp.PathArgs,
+		CallArgs:   clientOp.PathNames + ", &p",
+		ParamsType: clientOp.ParamsType,
+		Style:      p.Style,
+	}
+	if clientOp.BodyType != "" {
+		result.BodyArg = ", body " + clientOp.BodyType
+		result.CallArgs += ", body"
+	}
+
+	_, schema := successResponse(entry.Operation)
+	if schema == nil {
+		return result, fmt.Errorf("x-pagination on %s: operation has no JSON response", where)
+	}
+	response := resolveSchema(spec, *schema)
+
+	items := response
+	if p.Items != "" {
+		// Only component schemas are generated as structs with fields
+		if schema.Ref == "" {
+			return result, fmt.Errorf("x-pagination on %s: response must reference a component schema to have an items property", where)
+		}
+		prop, ok := response.Properties[p.Items]
+		if !ok {
+			return result, fmt.Errorf("x-pagination on %s: response has no %q property", where, p.Items)
+		}
+		items = resolveSchema(spec, prop)
+		result.ItemsField = utils.ToGoIdentifier(p.Items)
+	}
+	if items.Type != "array" || items.Items == nil {
+		return result, fmt.Errorf("x-pagination on %s: items are not an array", where)
+	}
+	result.ItemType = modelType(*items.Items)
+
+	findParam := func(name string) (clientParam, error) {
+		for _, param := range clientOp.QueryParams {
+			if param.Name == name {
+				return param, nil
+			}
+		}
+		return clientParam{}, fmt.Errorf("x-pagination on %s: no query parameter %q", where, name)
+	}
+
+	switch p.Style {
+	case paginationCursor:
+		param, err := findParam(p.CursorParam)
+		if err != nil {
+			return result, err
+		}
+		prop, ok := response.Properties[p.NextCursor]
+		if p.NextCursor == "" || !ok {
+			return result, fmt.Errorf("x-pagination on %s: response has no next cursor property %q", where, p.NextCursor)
+		}
+		if cursorType := modelType(prop); cursorType != param.Type {
+			return result, fmt.Errorf("x-pagination on %s: next cursor is %s but %s takes %s", where, cursorType, p.CursorParam, param.Type)
+		}
+		switch {
+		case param.Type == "string":
+			result.CursorZero = `""`
+		case isIntegerType(param.Type):
+			result.CursorZero = "0"
+		default:
+			return result, fmt.Errorf("x-pagination on %s: cursor must be a string or integer", where)
+		}
+		result.CursorField = param.Field
+		result.CursorOptional = param.Optional
+		result.NextCursor = utils.ToGoIdentifier(p.NextCursor)
+	case paginationOffset, paginationPage:
+		counter := p.OffsetParam
+		if p.Style == paginationPage {
+			counter = p.PageParam
+		}
+		param, err := findParam(counter)
+		if err != nil {
+			return result, err
+		}
+		if !isIntegerType(param.Type) {
+			return result, fmt.Errorf("x-pagination on %s: %s must be an integer", where, counter)
+		}
+		result.CounterField = param.Field
+		result.CounterOptional = param.Optional
+		result.CounterType = param.Type
+		if p.LimitParam != "" {
+			limit, err := findParam(p.LimitParam)
+			if err != nil {
+				return result, err
+			}
+			if !isIntegerType(limit.Type) {
+				return result, fmt.Errorf("x-pagination on %s: %s must be an integer", where, p.LimitParam)
+			}
+			result.LimitField = limit.Field
+			result.LimitOptional = limit.Optional
+		}
+	default:
+		return result, fmt.Errorf("x-pagination on %s: unknown style %q", where, p.Style)
+	}
+
+	return result, nil
+}
+
+// advanceStatement returns the statement moving the counter expression to the
+// next page of op
+func advanceStatement(counter string, op paginatedOperation) string {
+	if op.Style == paginationPage {
+		return counter + "++"
+	}
+	if op.CounterType == "int" {
+		return counter + " += len(items)"
+	}
+	return counter + " += " + op.CounterType + "(len(items))"
+}
+
+// resolveSchema follows a reference to a component schema
+func resolveSchema(spec *models.OpenAPISpec, schema models.Schema) models.Schema {
+	if schema.Ref == "" {
+		return schema
+	}
+	name := schema.Ref[strings.LastIndex(schema.Ref, "/")+1:]
+	if resolved, ok := spec.Components.Schemas[name]; ok {
+		return resolved
+	}
+	return schema
+}
+
+// isIntegerType reports whether goType is one of the integer types schemas map to
+func isIntegerType(goType string) bool {
+	return goType == "int" || goType == "int32" || goType == "int64"
+}
+
+// firstName returns the first of names matching match
+func firstName(names []string, match func(string) bool) string {
+	for _, name := range names {
+		if match(name) {
+			return name
+		}
+	}
+	return ""
+}
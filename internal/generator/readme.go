@@ -1,20 +1,34 @@
 package generator
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"text/template"
 
+	"github.com/shubhamku044/gopenapi/internal/examples"
 	"github.com/shubhamku044/gopenapi/internal/models"
 	"github.com/shubhamku044/gopenapi/pkg/utils"
 )
 
 // GenerateReadme generates a comprehensive README for the project
-func GenerateReadme(spec *models.OpenAPISpec, baseDir string, packageName string) error {
-	readmeTemplate := `# {{.Title}}
+func GenerateReadme(spec *models.OpenAPISpec, baseDir string, packageName string, framework string) error {
+	return generateReadme(spec, baseDir, packageName, framework, false)
+}
+
+// generateReadme generates a comprehensive README for the project. With
+// splitByTag, it describes the handlers files of each tag.
+func generateReadme(spec *models.OpenAPISpec, baseDir string, packageName string, framework string, splitByTag bool) error {
+	backend, err := LookupBackend(framework)
+	if err != nil {
+		return err
+	}
 
-{{.Description}}
+	readmeTemplate := `# {{markdown .Title}}
+
+{{markdown .Description}}
 
 **Generated by [gopenapi](https://github.com/shubhamku044/gopenapi)**
 
@@ -27,14 +41,46 @@ This project uses a **clean separation** between generated and user code:
 ├── main.go              # Your application entry point (never overwritten)
 ├── go.mod              # Your go.mod file (never overwritten)
 ├── handlers/           # 👈 YOUR CODE GOES HERE
-│   └── api.go         # Implement your business logic here
+{{- if .Groups}}
+{{- range .Groups}}
+│   ├── {{.FileBase}}.go
+{{- end}}
+│   ├── handlers.go    # Combines the handlers of every tag
+{{- else}}
+│   ├── api.go         # Implement your business logic here
+{{- end}}
+│   └── contract_test.go  # Checks your handlers against the spec
 ├── generated/          # 🤖 Generated code (safe to regenerate)
 │   ├── api/
 │   │   └── interfaces.go  # API interface definitions
+│   ├── client/
+│   │   ├── client.go      # Typed HTTP client for the API
+│   │   ├── operations.go  # One client method per operation
+│   │   └── pagination.go  # Iterators over paginated operations
 │   ├── models/
 │   │   └── models.go      # Data models from OpenAPI spec
+│   ├── contract/
+│   │   └── contract.go    # Example requests checking responses against the spec
+│   ├── apitest/
+│   │   └── apitest.go     # Fakes of the handlers and client for unit tests
+│   ├── auth/
+│   │   ├── auth.go        # Enforces the spec's security requirements
+│   │   └── jwt.go         # JWT verification with local JWKS or PEM keys
+│   ├── validate/
+│   │   ├── validate.go    # Request validation against the spec
+│   │   └── response.go    # Response validation against the spec
 │   └── server/
-│       └── router.go      # HTTP server and routing
+│       ├── server.go      # HTTP server and its options
+│       ├── router.go      # Routing on the framework's router
+│       ├── validation.go  # Parameter and body rules of each operation
+│       ├── security.go    # Security schemes and requirements of each operation
+│       ├── operations.go  # Operation metadata and middleware
+│       ├── serve.go       # TLS certificate reloading and listeners
+│       ├── h2c.go         # HTTP/2 without TLS
+│       ├── spec.go        # Embedded OpenAPI document and API reference
+│       ├── openapi.json   # The OpenAPI document as JSON
+│       ├── openapi.yaml   # The OpenAPI document as YAML
+│       └── docs.html      # API reference page
 └── README.md           # This file
 ` + "```" + `
 
@@ -42,14 +88,24 @@ This project uses a **clean separation** between generated and user code:
 
 ### 1. Implement Your Business Logic
 
+{{if .Groups -}}
+Handlers are split by tag. Implement each tag's endpoints in its own file:
+
+{{range .Groups}}- ` + "`handlers/{{.FileBase}}.go`" + `: {{codeSpan .Name}}, which {{markdown .Comment}}
+{{end}}
+` + "`handlers/handlers.go`" + ` combines them into the ` + "`api.APIHandlers`" + ` passed to ` + "`server.NewServer`" + `. Each handler is written like this:
+{{- else -}}
 Edit ` + "`handlers/api.go`" + ` to implement your API endpoints:
+{{- end}}
 
 ` + "```go" + `
 package handlers
 
 import (
     "net/http"
-    "github.com/gin-gonic/gin"
+{{- range .Imports}}
+    {{goString .}}
+{{- end}}
     "{{.PackageName}}/generated/api"
     "{{.PackageName}}/generated/models"
 )
@@ -68,8 +124,8 @@ func NewAPIHandlers() api.APIHandlers {
 }
 
 {{range .Endpoints}}
-// {{.HandlerName}} {{.Comment}}
-func (h *APIHandlers) {{.HandlerName}}(c *gin.Context{{.Parameters}}) {
+// {{.HandlerName}} {{comment .Comment}}
+func (h *APIHandlers) {{.HandlerName}}({{$.ContextParams}}{{.Parameters}}){{if $.HandlerResult}} {{$.HandlerResult}}{{end}} {
     // TODO: Implement your business logic here
     {{.ExampleImplementation}}
 }
@@ -89,14 +145,15 @@ The server will start on port 8080 (or the port specified in the ` + "`PORT`" +
 ### 3. Test Your API
 
 {{range .Endpoints}}
-**{{.Method}} {{.Path}}** - {{.Summary}}
+**{{markdown .Method}} {{markdown .Path}}** - {{markdown .Summary}}
 ` + "```bash" + `
-curl -X {{.Method}} http://localhost:8080{{.Path}}{{if eq .Method "POST"}} \
+curl -X {{shellQuote .Method}} {{shellQuote (print "http://localhost:8080" .Path)}}{{if .RequestBodyJSON}} \
   -H "Content-Type: application/json" \
-  -d '{}'{{end}}
+  -d {{shellQuote .RequestBodyJSON}}{{end}}
 ` + "```" + `
 
 {{end}}
+Run ` + "`go test ./handlers`" + ` to send an example request to every operation and check that your handlers respond with a status code and body the spec declares.
 
 ## 🔄 Updating Your API
 
@@ -104,7 +161,7 @@ When you modify your OpenAPI specification:
 
 1. **Regenerate the code:**
    ` + "```bash" + `
-   gopenapi --spec=your-api.yaml --output=. --package={{.PackageName}}
+   gopenapi --spec=your-api.yaml --output=. --package={{.PackageName}}{{if ne .Framework "gin"}} --framework={{shellQuote .Framework}}{{end}}
    ` + "```" + `
 
 2. **Your code is preserved:**
@@ -115,20 +172,23 @@ When you modify your OpenAPI specification:
 3. **Generated code is updated:**
    - 🔄 ` + "`generated/api/interfaces.go`" + ` - Updated interface definitions
    - 🔄 ` + "`generated/models/models.go`" + ` - Updated data models  
-   - 🔄 ` + "`generated/server/router.go`" + ` - Updated routing
+   - 🔄 ` + "`generated/server/`" + ` - Updated routing and embedded spec
+   - 🔄 ` + "`generated/client/`" + ` - Updated API client
+   - 🔄 ` + "`generated/contract/`" + ` - Updated contract test requests
+   - 🔄 ` + "`generated/apitest/`" + ` - Updated fakes for unit tests
 
 ## 📚 API Reference
 
 {{range .Endpoints}}
-### {{.Method}} {{.Path}}
+### {{markdown .Method}} {{markdown .Path}}
 
-**{{.Summary}}**
+**{{markdown .Summary}}**
 
-{{if .Description}}{{.Description}}{{end}}
+{{if .Description}}{{markdown .Description}}{{end}}
 
 {{if .PathParams}}**Path Parameters:**
 {{range .PathParams}}
-- ` + "`{{.Name}}`" + ` ({{.Type}}) - {{.Description}}
+- {{codeSpan .Name}} ({{codeSpan .Type}}) - {{markdown .Description}}
 {{end}}
 {{end}}
 
@@ -138,18 +198,54 @@ When you modify your OpenAPI specification:
 ` + "```" + `
 {{end}}
 
-**Response:**
+{{if .ResponseExample}}**Response:**
 ` + "```json" + `
 {{.ResponseExample}}
 ` + "```" + `
-
+{{end}}
 ---
 
 {{end}}
 
 ## 🛠️ Development Tips
 
-### Adding Middleware
+{{if eq .Framework "net/http"}}### Adding Routes
+
+Modify ` + "`main.go`" + ` to register extra routes on the ` + "`http.ServeMux`" + `:
+
+` + "```go" + `
+func main() {
+    apiHandlers := handlers.NewAPIHandlers()
+    srv := server.NewServer(apiHandlers)
+    
+    // Register additional routes on the router
+    router := srv.GetRouter()
+    router.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+        w.WriteHeader(http.StatusOK)
+    })
+    
+    srv.Start(":8080")
+}
+` + "```" + `
+
+### Error Handling
+
+Use consistent error responses:
+
+` + "```go" + `
+func (h *APIHandlers) SomeHandler(w http.ResponseWriter, r *http.Request) {
+    if err := someOperation(); err != nil {
+        api.WriteJSON(w, http.StatusInternalServerError, map[string]interface{}{
+            "error": "Internal server error",
+            "message": err.Error(),
+        })
+        return
+    }
+    
+    api.WriteJSON(w, http.StatusOK, map[string]interface{}{"message": "Success"})
+}
+` + "```" + `
+{{else if eq .Framework "gin"}}### Adding Middleware
 
 Modify ` + "`main.go`" + ` to add middleware:
 
@@ -185,6 +281,73 @@ func (h *APIHandlers) SomeHandler(c *gin.Context) {
     c.JSON(http.StatusOK, gin.H{"message": "Success"})
 }
 ` + "```" + `
+{{else}}### Customizing the Router
+
+` + "`srv.GetRouter()`" + ` returns the underlying ` + "`{{.RouterType}}`" + `. Modify ` + "`main.go`" + ` to add middleware or extra routes to it:
+
+` + "```go" + `
+func main() {
+    apiHandlers := handlers.NewAPIHandlers()
+    srv := server.NewServer(apiHandlers)
+    
+    // Add your custom middleware and routes to srv.GetRouter() here
+    
+    srv.Start(":8080")
+}
+` + "```" + `
+{{end}}
+### Configuring the Server
+
+` + "`server.NewServer`" + ` takes options for the router, timeouts, maximum header size, logger, base path and listeners:
+
+` + "```go" + `
+srv := server.NewServer(apiHandlers,
+    server.WithBasePath("/v2"),
+    server.WithReadTimeout(10*time.Second),
+    server.WithWriteTimeout(10*time.Second),
+    server.WithMaxHeaderBytes(64<<10),
+)
+` + "```" + `
+
+By default the API is served under ` + "`server.BasePath`" + `, the path of the server URL in the spec. ` + "`server.WithBasePath(\"/\")`" + ` serves it at the root.
+
+` + "`srv.StartTLS(addr, certFile, keyFile)`" + ` serves HTTPS, reloading the certificate when the files change. ` + "`srv.Serve(listener)`" + ` serves on a listener such as a Unix socket, and ` + "`server.WithH2C()`" + ` accepts HTTP/2 without TLS.
+
+### Serving the API Docs
+
+Pass ` + "`server.WithSpecRoutes()`" + ` to serve the OpenAPI document at ` + "`/openapi.json`" + ` and ` + "`/openapi.yaml`" + `, and an API reference page at ` + "`/docs`" + `. The page is embedded in the binary and works offline. It is a lightweight reference rather than Swagger UI or Redoc, and its "Try it" form doesn't send credentials:
+
+` + "```go" + `
+srv := server.NewServer(apiHandlers, server.WithSpecRoutes())
+` + "```" + `
+
+### Validating Requests
+
+Pass ` + "`server.WithRequestValidation()`" + ` to check the parameters, content type and body of every request against the spec once it is authenticated and before it reaches your handlers. Invalid parameters and malformed bodies are rejected with 400 Bad Request, bodies violating their schema with 422 Unprocessable Entity, each with a JSON list of errors:
+
+` + "```json" + `
+{"error": "invalid request", "errors": [{"in": "query", "field": "limit", "message": "must be at most 100"}]}
+` + "```" + `
+
+In development and tests, ` + "`server.WithResponseValidation(validate.RejectViolations)`" + ` checks the status code, content type and body of every response against the spec and turns mismatches into 500 Internal Server Error. ` + "`validate.LogViolations`" + ` only logs them.
+
+### Authentication
+
+Operations the spec secures call an ` + "`auth.Authenticator`" + ` before their handler. Requests without valid credentials get 401 Unauthorized, and requests whose credentials lack the required scopes get 403 Forbidden. Until you pass one with ` + "`server.WithAuthenticator(a)`" + `, secured operations respond with 500 Internal Server Error:
+
+` + "```go" + `
+srv := server.NewServer(apiHandlers, server.WithAuthenticator(auth.AuthenticatorFunc(
+	func(r *http.Request, scheme *auth.Scheme, scopes []string) (*http.Request, error) {
+		credential, ok := auth.Credential(r, scheme)
+		if !ok || !valid(credential, scopes) {
+			return nil, auth.ErrUnauthenticated
+		}
+		return r, nil
+	},
+)))
+` + "```" + `
+
+For JWT bearer tokens, pass an ` + "`&auth.JWTAuthenticator{Keys: keys, Issuer: ..., Audience: ...}`" + ` with keys from ` + "`auth.LoadJWKS`" + ` or ` + "`auth.LoadPEM`" + `.
 
 ### Database Integration
 
@@ -203,11 +366,11 @@ func NewAPIHandlers(db *sql.DB) api.APIHandlers {
 ## 📖 Generated Models
 
 {{range .Models}}
-### {{.Name}}
+### {{markdown .Name}}
 
 ` + "```go" + `
-type {{.Name}} struct {
-{{range .Fields}}    {{.Name}} {{.Type}} ` + "`json:\"{{.JSONName}}\"`" + `{{if .Description}} // {{.Description}}{{end}}
+type {{goIdent .Name}} struct {
+{{range .Fields}}    {{.Name}} {{.Type}} {{jsonTag .JSONName}}{{if .Description}} // {{comment .Description}}{{end}}
 {{end}}}
 ` + "```" + `
 
@@ -220,7 +383,7 @@ type {{.Name}} struct {
 > This README was generated automatically. To customize it, edit the template in your gopenapi generator.
 `
 
-	tmpl, err := template.New("readme").Parse(readmeTemplate)
+	tmpl, err := template.New("readme").Funcs(templateFuncs).Parse(readmeTemplate)
 	if err != nil {
 		return err
 	}
@@ -251,124 +414,96 @@ type {{.Name}} struct {
 			Description string
 		}
 		RequestBody           bool
+		RequestBodyJSON       string
 		RequestBodyExample    string
 		ResponseExample       string
 		ExampleImplementation string
 	}
 
-	for path, operations := range spec.Paths {
-		for method, op := range operations {
-			handlerName := utils.ToCamelCase(op.OperationID)
+	gen := examples.New(spec.Components.Schemas, exampleSeed)
+	for _, entry := range sortedOperations(spec) {
+		path, method, op := entry.Path, entry.Method, entry.Operation
+		handlerName := utils.ToGoIdentifier(op.OperationID)
 
-			comment := "handles " + strings.ToUpper(method) + " " + path
-			if op.Summary != "" {
-				comment = op.Summary
-			}
+		comment := "handles " + strings.ToUpper(method) + " " + path
+		if op.Summary != "" {
+			comment = op.Summary
+		}
 
-			// Build parameters
-			var params []string
-			var pathParams []struct {
-				Name        string
-				Type        string
-				Description string
-			}
-			for _, param := range op.Parameters {
-				if param.In == "path" {
-					paramType := utils.GetGoType(param.Schema)
-					params = append(params, param.Name+" "+paramType)
-					pathParams = append(pathParams, struct {
-						Name        string
-						Type        string
-						Description string
-					}{
-						Name:        param.Name,
-						Type:        paramType,
-						Description: param.Description,
-					})
-				}
+		// Build parameters
+		var params []string
+		var pathParams []struct {
+			Name        string
+			Type        string
+			Description string
+		}
+		for _, param := range op.Parameters {
+			if param.In == pathParameterType {
+				paramType := goTypeOf(param.Schema)
+				params = append(params, goParamName(param.Name)+" "+paramType)
+				pathParams = append(pathParams, struct {
+					Name        string
+					Type        string
+					Description string
+				}{
+					Name:        param.Name,
+					Type:        paramType,
+					Description: param.Description,
+				})
 			}
+		}
 
-			paramStr := ""
-			if len(params) > 0 {
-				paramStr = ", " + strings.Join(params, ", ")
-			}
+		paramStr := ""
+		if len(params) > 0 {
+			paramStr = ", " + strings.Join(params, ", ")
+		}
 
-			// Example implementation
-			var exampleImpl string
-			switch strings.ToUpper(method) {
-			case "GET":
-				if len(pathParams) > 0 {
-					exampleImpl = `// Use path parameter: ` + pathParams[0].Name + `
-    c.JSON(http.StatusOK, gin.H{
-        "id": ` + pathParams[0].Name + `,
-        "data": "your data here",
-    })`
-				} else {
-					exampleImpl = `c.JSON(http.StatusOK, gin.H{
-        "data": []interface{}{}, // Return your data here
-    })`
-				}
-			case "POST":
-				exampleImpl = `var request models.SomeModel
-    if err := c.ShouldBindJSON(&request); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-        return
-    }
-    
-    // Process the request...
-    
-    c.JSON(http.StatusCreated, request)`
-			case "PUT":
-				exampleImpl = `var request models.SomeModel
-    if err := c.ShouldBindJSON(&request); err != nil {
-        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-        return
-    }
-    
-    // Update logic here...
-    
-    c.JSON(http.StatusOK, request)`
-			case "DELETE":
-				exampleImpl = `// Delete logic here...
-    c.JSON(http.StatusNoContent, nil)`
-			default:
-				exampleImpl = `c.JSON(http.StatusNotImplemented, gin.H{
-        "error": "Not implemented yet",
-    })`
-			}
+		// Example implementation, as the handler stub writes it
+		exampleImpl := strings.TrimPrefix(backend.ExampleCode(strings.ToUpper(method), handlerExample(gen, op)), "// TODO: Implement your business logic here\n\n\t")
+		exampleImpl = strings.ReplaceAll(exampleImpl, "\t", "    ")
 
-			endpoints = append(endpoints, struct {
-				Method      string
-				Path        string
-				HandlerName string
-				Summary     string
-				Description string
-				Comment     string
-				Parameters  string
-				PathParams  []struct {
-					Name        string
-					Type        string
-					Description string
-				}
-				RequestBody           bool
-				RequestBodyExample    string
-				ResponseExample       string
-				ExampleImplementation string
-			}{
-				Method:                strings.ToUpper(method),
-				Path:                  path,
-				HandlerName:           handlerName,
-				Summary:               op.Summary,
-				Description:           op.Description,
-				Comment:               comment,
-				Parameters:            paramStr,
-				PathParams:            pathParams,
-				RequestBody:           op.RequestBody != nil,
-				RequestBodyExample:    `{"key": "value"}`,
-				ResponseExample:       `{"message": "success"}`,
-				ExampleImplementation: exampleImpl,
-			})
+		// Request and response bodies from the spec's examples
+		var requestJSON, requestBody, responseBody string
+		if body, ok := requestExample(gen, op); ok {
+			requestJSON, requestBody = readmeJSON(body)
+		}
+		if _, body, ok := operationExample(gen, op); ok && body != nil {
+			_, responseBody = readmeJSON(body)
 		}
+
+		endpoints = append(endpoints, struct {
+			Method      string
+			Path        string
+			HandlerName string
+			Summary     string
+			Description string
+			Comment     string
+			Parameters  string
+			PathParams  []struct {
+				Name        string
+				Type        string
+				Description string
+			}
+			RequestBody           bool
+			RequestBodyJSON       string
+			RequestBodyExample    string
+			ResponseExample       string
+			ExampleImplementation string
+		}{
+			Method:                strings.ToUpper(method),
+			Path:                  path,
+			HandlerName:           handlerName,
+			Summary:               op.Summary,
+			Description:           op.Description,
+			Comment:               comment,
+			Parameters:            paramStr,
+			PathParams:            pathParams,
+			RequestBody:           requestBody != "",
+			RequestBodyJSON:       requestJSON,
+			RequestBodyExample:    requestBody,
+			ResponseExample:       responseBody,
+			ExampleImplementation: exampleImpl,
+		})
 	}
 
 	// Prepare models
@@ -391,20 +526,21 @@ type {{.Name}} struct {
 		}
 
 		for fieldName, fieldSchema := range schema.Properties {
-			goType := utils.GetGoType(fieldSchema)
+			goType := goTypeOf(fieldSchema)
 			fields = append(fields, struct {
 				Name        string
 				Type        string
 				JSONName    string
 				Description string
 			}{
-				Name:        utils.ToCamelCase(fieldName),
+				Name:        utils.ToGoIdentifier(fieldName),
 				Type:        goType,
 				JSONName:    fieldName,
 				Description: fieldSchema.Description,
 			})
 		}
 
+		sort.Slice(fields, func(i, j int) bool { return fields[i].JSONName < fields[j].JSONName })
 		models = append(models, struct {
 			Name   string
 			Fields []struct {
@@ -418,12 +554,25 @@ type {{.Name}} struct {
 			Fields: fields,
 		})
 	}
+	// Map iteration order would reorder the models on every run
+	sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+
+	var groups []tagGroup
+	if splitByTag {
+		groups = tagGroups(spec)
+	}
 
 	data := struct {
-		Title       string
-		Description string
-		PackageName string
-		Endpoints   []struct {
+		Title         string
+		Description   string
+		PackageName   string
+		Framework     string
+		RouterType    string
+		ContextParams string
+		HandlerResult string
+		Imports       []string
+		Groups        []tagGroup
+		Endpoints     []struct {
 			Method      string
 			Path        string
 			HandlerName string
@@ -437,6 +586,7 @@ type {{.Name}} struct {
 				Description string
 			}
 			RequestBody           bool
+			RequestBodyJSON       string
 			RequestBodyExample    string
 			ResponseExample       string
 			ExampleImplementation string
@@ -451,11 +601,17 @@ type {{.Name}} struct {
 			}
 		}
 	}{
-		Title:       title,
-		Description: description,
-		PackageName: packageName,
-		Endpoints:   endpoints,
-		Models:      models,
+		Title:         title,
+		Description:   description,
+		PackageName:   packageName,
+		Framework:     backend.Name,
+		RouterType:    backend.RouterType,
+		ContextParams: backend.ContextParams,
+		HandlerResult: backend.HandlerResult,
+		Imports:       backend.Imports,
+		Groups:        groups,
+		Endpoints:     endpoints,
+		Models:        models,
 	}
 
 	f, err := os.Create(filepath.Join(baseDir, "README.md"))
@@ -466,3 +622,18 @@ type {{.Name}} struct {
 
 	return tmpl.Execute(f, data)
 }
+
+// readmeJSON returns value as compact JSON, for curl commands, and as
+// indented JSON, for the API reference. Values that can't be encoded are
+// returned as empty strings.
+func readmeJSON(value interface{}) (compact, indented string) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", ""
+	}
+	pretty, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", ""
+	}
+	return string(data), string(pretty)
+}
@@ -0,0 +1,231 @@
+package generator
+
+import (
+	"path/filepath"
+	"text/template"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+)
+
+// generateOperations generates generated/server/operations.go, describing
+// each operation for middleware and holding the middleware chains and base
+// path mounting the router templates use
+func generateOperations(spec *models.OpenAPISpec, baseDir string, moduleName string, server serverInfo) error {
+	operationsTemplate := `// Code generated by gopenapi. DO NOT EDIT.
+
+package server
+
+{{if .ServerURL -}}
+// BasePath is the path of the server URL in the spec,
+// {{comment .ServerURL}}. The server serves the API under it unless
+// WithBasePath is given.
+{{- else -}}
+// BasePath is the path the server serves the API under unless WithBasePath
+// is given. The spec declares no servers, so it is the root.
+{{- end}}
+const BasePath = {{goString .BasePath}}
+
+// Operation describes an operation of the API
+type Operation struct {
+	ID          string // operationId in the spec
+	HandlerName string // method of api.APIHandlers handling the operation
+	Method      string
+	Path        string // path in the spec, such as /users/{id}
+	Tags        []string
+}
+
+// operations are the operations of the API, by handler name
+var operations = map[string]*Operation{
+{{- range .Operations}}
+	{{goString .HandlerName}}: {
+		ID:          {{goString .ID}},
+		HandlerName: {{goString .HandlerName}},
+		Method:      {{goString .Method}},
+		Path:        {{goString .Path}},
+{{- if .Tags}}
+		Tags:        []string{ {{- range $i, $t := .Tags}}{{if $i}}, {{end}}{{goString $t}}{{end -}} },
+{{- end}}
+	},
+{{- end}}
+}
+
+// operationKey is the context key of the operation a request was routed to
+type operationKey struct{}
+
+// OperationFromContext returns the operation a request was routed to.
+// Middleware and handlers read it from the request context.
+func OperationFromContext(ctx context.Context) (*Operation, bool) {
+	op, ok := ctx.Value(operationKey{}).(*Operation)
+	return op, ok
+}
+
+// withOperation returns r with op in its context
+func withOperation(r *http.Request, op *Operation) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), operationKey{}, op))
+}
+
+// middlewareChain collects the middleware added to a Server. Middleware for
+// every operation runs first, then middleware for each of the operation's
+// tags, then middleware for the operation itself.
+type middlewareChain[M any] struct {
+	all         []M
+	byTag       map[string][]M
+	byOperation map[string][]M // by handler name
+	sealed      bool
+}
+
+// use adds middleware for every operation
+func (c *middlewareChain[M]) use(mw []M) {
+	c.checkSealed()
+	c.all = append(c.all, mw...)
+}
+
+// useForTag adds middleware for the operations tagged tag
+func (c *middlewareChain[M]) useForTag(tag string, mw []M) {
+	c.checkSealed()
+	if !hasTag(tag) {
+		panic(fmt.Sprintf("server: no operation is tagged %q", tag))
+	}
+	if c.byTag == nil {
+		c.byTag = map[string][]M{}
+	}
+	c.byTag[tag] = append(c.byTag[tag], mw...)
+}
+
+// useForOperation adds middleware for the operation with operationID
+func (c *middlewareChain[M]) useForOperation(operationID string, mw []M) {
+	c.checkSealed()
+	for _, op := range operations {
+		if op.ID == operationID {
+			if c.byOperation == nil {
+				c.byOperation = map[string][]M{}
+			}
+			c.byOperation[op.HandlerName] = append(c.byOperation[op.HandlerName], mw...)
+			return
+		}
+	}
+	panic(fmt.Sprintf("server: no operation has the operationId %q", operationID))
+}
+
+// forOperation returns the middleware of op in the order it runs
+func (c *middlewareChain[M]) forOperation(op *Operation) []M {
+	mw := append([]M(nil), c.all...)
+	for _, tag := range op.Tags {
+		mw = append(mw, c.byTag[tag]...)
+	}
+	return append(mw, c.byOperation[op.HandlerName]...)
+}
+
+// seal rejects middleware added once the routes are registered, since it
+// would never run
+func (c *middlewareChain[M]) seal() {
+	c.sealed = true
+}
+
+// checkSealed panics if the routes are already registered
+func (c *middlewareChain[M]) checkSealed() {
+	if c.sealed {
+		panic("server: middleware must be added before the server starts or GetRouter is called")
+	}
+}
+
+// mountAt serves next under basePath, with basePath removed from the request
+// path. Requests outside basePath get 404 Not Found.
+func mountAt(basePath string, next http.Handler) http.Handler {
+	strip := http.StripPrefix(basePath, next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, found := strings.CutPrefix(r.URL.Path, basePath)
+		if !found || !strings.HasPrefix(rest, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		strip.ServeHTTP(w, r)
+	})
+}
+
+// pathParam converts the raw value of the path parameter name to T, the type
+// its handler takes. If it doesn't parse, it responds with 400 Bad Request
+// and returns false.
+func pathParam[T int | int32 | int64 | float32 | float64 | bool | time.Time](w http.ResponseWriter, name, raw string) (T, bool) {
+	var value T
+	var err error
+	switch v := any(&value).(type) {
+	case *int:
+		*v, err = strconv.Atoi(raw)
+	case *int32:
+		var n int64
+		n, err = strconv.ParseInt(raw, 10, 32)
+		*v = int32(n)
+	case *int64:
+		*v, err = strconv.ParseInt(raw, 10, 64)
+	case *float32:
+		var f float64
+		f, err = strconv.ParseFloat(raw, 32)
+		*v = float32(f)
+	case *float64:
+		*v, err = strconv.ParseFloat(raw, 64)
+	case *bool:
+		*v, err = strconv.ParseBool(raw)
+	case *time.Time:
+		// date-time values, or dates
+		if *v, err = time.Parse(time.RFC3339, raw); err != nil {
+			*v, err = time.Parse(time.DateOnly, raw)
+		}
+	}
+	if err != nil {
+		validate.WriteError(w, &validate.RequestError{
+			Status: http.StatusBadRequest,
+			Errors: []validate.Error{ {In: "path", Field: name, Message: fmt.Sprintf("%q is not a valid %T", raw, value)} },
+		})
+		return value, false
+	}
+	return value, true
+}
+
+// hasTag reports whether any operation is tagged tag
+func hasTag(tag string) bool {
+	for _, op := range operations {
+		for _, t := range op.Tags {
+			if t == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+`
+
+	tmpl, err := template.New("operations").Funcs(templateFuncs).Parse(operationsTemplate)
+	if err != nil {
+		return err
+	}
+
+	type operationInfo struct {
+		ID          string
+		HandlerName string
+		Method      string
+		Path        string
+		Tags        []string
+	}
+
+	data := struct {
+		ServerURL  string
+		BasePath   string
+		Operations []operationInfo
+	}{
+		ServerURL: server.URL,
+		BasePath:  server.BasePath,
+	}
+	for _, entry := range sortedOperations(spec) {
+		data.Operations = append(data.Operations, operationInfo{
+			ID:          entry.Operation.OperationID,
+			HandlerName: utils.ToGoIdentifier(entry.Operation.OperationID),
+			Method:      entry.Method,
+			Path:        entry.Path,
+			Tags:        entry.Operation.Tags,
+		})
+	}
+
+	return writeGoFile(filepath.Join(baseDir, "generated", "server", "operations.go"), tmpl, data, moduleName)
+}
@@ -0,0 +1,162 @@
+package generator
+
+import (
+	"path/filepath"
+	"text/template"
+)
+
+// generateServe generates generated/server/serve.go, holding the certificate
+// reloading and listener handling the router templates' Start, StartTLS and
+// Serve methods use, and h2c.go enabling unencrypted HTTP/2 with
+// http.Protocols, or with golang.org/x/net before Go 1.24
+func generateServe(baseDir string, moduleName string) error {
+	serveTemplate := `// Code generated by gopenapi. DO NOT EDIT.
+
+package server
+
+// serveListeners serves on every listener with serve, returning the first
+// error. After Shutdown each returns http.ErrServerClosed.
+func serveListeners(listeners []net.Listener, serve func(net.Listener) error) error {
+	errs := make(chan error, len(listeners))
+	for _, l := range listeners {
+		go func(l net.Listener) {
+			errs <- serve(l)
+		}(l)
+	}
+	return <-errs
+}
+
+// tlsConfig returns the TLS configuration serving the certificate in
+// certFile and keyFile, reloaded when either file changes
+func tlsConfig(certFile, keyFile string, logger *log.Logger) (*tls.Config, error) {
+	certs := &certReloader{certFile: certFile, keyFile: keyFile, logger: logger}
+	if certs.logger == nil {
+		certs.logger = log.Default()
+	}
+	if err := certs.reload(); err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certs.getCertificate,
+	}, nil
+}
+
+// certCheckInterval is how often the certificate and key files are checked
+// for changes. Handshakes in between are served the loaded certificate
+// without touching the files.
+var certCheckInterval = 10 * time.Second
+
+// certReloader holds the certificate of a certificate and key file pair,
+// loading it again when the files change so that renewed certificates are
+// served without a restart
+type certReloader struct {
+	certFile string
+	keyFile  string
+	logger   *log.Logger
+	cert     atomic.Pointer[tls.Certificate]
+
+	mu      sync.Mutex // held while the files are checked
+	checked time.Time
+	certMod fileVersion
+	keyMod  fileVersion
+}
+
+// fileVersion tells versions of a file apart
+type fileVersion struct {
+	modTime time.Time
+	size    int64
+}
+
+// reload loads the certificate if either file changed since it was last
+// loaded. It must be called with mu held or before the reloader is shared.
+func (r *certReloader) reload() error {
+	r.checked = time.Now()
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return err
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return err
+	}
+	certMod := fileVersion{certInfo.ModTime(), certInfo.Size()}
+	keyMod := fileVersion{keyInfo.ModTime(), keyInfo.Size()}
+	if r.cert.Load() != nil && certMod == r.certMod && keyMod == r.keyMod {
+		return nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading TLS certificate: %w", err)
+	}
+	r.cert.Store(&cert)
+	r.certMod = certMod
+	r.keyMod = keyMod
+	return nil
+}
+
+// getCertificate implements tls.Config.GetCertificate. The files are checked
+// at most every certCheckInterval, by one handshake while the others are
+// served the loaded certificate. If the files can't be loaded, such as while
+// they are being replaced, the previous certificate is served.
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if r.mu.TryLock() {
+		if time.Since(r.checked) >= certCheckInterval {
+			if err := r.reload(); err != nil {
+				r.logger.Printf("server: keeping the previous TLS certificate: %v", err)
+			}
+		}
+		r.mu.Unlock()
+	}
+	return r.cert.Load(), nil
+}
+`
+
+	// The build constraints let the file use http.Protocols in modules
+	// declaring an older Go version
+	h2cTemplate := `//go:build go1.24
+
+// Code generated by gopenapi. DO NOT EDIT.
+
+package server
+
+// enableH2C makes srv accept HTTP/2 without TLS, as well as HTTP/1 and
+// HTTP/2 over TLS
+func enableH2C(srv *http.Server) {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	srv.Protocols = protocols
+}
+`
+
+	h2cFallbackTemplate := `//go:build !go1.24
+
+// Code generated by gopenapi. DO NOT EDIT.
+
+package server
+
+// enableH2C makes srv accept HTTP/2 without TLS with golang.org/x/net, since
+// http.Protocols needs Go 1.24
+func enableH2C(srv *http.Server) {
+	srv.Handler = h2c.NewHandler(srv.Handler, &http2.Server{})
+}
+`
+
+	serverDir := filepath.Join(baseDir, "generated", "server")
+	for _, file := range []struct{ name, text string }{
+		{"serve.go", serveTemplate},
+		{"h2c.go", h2cTemplate},
+		{"h2c_fallback.go", h2cFallbackTemplate},
+	} {
+		tmpl, err := template.New(file.name).Parse(file.text)
+		if err != nil {
+			return err
+		}
+		if err := writeGoFile(filepath.Join(serverDir, file.name), tmpl, nil, moduleName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,328 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// generateServer generates generated/server/server.go, declaring the parts of
+// the Server type every built-in router shares: its fields and options,
+// NewServer, Start, StartTLS, Serve, Shutdown, GetRouter and the Use methods.
+// Backends without CommonServer declare the whole Server in their router
+// template, so the file is removed for them.
+func generateServer(baseDir string, moduleName string, backend *Backend) error {
+	serverTemplate := `// Code generated by gopenapi. DO NOT EDIT.
+
+package server
+
+// Server wraps the HTTP server
+type Server struct {
+	router   {{.RouterType}}
+	server   *http.Server
+	handlers api.APIHandlers
+
+	readTimeout    time.Duration
+	writeTimeout   time.Duration
+	idleTimeout    time.Duration
+	maxHeaderBytes int
+	logger         *log.Logger
+	basePath       string
+	listeners      []net.Listener
+	h2c            bool
+	routerOptions
+
+	specRoutes        bool
+	validateRequests  bool
+	validateResponses bool
+	responseMode      validate.ResponseMode
+	authenticator     auth.Authenticator
+	validator         *validate.Validator
+
+	middleware middlewareChain[Middleware]
+	routesOnce sync.Once
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithReadTimeout sets the maximum duration for reading a request, body
+// included. The default is 30 seconds.
+func WithReadTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.readTimeout = d
+	}
+}
+
+// WithWriteTimeout sets the maximum duration for writing a response. The
+// default is 30 seconds.
+func WithWriteTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.writeTimeout = d
+	}
+}
+
+// WithIdleTimeout sets how long idle keep-alive connections are kept open.
+// The default is 120 seconds.
+func WithIdleTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.idleTimeout = d
+	}
+}
+
+// WithMaxHeaderBytes sets the maximum size of request headers. The default
+// is http.DefaultMaxHeaderBytes.
+func WithMaxHeaderBytes(n int) Option {
+	return func(s *Server) {
+		s.maxHeaderBytes = n
+	}
+}
+
+// WithLogger logs requests and server errors to logger instead of the
+// router's default output. Use slog.NewLogLogger to log to a slog.Handler.
+func WithLogger(logger *log.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// WithBasePath serves the API under path, such as /v2, instead of BasePath,
+// the path of the server URL in the spec. Routes on the router stay relative
+// to it. Pass "/" to serve the API at the root.
+func WithBasePath(path string) Option {
+	return func(s *Server) {
+		s.basePath = strings.TrimSuffix("/"+strings.Trim(path, "/"), "/")
+	}
+}
+
+// WithListener makes Start serve on l instead of listening on its address.
+// Pass it more than once to serve on several listeners. Tests can serve on
+// an ephemeral port with a listener from net.Listen("tcp", "127.0.0.1:0").
+func WithListener(l net.Listener) Option {
+	return func(s *Server) {
+		s.listeners = append(s.listeners, l)
+	}
+}
+
+// WithH2C accepts HTTP/2 without TLS (h2c) from clients that know the server
+// supports it, such as other services behind a load balancer terminating TLS.
+// HTTP/1 keeps working. Before Go 1.24 it is served with
+// golang.org/x/net/http2/h2c.
+func WithH2C() Option {
+	return func(s *Server) {
+		s.h2c = true
+	}
+}
+
+// WithSpecRoutes serves the OpenAPI document at /openapi.json and
+// /openapi.yaml and an API reference page at /docs
+func WithSpecRoutes() Option {
+	return func(s *Server) {
+		s.specRoutes = true
+	}
+}
+
+// WithRequestValidation checks the parameters and body of every request
+// against the spec once it is authenticated and before it reaches the
+// middleware and handlers, rejecting invalid requests with 400 Bad Request or
+// 422 Unprocessable Entity and a JSON list of errors
+func WithRequestValidation() Option {
+	return func(s *Server) {
+		s.validateRequests = true
+	}
+}
+
+// WithResponseValidation checks the status code, content type and body of
+// every response against the spec before it is sent. validate.LogViolations
+// logs responses that don't match, validate.RejectViolations replaces them
+// with 500 Internal Server Error so that tests catch handlers drifting from
+// the spec. Responses are buffered, so it's meant for development and tests.
+func WithResponseValidation(mode validate.ResponseMode) Option {
+	return func(s *Server) {
+		s.validateResponses = true
+		s.responseMode = mode
+	}
+}
+
+// WithAuthenticator uses a to authenticate requests to the operations the
+// spec secures, before they reach the middleware and handlers. Without an
+// Authenticator, secured operations respond with 500 Internal Server Error.
+func WithAuthenticator(a auth.Authenticator) Option {
+	return func(s *Server) {
+		s.authenticator = a
+	}
+}
+
+// Use adds middleware run for every operation, after its security
+// requirements are checked and the request validated. Middleware must be added before the server
+// starts or GetRouter is called.
+func (s *Server) Use(mw ...Middleware) {
+	s.middleware.use(mw)
+}
+
+// UseForTag adds middleware run for the operations tagged tag, after the
+// middleware added with Use
+func (s *Server) UseForTag(tag string, mw ...Middleware) {
+	s.middleware.useForTag(tag, mw)
+}
+
+// UseForOperation adds middleware run for the operation with operationID,
+// after the middleware added with Use and UseForTag
+func (s *Server) UseForOperation(operationID string, mw ...Middleware) {
+	s.middleware.useForOperation(operationID, mw)
+}
+
+// NewServer creates a new HTTP server with the provided handlers
+func NewServer(handlers api.APIHandlers, opts ...Option) *Server {
+	s := &Server{
+		handlers:     handlers,
+		readTimeout:  30 * time.Second,
+		writeTimeout: 30 * time.Second,
+		idleTimeout:  120 * time.Second,
+		basePath:     BasePath,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.setupRouter()
+	s.setupServer()
+
+	return s
+}
+
+// Start starts the HTTP server on addr, or on the listeners given with
+// WithListener
+func (s *Server) Start(addr string) error {
+	s.registerRoutes()
+	if len(s.listeners) == 0 {
+		s.server.Addr = addr
+		return s.server.ListenAndServe()
+	}
+	return serveListeners(s.listeners, s.server.Serve)
+}
+
+// StartTLS starts the HTTPS server on addr, or on the listeners given with
+// WithListener, serving the certificate and key in certFile and keyFile.
+// The files are checked every 10 seconds and loaded again when they change,
+// so renewed certificates are served without a restart. HTTP/2 is negotiated
+// with clients supporting it.
+func (s *Server) StartTLS(addr, certFile, keyFile string) error {
+	config, err := tlsConfig(certFile, keyFile, s.logger)
+	if err != nil {
+		return err
+	}
+	s.registerRoutes()
+	s.server.TLSConfig = config
+	if len(s.listeners) == 0 {
+		s.server.Addr = addr
+		return s.server.ListenAndServeTLS("", "")
+	}
+	return serveListeners(s.listeners, func(l net.Listener) error {
+		return s.server.ServeTLS(l, "", "")
+	})
+}
+
+// Serve serves HTTP on l, such as a Unix socket listener or one passed by
+// systemd socket activation, until Shutdown is called
+func (s *Server) Serve(l net.Listener) error {
+	s.registerRoutes()
+	return s.server.Serve(l)
+}
+
+// Shutdown gracefully shuts down the server
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// GetRouter returns the underlying router for advanced configuration.
+// It registers the API routes, so middleware must be added before.
+func (s *Server) GetRouter() {{.RouterType}} {
+	s.registerRoutes()
+	return s.router
+}
+
+// setupServer configures the HTTP server. Requests are validated by
+// prepare, once they are authenticated, and responses around the router.
+func (s *Server) setupServer() {
+	var handler http.Handler = s.router
+	if s.validateRequests || s.validateResponses {
+		s.validator = Validator()
+		if s.validateResponses {
+			handler = s.validator.ResponseMiddleware(s.responseMode, handler)
+		}
+	}
+
+	if s.basePath != "" {
+		handler = mountAt(s.basePath, handler)
+	}
+
+	s.server = &http.Server{
+		Handler:        s.serverHandler(handler),
+		ReadTimeout:    s.readTimeout,
+		WriteTimeout:   s.writeTimeout,
+		IdleTimeout:    s.idleTimeout,
+		MaxHeaderBytes: s.maxHeaderBytes,
+		ErrorLog:       s.logger,
+	}
+	if s.h2c {
+		enableH2C(s.server)
+	}
+}
+
+// prepare runs before the middleware and handler of the operation handled by
+// handlerName. It records op in the request context, checks its security
+// requirements and then, with WithRequestValidation, validates the request,
+// so unauthenticated requests are rejected before their body is looked at.
+// It responds and returns false if the request must not go further.
+func (s *Server) prepare(w http.ResponseWriter, r *http.Request, op *Operation, handlerName string) (*http.Request, bool) {
+	r, ok := authenticate(s.authenticator, w, withOperation(r, op), handlerName)
+	if !ok {
+		return r, false
+	}
+	if s.validateRequests {
+		err := s.validator.ValidateRequest(r)
+		var reqErr *validate.RequestError
+		if errors.As(err, &reqErr) {
+			validate.WriteError(w, reqErr)
+			return r, false
+		}
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return r, false
+		}
+	}
+	return r, true
+}
+
+// registerRoutes registers the routes once all middleware has been added
+func (s *Server) registerRoutes() {
+	s.routesOnce.Do(func() {
+		s.middleware.seal()
+		s.setupRoutes()
+		if s.specRoutes {
+			s.setupSpecRoutes()
+		}
+	})
+}
+`
+
+	filename := filepath.Join(baseDir, "generated", "server", "server.go")
+	if !backend.CommonServer {
+		if err := os.Remove(filename); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	tmpl, err := template.New("server").Parse(serverTemplate)
+	if err != nil {
+		return err
+	}
+	data := struct {
+		RouterType string
+	}{
+		RouterType: backend.RouterType,
+	}
+	return writeGoFile(filename, tmpl, data, moduleName)
+}
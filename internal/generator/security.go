@@ -0,0 +1,195 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+	"github.com/shubhamku044/gopenapi/pkg/auth"
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+)
+
+// GenerateSecurity generates the auth package in generated/auth/ and the
+// security schemes and requirements of each operation in generated/server/
+func GenerateSecurity(spec *models.OpenAPISpec, baseDir string, moduleName string) error {
+	err := writeRuntimeFiles(auth.Files, filepath.Join(baseDir, "generated", "auth"))
+	if err != nil {
+		return err
+	}
+
+	securityTemplate := `// Code generated by gopenapi. DO NOT EDIT.
+
+package server
+
+// securitySchemes are the security schemes of the spec, by name
+var securitySchemes = map[string]*auth.Scheme{
+{{- range .Schemes}}
+	{{goString .Name}}: {{.Literal}},
+{{- end}}
+}
+
+// operationSecurity are the security requirements of each secured
+// operation, by handler name. Requests must satisfy one of them.
+var operationSecurity = map[string][]auth.Requirement{
+{{- range .Operations}}
+	{{goString .HandlerName}}: {
+{{- range .Requirements}}
+		{{.}},
+{{- end}}
+	},
+{{- end}}
+}
+
+// SecuritySchemes returns the security schemes of the spec, by name
+func SecuritySchemes() map[string]*auth.Scheme {
+	return securitySchemes
+}
+
+// authenticate checks the credentials of a request to the operation handled
+// by handlerName, writing a 401 or 403 response if they don't satisfy its
+// security requirements
+func authenticate(a auth.Authenticator, w http.ResponseWriter, r *http.Request, handlerName string) (*http.Request, bool) {
+	return auth.Check(a, w, r, operationSecurity[handlerName])
+}
+`
+
+	tmpl, err := template.New("security").Funcs(templateFuncs).Parse(securityTemplate)
+	if err != nil {
+		return err
+	}
+
+	type schemeEntry struct {
+		Name    string
+		Literal string
+	}
+	type operationSecurity struct {
+		HandlerName  string
+		Requirements []string
+	}
+
+	var data struct {
+		Schemes    []schemeEntry
+		Operations []operationSecurity
+	}
+
+	names := make([]string, 0, len(spec.Components.SecuritySchemes))
+	for name := range spec.Components.SecuritySchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data.Schemes = append(data.Schemes, schemeEntry{
+			Name:    name,
+			Literal: securitySchemeLiteral(name, spec.Components.SecuritySchemes[name]),
+		})
+	}
+
+	for _, entry := range sortedOperations(spec) {
+		requirements := effectiveSecurity(spec, entry.Operation)
+		if len(requirements) == 0 {
+			continue
+		}
+
+		security := operationSecurity{HandlerName: utils.ToGoIdentifier(entry.Operation.OperationID)}
+		for _, requirement := range requirements {
+			literal, err := securityRequirementLiteral(spec, requirement)
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", entry.Method, entry.Path, err)
+			}
+			security.Requirements = append(security.Requirements, literal)
+		}
+		data.Operations = append(data.Operations, security)
+	}
+
+	return writeGoFile(filepath.Join(baseDir, "generated", "server", "security.go"), tmpl, data, moduleName)
+}
+
+// effectiveSecurity returns the security requirements of op: its own, or
+// the spec's if it declares none
+func effectiveSecurity(spec *models.OpenAPISpec, op models.Operation) []models.SecurityRequirement {
+	if op.Security != nil {
+		return *op.Security
+	}
+	return spec.Security
+}
+
+// securitySchemeLiteral returns scheme as a *auth.Scheme expression
+func securitySchemeLiteral(name string, scheme models.SecurityScheme) string {
+	fields := []string{"Name: " + strconv.Quote(name)}
+	for _, field := range []struct{ name, value string }{
+		{"Type", scheme.Type},
+		{"In", scheme.In},
+		{"ParamName", scheme.Name},
+		{"HTTPScheme", scheme.Scheme},
+		{"BearerFormat", scheme.BearerFormat},
+		{"OpenIDConnectURL", scheme.OpenIDConnectURL},
+	} {
+		if field.value != "" {
+			fields = append(fields, field.name+": "+strconv.Quote(field.value))
+		}
+	}
+
+	if scopes := oauthScopes(scheme.Flows); len(scopes) > 0 {
+		quoted := make([]string, len(scopes))
+		for i, scope := range scopes {
+			quoted[i] = strconv.Quote(scope)
+		}
+		fields = append(fields, "Scopes: []string{"+strings.Join(quoted, ", ")+"}")
+	}
+
+	return "&auth.Scheme{" + strings.Join(fields, ", ") + "}"
+}
+
+// oauthScopes returns the scopes of every flow in flows, sorted
+func oauthScopes(flows *models.OAuthFlows) []string {
+	if flows == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	var scopes []string
+	for _, flow := range []*models.OAuthFlow{flows.Implicit, flows.Password, flows.ClientCredentials, flows.AuthorizationCode} {
+		if flow == nil {
+			continue
+		}
+		for scope := range flow.Scopes {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+// securityRequirementLiteral returns requirement as an auth.Requirement
+// composite literal
+func securityRequirementLiteral(spec *models.OpenAPISpec, requirement models.SecurityRequirement) (string, error) {
+	names := make([]string, 0, len(requirement))
+	for name := range requirement {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	needs := make([]string, len(names))
+	for i, name := range names {
+		if _, ok := spec.Components.SecuritySchemes[name]; !ok {
+			return "", fmt.Errorf("security requirement references undefined scheme %q", name)
+		}
+
+		need := "{Scheme: securitySchemes[" + strconv.Quote(name) + "]"
+		if scopes := requirement[name]; len(scopes) > 0 {
+			quoted := make([]string, len(scopes))
+			for j, scope := range scopes {
+				quoted[j] = strconv.Quote(scope)
+			}
+			need += ", Scopes: []string{" + strings.Join(quoted, ", ") + "}"
+		}
+		needs[i] = need + "}"
+	}
+	return "{" + strings.Join(needs, ", ") + "}", nil
+}
@@ -1,7 +1,6 @@
 package generator
 
 import (
-	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
@@ -13,6 +12,7 @@ import (
 type PathParam struct {
 	Name string
 	Type string
+	Var  string
 }
 
 type Route struct {
@@ -60,12 +60,12 @@ func NewServer(api api.API, options ...ServerOption) *Server {
 		router: gin.Default(),
 		api:    api,
 	}
-	
+
 	// Apply options
 	for _, option := range options {
 		option(s)
 	}
-	
+
 	s.setupRoutes()
 	return s
 }
@@ -82,20 +82,20 @@ func (s *Server) GetRouter() *gin.Engine {
 
 // setupRoutes configures the API routes
 func (s *Server) setupRoutes() {
-{{range .Routes}}
-	s.router.{{.Method}}("{{.Path}}", func(c *gin.Context) {
-		{{if .HasPathParams}}
-		{{range .PathParams}}
-		{{.Name}} := c.Param("{{.Name}}")
-		{{end}}
-		{{end}}
-		s.api.{{.HandlerName}}(c{{if .HasPathParams}}, {{range $index, $param := .PathParams}}{{if $index}}, {{end}}{{.Name}}{{end}}{{end}})
-	})
+{{- range $i, $r := .Routes}}
+{{- if $i}}
 {{end}}
+	s.router.Handle({{goString .Method}}, {{goString .Path}}, func(c *gin.Context) {
+		{{- range .PathParams}}
+		{{.Var}} := c.Param({{goString .Name}})
+		{{- end}}
+		s.api.{{.HandlerName}}(c{{range .PathParams}}, {{.Var}}{{end}})
+	})
+{{- end}}
 }
 `
 
-	tmpl, err := template.New("server").Parse(serverTemplate)
+	tmpl, err := template.New("server").Funcs(templateFuncs).Parse(serverTemplate)
 	if err != nil {
 		return err
 	}
@@ -104,14 +104,15 @@ func (s *Server) setupRoutes() {
 	for path, operations := range spec.Paths {
 		for method, op := range operations {
 			ginPath := utils.ConvertPathToGin(path)
-			handlerName := utils.ToCamelCase(op.OperationID)
+			handlerName := utils.ToGoIdentifier(op.OperationID)
 
 			var pathParams []PathParam
 			for _, param := range op.Parameters {
 				if param.In == "path" {
 					pathParams = append(pathParams, PathParam{
 						Name: param.Name,
-						Type: utils.GetGoType(param.Schema),
+						Type: goTypeOf(param.Schema),
+						Var:  goParamName(param.Name),
 					})
 				}
 			}
@@ -136,11 +137,5 @@ func (s *Server) setupRoutes() {
 		Routes:      routes,
 	}
 
-	f, err := os.Create(filepath.Join(baseDir, "server", "server.go"))
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	return tmpl.Execute(f, data)
+	return writeGoFile(filepath.Join(baseDir, "server", "server.go"), tmpl, data, moduleName)
 }
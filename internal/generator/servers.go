@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+)
+
+// serverVariablePattern matches the {variables} of a server URL
+var serverVariablePattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// serverInfo is the entry of servers the code is generated for
+type serverInfo struct {
+	URL      string // with its variables substituted, empty if the spec has no servers
+	BasePath string // path of URL the API is mounted at, such as /v2, empty for the root
+}
+
+// BaseURL returns the URL clients call by default, or "" if the server URL
+// is relative to the spec and so has no host to call
+func (s serverInfo) BaseURL() string {
+	u, err := url.Parse(s.URL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+	return strings.TrimRight(s.URL, "/")
+}
+
+// ServerBasePath returns the path of the server URL of spec that selector
+// picks, as the generated server mounts the API at it, or "" for the root.
+// selector and vars are those of Config.Server and Config.ServerVariables.
+func ServerBasePath(spec *models.OpenAPISpec, selector string, vars map[string]string) (string, error) {
+	server, err := resolveServer(spec, selector, vars)
+	if err != nil {
+		return "", err
+	}
+	return server.BasePath, nil
+}
+
+// resolveServer returns the server of spec that selector picks, by its index
+// in servers, its URL or its description, or the first one if selector is
+// empty. Variables in its URL take their value from vars, falling back to
+// their default.
+func resolveServer(spec *models.OpenAPISpec, selector string, vars map[string]string) (serverInfo, error) {
+	if len(spec.Servers) == 0 {
+		if selector != "" {
+			return serverInfo{}, fmt.Errorf("server %q selected, but the spec declares no servers", selector)
+		}
+		if len(vars) > 0 {
+			return serverInfo{}, fmt.Errorf("server variables given, but the spec declares no servers")
+		}
+		return serverInfo{}, nil
+	}
+
+	server, err := selectServer(spec.Servers, selector)
+	if err != nil {
+		return serverInfo{}, err
+	}
+
+	for name := range vars {
+		if _, ok := server.Variables[name]; !ok {
+			return serverInfo{}, fmt.Errorf("server %s has no variable %q", server.URL, name)
+		}
+	}
+
+	var substErr error
+	resolved := serverVariablePattern.ReplaceAllStringFunc(server.URL, func(match string) string {
+		name := match[1 : len(match)-1]
+		variable, ok := server.Variables[name]
+		if !ok {
+			substErr = fmt.Errorf("server %s uses variable %q without declaring it", server.URL, name)
+			return match
+		}
+		value, ok := vars[name]
+		if !ok {
+			value = variable.Default
+		}
+		if len(variable.Enum) > 0 && !slices.Contains(variable.Enum, value) {
+			substErr = fmt.Errorf("server variable %q is %q, want one of %s", name, value, strings.Join(variable.Enum, ", "))
+		}
+		return value
+	})
+	if substErr != nil {
+		return serverInfo{}, substErr
+	}
+
+	u, err := url.Parse(resolved)
+	if err != nil {
+		return serverInfo{}, fmt.Errorf("invalid server URL %s: %w", resolved, err)
+	}
+
+	return serverInfo{
+		URL:      resolved,
+		BasePath: strings.TrimSuffix("/"+strings.Trim(u.Path, "/"), "/"),
+	}, nil
+}
+
+// selectServer returns the entry of servers selector picks
+func selectServer(servers []models.Server, selector string) (models.Server, error) {
+	if selector == "" {
+		return servers[0], nil
+	}
+	if i, err := strconv.Atoi(selector); err == nil {
+		if i < 0 || i >= len(servers) {
+			return models.Server{}, fmt.Errorf("server index %d out of range, the spec declares %d servers", i, len(servers))
+		}
+		return servers[i], nil
+	}
+	for _, server := range servers {
+		if server.URL == selector || server.Description == selector {
+			return server, nil
+		}
+	}
+	return models.Server{}, fmt.Errorf("no server has the URL or description %q", selector)
+}
@@ -0,0 +1,226 @@
+package generator
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// docsHTML is the self-contained API reference page served by WithSpecRoutes.
+// It is written for gopenapi rather than vendored from Swagger UI or Redoc:
+// it outlines operations and schemas, and its "Try it" form sends requests
+// without credentials.
+//
+//go:embed assets/docs.html
+var docsHTML []byte
+
+// GenerateSpec embeds the OpenAPI document, as JSON and YAML, and the API
+// reference page into the generated server package
+func GenerateSpec(spec *models.OpenAPISpec, baseDir string, moduleName string) error {
+	specTemplate := `// Code generated by gopenapi. DO NOT EDIT.
+
+package server
+
+import (
+	_ "embed"
+)
+
+//go:embed openapi.json
+var specJSON []byte
+
+//go:embed openapi.yaml
+var specYAML []byte
+
+//go:embed docs.html
+var docsHTML []byte
+
+// specPaths are the paths SpecHandler serves
+var specPaths = []string{"/openapi.json", "/openapi.yaml", "/docs"}
+
+// SpecJSON returns the OpenAPI document the server was generated from as JSON
+func SpecJSON() []byte {
+	return specJSON
+}
+
+// SpecYAML returns the OpenAPI document the server was generated from as YAML
+func SpecYAML() []byte {
+	return specYAML
+}
+
+// SpecHandler serves the OpenAPI document at /openapi.json and /openapi.yaml
+// and an API reference page at /docs. The page needs no network access; its
+// "Try it" form sends requests without credentials.
+func SpecHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		var contentType string
+		var body []byte
+		switch r.URL.Path {
+		case "/openapi.json":
+			contentType, body = "application/json", specJSON
+		case "/openapi.yaml":
+			contentType, body = "application/yaml", specYAML
+		case "/docs":
+			contentType, body = "text/html; charset=utf-8", docsHTML
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(body)
+		}
+	})
+}
+`
+
+	jsonDoc, yamlDoc, err := specDocuments(spec)
+	if err != nil {
+		return fmt.Errorf("embedding spec: %w", err)
+	}
+
+	serverDir := filepath.Join(baseDir, "generated", "server")
+	files := map[string][]byte{
+		"openapi.json": jsonDoc,
+		"openapi.yaml": yamlDoc,
+		"docs.html":    docsHTML,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(serverDir, name), content, 0600); err != nil {
+			return err
+		}
+	}
+
+	tmpl, err := template.New("spec").Parse(specTemplate)
+	if err != nil {
+		return err
+	}
+
+	return writeGoFile(filepath.Join(serverDir, "spec.go"), tmpl, nil, moduleName)
+}
+
+// specDocuments returns the spec as a JSON and a YAML document, keeping the
+// key order of the document it was parsed from. Specs that weren't parsed
+// from a document are rendered from the model.
+func specDocuments(spec *models.OpenAPISpec) ([]byte, []byte, error) {
+	source := spec.Raw
+	if len(source) == 0 {
+		var err error
+		if source, err = json.Marshal(spec); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// YAML is a superset of JSON, so either format parses into a node tree
+	var doc yaml.Node
+	if err := yaml.Unmarshal(source, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	var compact bytes.Buffer
+	if err := writeJSONNode(&compact, &doc); err != nil {
+		return nil, nil, err
+	}
+	var jsonDoc bytes.Buffer
+	if err := json.Indent(&jsonDoc, compact.Bytes(), "", "  "); err != nil {
+		return nil, nil, err
+	}
+	jsonDoc.WriteByte('\n')
+
+	// Let the encoder pick block style and quoting instead of keeping the
+	// flow style and quotes of a JSON source
+	clearNodeStyle(&doc)
+	var yamlDoc bytes.Buffer
+	enc := yaml.NewEncoder(&yamlDoc)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	return jsonDoc.Bytes(), yamlDoc.Bytes(), nil
+}
+
+// writeJSONNode writes node to buf as compact JSON
+func writeJSONNode(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return writeJSONNode(buf, node.Content[0])
+	case yaml.AliasNode:
+		return writeJSONNode(buf, node.Alias)
+	case yaml.MappingNode:
+		buf.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(node.Content[i].Value)
+			if err != nil {
+				return err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := writeJSONNode(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeJSONNode(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		// Scalars other than null, booleans and numbers, timestamps
+		// included, stay strings as written
+		var value interface{} = node.Value
+		switch node.ShortTag() {
+		case "!!null":
+			value = nil
+		case "!!bool", "!!int", "!!float":
+			if err := node.Decode(&value); err != nil {
+				return err
+			}
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		buf.Write(data)
+	}
+	return nil
+}
+
+// clearNodeStyle removes flow and quoting styles from node and its children
+func clearNodeStyle(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle
+	for _, child := range node.Content {
+		clearNodeStyle(child)
+	}
+}
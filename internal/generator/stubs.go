@@ -0,0 +1,325 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// handlerStub represents a handler method generated into handlers/
+type handlerStub struct {
+	HandlerName string
+	Comment     string
+	Parameters  string
+	ExampleCode string
+}
+
+// handlerImpl describes the user's implementation of api.APIHandlers, or of
+// one tag's interface when handlers are split by tag
+type handlerImpl struct {
+	Package  string
+	TypeName string
+	Receiver string                        // receiver name used by existing methods
+	Pointer  bool                          // whether existing methods use a pointer receiver
+	Methods  map[string]*handlerMethodDecl // methods already declared on the type
+}
+
+// handlerMethodDecl locates a method declaration in the user's handlers
+type handlerMethodDecl struct {
+	File   string
+	Line   int
+	Params *ast.FieldList
+	// Start and End are the byte offsets of the parameter list, parentheses included
+	Start int
+	End   int
+}
+
+// addMissingHandlerStubs appends stubs for the handler methods the user's
+// implementation in handlersDir doesn't have yet. Existing files are never
+// modified; the stubs go to a new file.
+func addMissingHandlerStubs(handlersDir string, methods []handlerStub, moduleName string, backend *Backend) error {
+	impl, err := parseHandlerImpl(handlersDir)
+	if err != nil {
+		return fmt.Errorf("adding handlers for new operations: %w", err)
+	}
+	if impl == nil {
+		return fmt.Errorf("adding handlers for new operations: %s declares no type implementing api.APIHandlers", handlersDir)
+	}
+
+	return writeMissingStubs(handlersDir, "stubs", impl, methods, moduleName, backend)
+}
+
+// addMissingTagHandlerStubs appends stubs for the handler methods missing
+// from the user's implementation of each tag's interface, to one new file
+// per tag. It returns the groups the package has no implementation type for
+// yet, which need a handlers file of their own.
+func addMissingTagHandlerStubs(handlersDir string, groups []tagGroup, methods []handlerStub, moduleName string, backend *Backend) ([]tagGroup, error) {
+	pkg, err := parseHandlerPackage(handlersDir)
+	if err != nil {
+		return nil, fmt.Errorf("adding handlers for new operations: %w", err)
+	}
+
+	var undeclared []tagGroup
+	for _, group := range groups {
+		impl := pkg.impl(group.Name)
+		if impl == nil {
+			undeclared = append(undeclared, group)
+			continue
+		}
+		err := writeMissingStubs(handlersDir, group.baseName()+"_stubs", impl, groupStubs(group, methods), moduleName, backend)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(undeclared) == len(groups) {
+		// Handlers generated before splitting by tag implement APIHandlers
+		// as a whole, so they keep getting stubs that way
+		if impl := pkg.apiImpl(); impl != nil {
+			return nil, writeMissingStubs(handlersDir, "stubs", impl, methods, moduleName, backend)
+		}
+	}
+	return undeclared, nil
+}
+
+// writeMissingStubs writes stubs for the methods impl doesn't have yet to a
+// new file in handlersDir named after base
+func writeMissingStubs(handlersDir string, base string, impl *handlerImpl, methods []handlerStub, moduleName string, backend *Backend) error {
+	stubsTemplate := `package {{.Package}}
+
+import (
+	"net/http"
+{{- range .Imports}}
+	{{goString .}}
+{{- end}}
+)
+
+// Handlers for operations added to the OpenAPI spec since the handlers were
+// last generated. Move them to any file in this package.
+{{range .Methods}}
+// {{.HandlerName}} {{comment .Comment}}
+func ({{$.Receiver}}) {{.HandlerName}}({{$.ContextParams}}{{.Parameters}}){{if $.HandlerResult}} {{$.HandlerResult}}{{end}} {
+	{{.ExampleCode}}
+}
+{{end}}`
+
+	var missing []handlerStub
+	for _, method := range methods {
+		if impl.Methods[method.HandlerName] == nil {
+			missing = append(missing, method)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i].HandlerName < missing[j].HandlerName })
+
+	tmpl, err := template.New("stubs").Funcs(templateFuncs).Parse(stubsTemplate)
+	if err != nil {
+		return err
+	}
+
+	receiver := stubReceiver(impl.Receiver, backend, missing) + " "
+	if impl.Pointer {
+		receiver += "*"
+	}
+	receiver += impl.TypeName
+
+	data := struct {
+		Package       string
+		Receiver      string
+		ContextParams string
+		HandlerResult string
+		Imports       []string
+		Methods       []handlerStub
+	}{
+		Package:       impl.Package,
+		Receiver:      receiver,
+		ContextParams: backend.ContextParams,
+		HandlerResult: backend.HandlerResult,
+		Imports:       backend.Imports,
+		Methods:       missing,
+	}
+
+	return writeGoFile(newStubsPath(handlersDir, base), tmpl, data, moduleName)
+}
+
+// stubReceiver returns the receiver name of the stubs for methods: the one
+// the user's methods use, or h if it would collide with a parameter of the
+// stubs or a package their bodies use
+func stubReceiver(receiver string, backend *Backend, methods []handlerStub) string {
+	taken := knownImports("module")
+	params := backend.ContextParams
+	for _, method := range methods {
+		params += method.Parameters
+	}
+	for _, param := range strings.Split(params, ",") {
+		if fields := strings.Fields(param); len(fields) > 0 {
+			taken[fields[0]] = ""
+		}
+	}
+
+	if _, ok := taken[receiver]; !ok {
+		return receiver
+	}
+	name := "h"
+	for {
+		if _, ok := taken[name]; !ok {
+			return name
+		}
+		name += "h"
+	}
+}
+
+// handlerPackage is the types and methods declared in the user's handlers package
+type handlerPackage struct {
+	Name  string
+	Types map[string]bool
+	Impls map[string]*handlerImpl // types with methods, by name
+}
+
+// impl returns the implementation named typeName, or nil if the package
+// doesn't declare such a type
+func (p *handlerPackage) impl(typeName string) *handlerImpl {
+	if !p.Types[typeName] {
+		return nil
+	}
+	impl := p.Impls[typeName]
+	if impl == nil {
+		impl = &handlerImpl{TypeName: typeName, Pointer: true, Methods: map[string]*handlerMethodDecl{}}
+	}
+	impl.Package = p.Name
+	if impl.Receiver == "" {
+		impl.Receiver = "h"
+	}
+	return impl
+}
+
+// parseHandlerImpl finds the type implementing api.APIHandlers in the user's
+// handlers package. It returns nil if the package declares no such type.
+func parseHandlerImpl(handlersDir string) (*handlerImpl, error) {
+	pkg, err := parseHandlerPackage(handlersDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return pkg.apiImpl(), nil
+}
+
+// apiImpl returns the type implementing api.APIHandlers as a whole: the
+// conventional APIHandlers type, or else the type with the most methods
+func (p *handlerPackage) apiImpl() *handlerImpl {
+	if p.Types["APIHandlers"] {
+		return p.impl("APIHandlers")
+	}
+	var best *handlerImpl
+	for _, impl := range p.Impls {
+		if !p.Types[impl.TypeName] {
+			continue
+		}
+		if best == nil || len(impl.Methods) > len(best.Methods) ||
+			(len(impl.Methods) == len(best.Methods) && impl.TypeName < best.TypeName) {
+			best = impl
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return p.impl(best.TypeName)
+}
+
+// parseHandlerPackage parses the types and methods of the user's handlers package
+func parseHandlerPackage(handlersDir string) (*handlerPackage, error) {
+	entries, err := os.ReadDir(handlersDir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	pkgName := ""
+	types := map[string]bool{}
+	impls := map[string]*handlerImpl{}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		filename := filepath.Join(handlersDir, name)
+		file, err := parser.ParseFile(fset, filename, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		pkgName = file.Name.Name
+
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						types[ts.Name.Name] = true
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv == nil || len(d.Recv.List) == 0 {
+					continue
+				}
+				typeName, pointer := receiverType(d.Recv.List[0].Type)
+				if typeName == "" {
+					continue
+				}
+				impl := impls[typeName]
+				if impl == nil {
+					impl = &handlerImpl{TypeName: typeName, Methods: map[string]*handlerMethodDecl{}}
+					impls[typeName] = impl
+				}
+				impl.Methods[d.Name.Name] = &handlerMethodDecl{
+					File:   filename,
+					Line:   fset.Position(d.Pos()).Line,
+					Params: d.Type.Params,
+					Start:  fset.Position(d.Type.Params.Opening).Offset,
+					End:    fset.Position(d.Type.Params.Closing).Offset + 1,
+				}
+				impl.Pointer = impl.Pointer || pointer
+				if names := d.Recv.List[0].Names; len(names) > 0 && names[0].Name != "_" {
+					impl.Receiver = names[0].Name
+				}
+			}
+		}
+	}
+
+	return &handlerPackage{Name: pkgName, Types: types, Impls: impls}, nil
+}
+
+// receiverType returns the type name of a method receiver and whether it is a pointer
+func receiverType(expr ast.Expr) (string, bool) {
+	pointer := false
+	if star, ok := expr.(*ast.StarExpr); ok {
+		pointer = true
+		expr = star.X
+	}
+	// Generic receivers such as T[K] aren't handler implementations
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name, pointer
+	}
+	return "", false
+}
+
+// newStubsPath returns a file name in handlersDir starting with base that
+// doesn't exist yet: base.go, base_2.go, ...
+func newStubsPath(handlersDir string, base string) string {
+	path := filepath.Join(handlersDir, base+".go")
+	for i := 2; ; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = filepath.Join(handlersDir, fmt.Sprintf("%s_%d.go", base, i))
+	}
+}
@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+)
+
+// defaultTag is the tag of operations that declare none
+const defaultTag = "default"
+
+// tagGroup is the operations sharing a tag when handlers are split by tag.
+// Each group gets an interface in generated/api and an implementation of
+// the same name in handlers/.
+type tagGroup struct {
+	Name     string   // interface and implementation type, e.g. UsersHandlers
+	Tags     []string // tags mapping to Name, usually one
+	Handlers []string // handler names, sorted
+}
+
+// Comment describes the operations of the group
+func (g tagGroup) Comment() string {
+	return "handles the operations tagged " + strings.Join(g.Tags, ", ")
+}
+
+// ParamName returns the name of the group's parameter of api.NewHandlers,
+// such as usersHandlers or apiTagHandlers
+func (g tagGroup) ParamName() string {
+	runes := []rune(g.Name)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	// The last capital of an initialism such as API starts the next word
+	if upper > 1 && upper < len(runes) && unicode.IsLower(runes[upper]) {
+		upper--
+	}
+	for i := 0; i < max(upper, 1); i++ {
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// FileBase returns the name of the group's handlers file without the .go
+// extension. The _handlers suffix keeps tags such as "linux" or "test" from
+// turning the file into a build-constrained or test file.
+func (g tagGroup) FileBase() string {
+	return g.baseName() + "_handlers"
+}
+
+// baseName returns the group's name in snake case without the Handlers suffix
+func (g tagGroup) baseName() string {
+	return snakeCase(strings.TrimSuffix(g.Name, "Handlers"))
+}
+
+// operationTag returns the tag op is grouped under: its first tag, or
+// defaultTag if it has none
+func operationTag(op models.Operation) string {
+	if len(op.Tags) > 0 && op.Tags[0] != "" {
+		return op.Tags[0]
+	}
+	return defaultTag
+}
+
+// tagInterfaceName returns the name of the interface handling the operations
+// tagged tag
+func tagInterfaceName(tag string) string {
+	name := utils.ToGoIdentifier(tag) + "Handlers"
+	// APIHandlers is the interface embedding every group's
+	if name == "APIHandlers" {
+		name = "APITagHandlers"
+	}
+	return name
+}
+
+// tagGroups returns the operations of spec grouped by tag, sorted by name.
+// Tags that map to the same Go identifier share a group.
+func tagGroups(spec *models.OpenAPISpec) []tagGroup {
+	byName := map[string]*tagGroup{}
+	for _, entry := range sortedOperations(spec) {
+		tag := operationTag(entry.Operation)
+		name := tagInterfaceName(tag)
+		group := byName[name]
+		if group == nil {
+			group = &tagGroup{Name: name}
+			byName[name] = group
+		}
+		if !slices.Contains(group.Tags, tag) {
+			group.Tags = append(group.Tags, tag)
+		}
+		group.Handlers = append(group.Handlers, utils.ToGoIdentifier(entry.Operation.OperationID))
+	}
+
+	groups := make([]tagGroup, 0, len(byName))
+	for _, group := range byName {
+		sort.Strings(group.Tags)
+		sort.Strings(group.Handlers)
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+// snakeCase converts a Go identifier such as BillingAccounts to
+// billing_accounts
+func snakeCase(ident string) string {
+	runes := []rune(ident)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,400 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+	"github.com/shubhamku044/gopenapi/pkg/validate"
+)
+
+// GenerateValidation generates the request validator: the validate package in
+// generated/validate/ and the rules of each operation in generated/server/
+func GenerateValidation(spec *models.OpenAPISpec, baseDir string, moduleName string) error {
+	err := writeRuntimeFiles(validate.Files, filepath.Join(baseDir, "generated", "validate"))
+	if err != nil {
+		return err
+	}
+
+	validationTemplate := `// Code generated by gopenapi. DO NOT EDIT.
+
+package server
+
+// componentSchemas are the component schemas of the spec, by name
+var componentSchemas = map[string]*validate.Schema{
+{{- range .Schemas}}
+	{{goString .Name}}: {{.Literal}},
+{{- end}}
+}
+
+// operationRules are the parameters, request body and responses of each
+// operation, by handler name
+var operationRules = map[string]validate.Operation{
+{{- range .Operations}}
+	{{goString .HandlerName}}: {
+{{- if .Params}}
+		Params: []validate.Param{
+{{- range .Params}}
+			{{.}},
+{{- end}}
+		},
+{{- end}}
+{{- if .Body}}
+		Body: {{.Body}},
+{{- end}}
+{{- if .Responses}}
+		Responses: map[string]*validate.Response{
+{{- range .Responses}}
+			{{goString .Status}}: {{.Literal}},
+{{- end}}
+		},
+{{- end}}
+	},
+{{- end}}
+}
+
+// Validator returns a validator checking requests and responses against the
+// operations the spec declares for each route
+func Validator() *validate.Validator {
+	methods := api.GetAPIMethods()
+	operations := make([]validate.Operation, 0, len(methods))
+	for _, m := range methods {
+		op := operationRules[m.HandlerName]
+		op.Method, op.Path, op.Name = m.Method, m.Path, m.HandlerName
+		operations = append(operations, op)
+	}
+	return validate.New(componentSchemas, operations)
+}
+`
+
+	tmpl, err := template.New("validation").Funcs(templateFuncs).Parse(validationTemplate)
+	if err != nil {
+		return err
+	}
+
+	type schemaEntry struct {
+		Name    string
+		Literal string
+	}
+	type responseRules struct {
+		Status  string
+		Literal string
+	}
+	type operationRules struct {
+		HandlerName string
+		Params      []string
+		Body        string
+		Responses   []responseRules
+	}
+
+	var data struct {
+		Schemas    []schemaEntry
+		Operations []operationRules
+	}
+
+	names := make([]string, 0, len(spec.Components.Schemas))
+	for name := range spec.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		schema := spec.Components.Schemas[name]
+		literal, err := schemaLiteral(&schema)
+		if err != nil {
+			return fmt.Errorf("schema %s: %w", name, err)
+		}
+		data.Schemas = append(data.Schemas, schemaEntry{Name: name, Literal: literal})
+	}
+
+	for _, entry := range sortedOperations(spec) {
+		op := entry.Operation
+		rules := operationRules{HandlerName: utils.ToGoIdentifier(op.OperationID)}
+
+		for _, param := range op.Parameters {
+			literal, err := paramLiteral(param)
+			if err != nil {
+				return fmt.Errorf("%s %s: parameter %s: %w", entry.Method, entry.Path, param.Name, err)
+			}
+			rules.Params = append(rules.Params, literal)
+		}
+
+		if op.RequestBody != nil && len(op.RequestBody.Content) > 0 {
+			literal, err := bodyLiteral(op.RequestBody)
+			if err != nil {
+				return fmt.Errorf("%s %s: request body: %w", entry.Method, entry.Path, err)
+			}
+			rules.Body = literal
+		}
+
+		statuses := make([]string, 0, len(op.Responses))
+		for status := range op.Responses {
+			statuses = append(statuses, status)
+		}
+		sort.Strings(statuses)
+		for _, status := range statuses {
+			fields, err := contentFields(op.Responses[status].Content)
+			if err != nil {
+				return fmt.Errorf("%s %s: response %s: %w", entry.Method, entry.Path, status, err)
+			}
+			rules.Responses = append(rules.Responses, responseRules{
+				Status:  status,
+				Literal: "{" + strings.Join(fields, ", ") + "}",
+			})
+		}
+
+		data.Operations = append(data.Operations, rules)
+	}
+
+	return writeGoFile(filepath.Join(baseDir, "generated", "server", "validation.go"), tmpl, data, moduleName)
+}
+
+// paramLiteral returns param as a validate.Param composite literal
+func paramLiteral(param models.Parameter) (string, error) {
+	schema, err := schemaLiteral(&param.Schema)
+	if err != nil {
+		return "", err
+	}
+
+	// Only the form style, the default for query and cookie parameters,
+	// repeats array values by default
+	explode := param.In == "query" || param.In == "cookie"
+	if param.Style != "" {
+		explode = param.Style == "form"
+	}
+	if param.Explode != nil {
+		explode = *param.Explode
+	}
+
+	fields := []string{
+		"Name: " + strconv.Quote(param.Name),
+		"In: " + strconv.Quote(param.In),
+	}
+	if param.Required || param.In == pathParameterType {
+		fields = append(fields, "Required: true")
+	}
+	if explode {
+		fields = append(fields, "Explode: true")
+	}
+	fields = append(fields, "Schema: "+schema)
+	return "{" + strings.Join(fields, ", ") + "}", nil
+}
+
+// bodyLiteral returns body as a validate.Body composite literal
+func bodyLiteral(body *models.RequestBody) (string, error) {
+	fields, err := contentFields(body.Content)
+	if err != nil {
+		return "", err
+	}
+	if body.Required {
+		fields = append([]string{"Required: true"}, fields...)
+	}
+	return "&validate.Body{" + strings.Join(fields, ", ") + "}", nil
+}
+
+// contentFields returns the ContentTypes and Schema fields of a validate.Body
+// or validate.Response literal for content
+func contentFields(content map[string]models.MediaType) ([]string, error) {
+	if len(content) == 0 {
+		return nil, nil
+	}
+
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, strconv.Quote(mediaType))
+	}
+	sort.Strings(mediaTypes)
+
+	fields := []string{"ContentTypes: []string{" + strings.Join(mediaTypes, ", ") + "}"}
+	if schema := jsonSchema(content); schema != nil {
+		literal, err := schemaLiteral(schema)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, "Schema: "+literal)
+	}
+	return fields, nil
+}
+
+// schemaLiteral returns schema as a *validate.Schema expression
+func schemaLiteral(schema *models.Schema) (string, error) {
+	if schema.Ref != "" {
+		return "&validate.Schema{Ref: " + strconv.Quote(schemaRefName(schema.Ref)) + "}", nil
+	}
+
+	var fields []string
+	addString := func(name, value string) {
+		if value != "" {
+			fields = append(fields, name+": "+strconv.Quote(value))
+		}
+	}
+	addFloat := func(name string, value *float64) {
+		if value != nil {
+			fields = append(fields, name+": validate.Float("+strconv.FormatFloat(*value, 'g', -1, 64)+")")
+		}
+	}
+	addInt := func(name string, value *int) {
+		if value != nil {
+			fields = append(fields, name+": validate.Int("+strconv.Itoa(*value)+")")
+		}
+	}
+	addSchemas := func(name string, schemas []models.Schema) error {
+		if len(schemas) == 0 {
+			return nil
+		}
+		literals := make([]string, len(schemas))
+		for i := range schemas {
+			literal, err := schemaLiteral(&schemas[i])
+			if err != nil {
+				return err
+			}
+			literals[i] = literal
+		}
+		fields = append(fields, name+": []*validate.Schema{"+strings.Join(literals, ", ")+"}")
+		return nil
+	}
+
+	addString("Type", schema.Type)
+	addString("Format", schema.Format)
+	if schema.Nullable {
+		fields = append(fields, "Nullable: true")
+	}
+	if len(schema.Enum) > 0 {
+		values := make([]string, len(schema.Enum))
+		for i, value := range schema.Enum {
+			literal, err := valueLiteral(value)
+			if err != nil {
+				return "", fmt.Errorf("enum: %w", err)
+			}
+			values[i] = literal
+		}
+		fields = append(fields, "Enum: []interface{}{"+strings.Join(values, ", ")+"}")
+	}
+
+	if len(schema.Properties) > 0 {
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		properties := make([]string, len(names))
+		for i, name := range names {
+			prop := schema.Properties[name]
+			literal, err := schemaLiteral(&prop)
+			if err != nil {
+				return "", fmt.Errorf("property %s: %w", name, err)
+			}
+			properties[i] = strconv.Quote(name) + ": " + literal
+		}
+		fields = append(fields, "Properties: map[string]*validate.Schema{"+strings.Join(properties, ", ")+"}")
+	}
+	if len(schema.Required) > 0 {
+		required := make([]string, len(schema.Required))
+		for i, name := range schema.Required {
+			required[i] = strconv.Quote(name)
+		}
+		fields = append(fields, "Required: []string{"+strings.Join(required, ", ")+"}")
+	}
+	if schema.AdditionalProperties != nil {
+		fields = append(fields, "AdditionalProperties: validate.Bool("+strconv.FormatBool(*schema.AdditionalProperties)+")")
+	}
+
+	if schema.Items != nil {
+		literal, err := schemaLiteral(schema.Items)
+		if err != nil {
+			return "", fmt.Errorf("items: %w", err)
+		}
+		fields = append(fields, "Items: "+literal)
+	}
+	for _, composition := range []struct {
+		name    string
+		schemas []models.Schema
+	}{
+		{"AllOf", schema.AllOf},
+		{"OneOf", schema.OneOf},
+		{"AnyOf", schema.AnyOf},
+	} {
+		if err := addSchemas(composition.name, composition.schemas); err != nil {
+			return "", err
+		}
+	}
+	if schema.Not != nil {
+		literal, err := schemaLiteral(schema.Not)
+		if err != nil {
+			return "", fmt.Errorf("not: %w", err)
+		}
+		fields = append(fields, "Not: "+literal)
+	}
+
+	addFloat("Minimum", schema.Minimum)
+	addFloat("Maximum", schema.Maximum)
+	addFloat("MultipleOf", schema.MultipleOf)
+	addInt("MinLength", schema.MinLength)
+	addInt("MaxLength", schema.MaxLength)
+	addString("Pattern", schema.Pattern)
+	addInt("MinItems", schema.MinItems)
+	addInt("MaxItems", schema.MaxItems)
+	if schema.UniqueItems {
+		fields = append(fields, "UniqueItems: true")
+	}
+
+	return "&validate.Schema{" + strings.Join(fields, ", ") + "}", nil
+}
+
+// schemaRefName returns the component name a $ref points to
+func schemaRefName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// valueLiteral returns a value decoded from the spec, such as an enum
+// value, as a Go expression
+func valueLiteral(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "nil", nil
+	case string:
+		return strconv.Quote(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		return "float64(" + strconv.FormatFloat(v, 'g', -1, 64) + ")", nil
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			literal, err := valueLiteral(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = literal
+		}
+		return "[]interface{}{" + strings.Join(items, ", ") + "}", nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		entries := make([]string, len(keys))
+		for i, key := range keys {
+			literal, err := valueLiteral(v[key])
+			if err != nil {
+				return "", err
+			}
+			entries[i] = strconv.Quote(key) + ": " + literal
+		}
+		return "map[string]interface{}{" + strings.Join(entries, ", ") + "}", nil
+	}
+	return "", fmt.Errorf("unsupported value %v of type %T", value, value)
+}
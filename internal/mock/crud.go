@@ -0,0 +1,457 @@
+package mock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+	"github.com/shubhamku044/gopenapi/pkg/validate"
+	"gopkg.in/yaml.v3"
+)
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithState makes the server stateful: requests are validated against the
+// spec, and collections inferred from path pairs such as /users and
+// /users/{id} keep their items in memory, so that an item created with POST
+// can be read back with GET, changed with PUT or PATCH, deleted and listed.
+// Other operations, and requests selecting a response with Prefer: code=...,
+// are still served with examples.
+func WithState() Option {
+	return func(s *Server) {
+		s.state = &state{
+			validator:   newValidator(s.spec),
+			collections: inferCollections(s.spec),
+			stores:      map[string]*store{},
+		}
+	}
+}
+
+// Fixtures are the items collections start with, by collection path, such
+// as /users or /users/1/posts
+type Fixtures map[string][]map[string]interface{}
+
+// LoadFixtures reads fixtures from a YAML or JSON file
+func LoadFixtures(filename string) (Fixtures, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures Fixtures
+	switch ext := strings.ToLower(filepath.Ext(filename)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &fixtures)
+	case ".json":
+		err = json.Unmarshal(data, &fixtures)
+	default:
+		return nil, fmt.Errorf("unsupported file format: %s", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return fixtures, nil
+}
+
+// state holds the items of a stateful server's collections
+type state struct {
+	validator   *validate.Validator
+	collections map[string]*collection // by collection and item path in the spec
+
+	mu     sync.Mutex
+	stores map[string]*store // by collection path, such as /users/1/posts
+}
+
+// collection is a collection of items inferred from a path whose last
+// segment is a parameter, such as /users/{id}, and its parent path
+type collection struct {
+	path     string   // collection path in the spec, such as /users
+	itemPath string   // item path in the spec, such as /users/{id}
+	segments []string // collection path segments in Gin format
+	idField  string   // property of items holding their id
+	integer  bool     // whether ids are integers
+	uuid     bool     // whether ids are UUIDs
+}
+
+// store holds the items of one collection, such as the posts of one user
+type store struct {
+	items  map[string]map[string]interface{}
+	order  []string // ids in the order items were added
+	nextID int
+}
+
+// inferCollections returns the collections of spec by collection and item
+// path
+func inferCollections(spec *models.OpenAPISpec) map[string]*collection {
+	collections := map[string]*collection{}
+	for _, itemPath := range sortedKeys(spec.Paths) {
+		slash := strings.LastIndex(strings.TrimSuffix(itemPath, "/"), "/")
+		if slash <= 0 {
+			continue
+		}
+		parent, last := itemPath[:slash], strings.TrimSuffix(itemPath[slash+1:], "/")
+		if !strings.HasPrefix(last, "{") || !strings.HasSuffix(last, "}") {
+			continue
+		}
+		param := last[1 : len(last)-1]
+
+		c := &collection{
+			path:     parent,
+			itemPath: itemPath,
+			segments: strings.Split(strings.Trim(utils.ConvertPathToGin(parent), "/"), "/"),
+			idField:  "id",
+		}
+		item := itemSchema(spec, parent, itemPath)
+		for _, name := range []string{param, "id"} {
+			if prop, ok := property(spec, item, name, 0); ok {
+				c.idField = name
+				c.integer = prop.Type == "integer"
+				c.uuid = prop.Format == "uuid"
+				break
+			}
+		}
+		collections[parent] = c
+		collections[itemPath] = c
+	}
+	return collections
+}
+
+// itemSchema returns the schema of the items of the collection at path:
+// the body of a successful GET of an item, or else of a POST to the
+// collection or a PUT of an item
+func itemSchema(spec *models.OpenAPISpec, path, itemPath string) models.Schema {
+	if op, ok := spec.Paths[itemPath]["get"]; ok {
+		for _, code := range sortedKeys(op.Responses) {
+			if strings.HasPrefix(code, "2") {
+				if schema, ok := jsonSchema(op.Responses[code].Content); ok {
+					return schema
+				}
+			}
+		}
+	}
+	for _, candidate := range []struct{ path, method string }{{path, "post"}, {itemPath, "put"}} {
+		if op, ok := spec.Paths[candidate.path][candidate.method]; ok && op.RequestBody != nil {
+			if schema, ok := jsonSchema(op.RequestBody.Content); ok {
+				return schema
+			}
+		}
+	}
+	return models.Schema{}
+}
+
+// property returns the schema of the property name of schema, following
+// references and allOf
+func property(spec *models.OpenAPISpec, schema models.Schema, name string, depth int) (models.Schema, bool) {
+	if depth > 32 {
+		return models.Schema{}, false
+	}
+	if schema.Ref != "" {
+		resolved, ok := spec.Components.Schemas[schema.Ref[strings.LastIndex(schema.Ref, "/")+1:]]
+		if !ok {
+			return models.Schema{}, false
+		}
+		return property(spec, resolved, name, depth+1)
+	}
+	if prop, ok := schema.Properties[name]; ok {
+		return prop, true
+	}
+	for _, sub := range schema.AllOf {
+		if prop, ok := property(spec, sub, name, depth+1); ok {
+			return prop, true
+		}
+	}
+	return models.Schema{}, false
+}
+
+// Seed adds fixtures to the collections of a server created WithState
+func (s *Server) Seed(fixtures Fixtures) error {
+	if s.state == nil {
+		return errors.New("fixtures need a server created with WithState")
+	}
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+
+	for _, path := range sortedKeys(fixtures) {
+		c := s.state.collectionAt(path)
+		if c == nil {
+			return fmt.Errorf("fixtures for %s: no collection in the spec", path)
+		}
+		st := s.state.store(strings.TrimSuffix(path, "/"))
+		for i, item := range fixtures[path] {
+			if _, err := c.add(st, item); err != nil {
+				return fmt.Errorf("fixtures for %s, item %d: %w", path, i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// collectionAt returns the collection whose path matches the concrete path
+// of a collection, such as /users/1/posts
+func (st *state) collectionAt(path string) *collection {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, c := range st.collections {
+		if matchSegments(c.segments, segments) {
+			return c
+		}
+	}
+	return nil
+}
+
+// store returns the store of the collection at path, creating it if needed.
+// It must be called with mu held.
+func (st *state) store(path string) *store {
+	if st.stores[path] == nil {
+		st.stores[path] = &store{items: map[string]map[string]interface{}{}, nextID: 1}
+	}
+	return st.stores[path]
+}
+
+// add adds item to st, assigning it an id if it has none, and returns its id
+func (c *collection) add(st *store, item map[string]interface{}) (string, error) {
+	if item[c.idField] == nil {
+		item[c.idField] = c.newID(st)
+	}
+	id := idKey(item[c.idField])
+	if _, exists := st.items[id]; exists {
+		return "", fmt.Errorf("%s %s already exists", c.idField, id)
+	}
+	if n, err := strconv.Atoi(id); err == nil && n >= st.nextID {
+		st.nextID = n + 1
+	}
+	st.items[id] = item
+	st.order = append(st.order, id)
+	return id, nil
+}
+
+// newID returns the id of the next item added to st without one
+func (c *collection) newID(st *store) interface{} {
+	n := st.nextID
+	st.nextID++
+	switch {
+	case c.integer:
+		return n
+	case c.uuid:
+		return fmt.Sprintf("00000000-0000-4000-8000-%012d", n)
+	}
+	return strconv.Itoa(n)
+}
+
+// idKey returns the key an item is stored under for the value of its id
+func idKey(id interface{}) string {
+	switch v := id.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		return v
+	}
+	return fmt.Sprint(id)
+}
+
+// list returns the items of st in the order they were added
+func (st *store) list() []interface{} {
+	items := make([]interface{}, len(st.order))
+	for i, id := range st.order {
+		items[i] = st.items[id]
+	}
+	return items
+}
+
+// remove removes the item with id from st
+func (st *store) remove(id string) {
+	delete(st.items, id)
+	for i, existing := range st.order {
+		if existing == id {
+			st.order = append(st.order[:i], st.order[i+1:]...)
+			return
+		}
+	}
+}
+
+// serveState validates r and, unless examples are preferred, serves it from
+// the collections if rt is the list, create, read, update or delete
+// operation of one. It reports whether r was handled.
+func (s *Server) serveState(w http.ResponseWriter, r *http.Request, rt *route, preferExamples bool) bool {
+	err := s.state.validator.ValidateRequest(r)
+	var reqErr *validate.RequestError
+	if errors.As(err, &reqErr) {
+		validate.WriteError(w, reqErr)
+		return true
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return true
+	}
+
+	c := s.state.collections[rt.path]
+	if c == nil || preferExamples {
+		return false
+	}
+	status, response, err := selectResponse(rt.op, "")
+	if err != nil {
+		return false
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if rt.path == c.path {
+		switch r.Method {
+		case http.MethodGet:
+			s.state.mu.Lock()
+			items := s.state.store(path).list()
+			s.state.mu.Unlock()
+			writeJSON(w, status, response, s.listBody(response, items))
+			return true
+		case http.MethodPost:
+			item, ok := decodeItem(w, r)
+			if !ok {
+				return true
+			}
+			s.state.mu.Lock()
+			_, err := c.add(s.state.store(path), item)
+			s.state.mu.Unlock()
+			if err != nil {
+				writeError(w, http.StatusConflict, "%v", err)
+				return true
+			}
+			writeJSON(w, status, response, item)
+			return true
+		}
+		return false
+	}
+
+	slash := strings.LastIndex(path, "/")
+	collectionPath, id := path[:slash], path[slash+1:]
+
+	s.state.mu.Lock()
+	defer s.state.mu.Unlock()
+	st := s.state.store(collectionPath)
+	existing, found := st.items[id]
+
+	switch r.Method {
+	case http.MethodGet, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		if !found {
+			if _, _, err := selectResponse(rt.op, "404"); err != nil {
+				writeError(w, http.StatusNotFound, "%s %s not found", c.idField, id)
+				return true
+			}
+			s.serveExample(w, r, rt, "404", "")
+			return true
+		}
+	default:
+		return false
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, status, response, existing)
+	case http.MethodPut, http.MethodPatch:
+		item, ok := decodeItem(w, r)
+		if !ok {
+			return true
+		}
+		if r.Method == http.MethodPatch {
+			for key, value := range item {
+				existing[key] = value
+			}
+			item = existing
+		}
+		// The id in the path wins over the body's
+		item[c.idField] = existing[c.idField]
+		st.items[id] = item
+		writeJSON(w, status, response, item)
+	case http.MethodDelete:
+		st.remove(id)
+		writeJSON(w, status, response, existing)
+	}
+	return true
+}
+
+// listBody returns the body listing items: the items themselves if the
+// response is an array, or else an example of the response with its array
+// property holding them, as in {"items": [...], "total": 2}
+func (s *Server) listBody(response models.Response, items []interface{}) interface{} {
+	schema, ok := jsonSchema(response.Content)
+	if !ok {
+		return items
+	}
+	if schema.Ref != "" {
+		if resolved, ok := s.spec.Components.Schemas[schema.Ref[strings.LastIndex(schema.Ref, "/")+1:]]; ok {
+			schema = resolved
+		}
+	}
+	if schemaType(schema) != "object" {
+		return items
+	}
+
+	body, ok := s.examples.Value(schema).(map[string]interface{})
+	if !ok {
+		return items
+	}
+	for _, name := range sortedKeys(schema.Properties) {
+		if schemaType(schema.Properties[name]) == "array" {
+			body[name] = items
+			break
+		}
+	}
+	for _, name := range []string{"total", "count", "totalCount", "total_count"} {
+		if _, ok := body[name]; ok {
+			body[name] = len(items)
+		}
+	}
+	return body
+}
+
+// schemaType returns the type of schema, inferred from its keywords if it
+// declares none
+func schemaType(schema models.Schema) string {
+	switch {
+	case schema.Type != "":
+		return schema.Type
+	case len(schema.Properties) > 0:
+		return "object"
+	case schema.Items != nil:
+		return "array"
+	}
+	return ""
+}
+
+// decodeItem decodes the JSON object in the body of r, writing an error if
+// it isn't one
+func decodeItem(w http.ResponseWriter, r *http.Request) (map[string]interface{}, bool) {
+	var item map[string]interface{}
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&item); err != nil || item == nil {
+		writeError(w, http.StatusBadRequest, "the body must be a JSON object")
+		return nil, false
+	}
+	return item, true
+}
+
+// writeJSON writes body with status, leaving it out if the response
+// declares no content
+func writeJSON(w http.ResponseWriter, status int, response models.Response, body interface{}) {
+	if len(response.Content) == 0 {
+		w.WriteHeader(status)
+		return
+	}
+	mediaType := "application/json"
+	for _, declared := range sortedKeys(response.Content) {
+		if isJSON(declared) {
+			mediaType = declared
+			break
+		}
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
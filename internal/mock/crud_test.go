@@ -0,0 +1,365 @@
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shubhamku044/gopenapi/internal/parser"
+)
+
+const crudSpec = `
+openapi: 3.0.0
+info:
+  title: CRUD API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      operationId: list_users
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  items:
+                    type: array
+                    items:
+                      $ref: '#/components/schemas/User'
+                  total:
+                    type: integer
+    post:
+      operationId: create_user
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewUser'
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+  /users/{userId}:
+    get:
+      operationId: get_user
+      parameters:
+        - {name: userId, in: path, required: true, schema: {type: integer}}
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+        '404':
+          description: Not found
+          content:
+            application/json:
+              example: {message: no such user}
+    put:
+      operationId: replace_user
+      parameters:
+        - {name: userId, in: path, required: true, schema: {type: integer}}
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewUser'
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+    patch:
+      operationId: update_user
+      parameters:
+        - {name: userId, in: path, required: true, schema: {type: integer}}
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                email: {type: string, format: email}
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+    delete:
+      operationId: delete_user
+      parameters:
+        - {name: userId, in: path, required: true, schema: {type: integer}}
+      responses:
+        '204':
+          description: Deleted
+  /users/{userId}/posts:
+    get:
+      operationId: list_posts
+      parameters:
+        - {name: userId, in: path, required: true, schema: {type: integer}}
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: object
+                  properties:
+                    id: {type: string, format: uuid}
+                    title: {type: string}
+    post:
+      operationId: create_post
+      parameters:
+        - {name: userId, in: path, required: true, schema: {type: integer}}
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                title: {type: string}
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json: {}
+  /users/{userId}/posts/{postId}:
+    get:
+      operationId: get_post
+      parameters:
+        - {name: userId, in: path, required: true, schema: {type: integer}}
+        - {name: postId, in: path, required: true, schema: {type: string}}
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id: {type: string, format: uuid}
+                  title: {type: string}
+  /health:
+    get:
+      operationId: health
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              example: {status: ok}
+components:
+  schemas:
+    NewUser:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, minLength: 1}
+        email: {type: string, format: email}
+    User:
+      allOf:
+        - type: object
+          required: [id]
+          properties:
+            id: {type: integer}
+        - $ref: '#/components/schemas/NewUser'
+`
+
+func crudServer(t *testing.T) *Server {
+	t.Helper()
+	specFile := filepath.Join(t.TempDir(), "api.yaml")
+	if err := os.WriteFile(specFile, []byte(crudSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := parser.ParseSpecFile(specFile)
+	if err != nil {
+		t.Fatalf("ParseSpecFile failed: %v", err)
+	}
+	return New(spec, WithState())
+}
+
+// call sends a request with a JSON body to s and decodes the JSON response
+func call(t *testing.T, s *Server, method, path, body string, header http.Header) (int, interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	var decoded interface{}
+	if rec.Body.Len() > 0 {
+		if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+			t.Fatalf("%s %s: invalid JSON response %q: %v", method, path, rec.Body, err)
+		}
+	}
+	return rec.Code, decoded
+}
+
+func TestStatefulCRUD(t *testing.T) {
+	s := crudServer(t)
+
+	steps := []struct {
+		method string
+		path   string
+		body   string
+		status int
+		want   interface{}
+	}{
+		{"GET", "/users", "", 200, map[string]interface{}{"items": []interface{}{}, "total": float64(0)}},
+		{"POST", "/users", `{"name": "Ada"}`, 201, map[string]interface{}{"id": float64(1), "name": "Ada"}},
+		{"POST", "/users", `{"name": "Grace", "email": "grace@example.com"}`, 201, map[string]interface{}{"id": float64(2), "name": "Grace", "email": "grace@example.com"}},
+		{"GET", "/users/1", "", 200, map[string]interface{}{"id": float64(1), "name": "Ada"}},
+		{"PUT", "/users/1", `{"id": 7, "name": "Ada Lovelace"}`, 200, map[string]interface{}{"id": float64(1), "name": "Ada Lovelace"}},
+		{"PATCH", "/users/1", `{"email": "ada@example.com"}`, 200, map[string]interface{}{"id": float64(1), "name": "Ada Lovelace", "email": "ada@example.com"}},
+		{"DELETE", "/users/2", "", 204, nil},
+		{"GET", "/users/2", "", 404, map[string]interface{}{"message": "no such user"}},
+		{"DELETE", "/users/2", "", 404, nil},
+		{"GET", "/users", "", 200, map[string]interface{}{
+			"items": []interface{}{map[string]interface{}{"id": float64(1), "name": "Ada Lovelace", "email": "ada@example.com"}},
+			"total": float64(1),
+		}},
+		{"POST", "/users", `{"id": 1, "name": "Duplicate"}`, 409, nil},
+	}
+
+	for _, step := range steps {
+		status, got := call(t, s, step.method, step.path, step.body, nil)
+		if status != step.status {
+			t.Fatalf("%s %s: expected status %d, got %d: %v", step.method, step.path, step.status, status, got)
+		}
+		if step.want != nil && !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s %s: expected %v, got %v", step.method, step.path, step.want, got)
+		}
+	}
+}
+
+func TestStatefulValidation(t *testing.T) {
+	s := crudServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"MissingRequired", "POST", "/users", `{"email": "ada@example.com"}`, http.StatusUnprocessableEntity},
+		{"InvalidFormat", "PATCH", "/users/1", `{"email": "not an email"}`, http.StatusUnprocessableEntity},
+		{"MalformedBody", "POST", "/users", `{"name":`, http.StatusBadRequest},
+		{"InvalidPathParameter", "GET", "/users/abc", "", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, got := call(t, s, tt.method, tt.path, tt.body, nil)
+			if status != tt.status {
+				t.Fatalf("Expected status %d, got %d: %v", tt.status, status, got)
+			}
+			if body, ok := got.(map[string]interface{}); !ok || body["errors"] == nil {
+				t.Errorf("Expected a list of validation errors, got %v", got)
+			}
+		})
+	}
+}
+
+func TestStatefulNestedCollections(t *testing.T) {
+	s := crudServer(t)
+
+	status, created := call(t, s, "POST", "/users/1/posts", `{"title": "Hello"}`, nil)
+	if status != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %v", status, created)
+	}
+	id := created.(map[string]interface{})["id"]
+	if id != "00000000-0000-4000-8000-000000000001" {
+		t.Errorf("Expected a UUID id, got %v", id)
+	}
+
+	if status, got := call(t, s, "GET", "/users/1/posts/"+id.(string), "", nil); status != http.StatusOK || !reflect.DeepEqual(got, created) {
+		t.Errorf("Expected %v, got %d %v", created, status, got)
+	}
+	if status, got := call(t, s, "GET", "/users/2/posts", "", nil); status != http.StatusOK || !reflect.DeepEqual(got, []interface{}{}) {
+		t.Errorf("Expected another user's posts to be empty, got %d %v", status, got)
+	}
+}
+
+func TestStatefulFallsBackToExamples(t *testing.T) {
+	s := crudServer(t)
+
+	if status, got := call(t, s, "GET", "/health", "", nil); status != http.StatusOK || !reflect.DeepEqual(got, map[string]interface{}{"status": "ok"}) {
+		t.Errorf("Expected the example of a non-CRUD operation, got %d %v", status, got)
+	}
+
+	status, got := call(t, s, "GET", "/users/1", "", http.Header{"Prefer": {"code=404"}})
+	if status != http.StatusNotFound || !reflect.DeepEqual(got, map[string]interface{}{"message": "no such user"}) {
+		t.Errorf("Expected the 404 example, got %d %v", status, got)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	dir := t.TempDir()
+	fixturesFile := filepath.Join(dir, "fixtures.yaml")
+	fixtures := `
+/users:
+  - {id: 3, name: Ada}
+  - {id: 10, name: Grace}
+/users/3/posts:
+  - {id: p1, title: First}
+`
+	if err := os.WriteFile(fixturesFile, []byte(fixtures), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadFixtures(fixturesFile)
+	if err != nil {
+		t.Fatalf("LoadFixtures failed: %v", err)
+	}
+	s := crudServer(t)
+	if err := s.Seed(loaded); err != nil {
+		t.Fatalf("Seed failed: %v", err)
+	}
+
+	if status, got := call(t, s, "GET", "/users/10", "", nil); status != http.StatusOK || got.(map[string]interface{})["name"] != "Grace" {
+		t.Errorf("Expected the seeded user, got %d %v", status, got)
+	}
+	if status, got := call(t, s, "GET", "/users/3/posts/p1", "", nil); status != http.StatusOK || got.(map[string]interface{})["title"] != "First" {
+		t.Errorf("Expected the seeded post, got %d %v", status, got)
+	}
+	// New ids continue after the seeded ones
+	if _, got := call(t, s, "POST", "/users", `{"name": "Linus"}`, nil); got.(map[string]interface{})["id"] != float64(11) {
+		t.Errorf("Expected id 11, got %v", got)
+	}
+
+	if err := s.Seed(Fixtures{"/pets": {{"name": "Rex"}}}); err == nil || !strings.Contains(err.Error(), "no collection") {
+		t.Errorf("Expected an error seeding an unknown collection, got %v", err)
+	}
+	if err := s.Seed(Fixtures{"/users": {{"id": 3}}}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected an error seeding a duplicate id, got %v", err)
+	}
+	if err := New(s.spec).Seed(loaded); err == nil {
+		t.Error("Expected an error seeding a server without state")
+	}
+}
@@ -0,0 +1,390 @@
+// Package mock serves the operations of an OpenAPI spec with the examples it
+// declares, or with bodies synthesized from their schemas where it declares
+// none, so that clients can be built before the API is implemented.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shubhamku044/gopenapi/internal/examples"
+	"github.com/shubhamku044/gopenapi/internal/models"
+	"github.com/shubhamku044/gopenapi/pkg/utils"
+)
+
+// Server is an http.Handler responding to every operation of a spec
+type Server struct {
+	spec     *models.OpenAPISpec
+	routes   []route
+	examples *examples.Generator
+	state    *state // nil unless created WithState
+	basePath string // path the operations are served under, such as /v2
+}
+
+// route is an operation with its path split into segments
+type route struct {
+	method   string
+	path     string   // path in the spec, such as /users/{id}
+	segments []string // path segments in Gin format, :id for parameters
+	literal  int      // literal segments, to prefer concrete paths
+	op       models.Operation
+}
+
+// New returns a mock server for the operations of spec
+func New(spec *models.OpenAPISpec, opts ...Option) *Server {
+	s := &Server{spec: spec, examples: examples.New(spec.Components.Schemas, 0)}
+	for path, methods := range spec.Paths {
+		segments := strings.Split(strings.Trim(utils.ConvertPathToGin(path), "/"), "/")
+		literal := 0
+		for _, segment := range segments {
+			if !strings.HasPrefix(segment, ":") {
+				literal++
+			}
+		}
+		for method, op := range methods {
+			s.routes = append(s.routes, route{
+				method:   strings.ToUpper(method),
+				path:     path,
+				segments: segments,
+				literal:  literal,
+				op:       op,
+			})
+		}
+	}
+	// Sorting keeps matching deterministic between equally concrete paths
+	sort.Slice(s.routes, func(i, j int) bool {
+		if s.routes[i].path != s.routes[j].path {
+			return s.routes[i].path < s.routes[j].path
+		}
+		return s.routes[i].method < s.routes[j].method
+	})
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// WithSeed derives the values synthesized for responses without examples from
+// seed instead of 0. The same seed always produces the same values.
+func WithSeed(seed uint64) Option {
+	return func(s *Server) {
+		s.examples = examples.New(s.spec.Components.Schemas, seed)
+	}
+}
+
+// WithBasePath serves the operations under path, such as /v2, the path of
+// the server URL the spec declares. Requests outside of it get 404.
+func WithBasePath(path string) Option {
+	return func(s *Server) {
+		s.basePath = strings.TrimSuffix("/"+strings.Trim(path, "/"), "/")
+	}
+}
+
+// Routes returns the method and path of every operation served, sorted by path
+func (s *Server) Routes() []string {
+	routes := make([]string, len(s.routes))
+	for i, rt := range s.routes {
+		routes[i] = rt.method + " " + s.basePath + rt.path
+	}
+	return routes
+}
+
+// ServeHTTP responds to r with an example response of the operation it
+// matches, or from the collections of a server created WithState. The
+// Prefer header selects the example response, as in "Prefer: code=404" or
+// "Prefer: example=notFound", and the Accept header its media type.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.basePath != "" {
+		path, ok := strings.CutPrefix(r.URL.Path, s.basePath)
+		if !ok || path != "" && !strings.HasPrefix(path, "/") {
+			writeError(w, http.StatusNotFound, "no operation serves %s %s", r.Method, r.URL.Path)
+			return
+		}
+		r = r.Clone(r.Context())
+		r.URL.Path = "/" + strings.TrimPrefix(path, "/")
+		r.URL.RawPath = ""
+	}
+
+	rt, allowed := s.match(r)
+	if rt == nil {
+		if len(allowed) > 0 {
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			writeError(w, http.StatusMethodNotAllowed, "method %s not allowed for %s", r.Method, r.URL.Path)
+			return
+		}
+		writeError(w, http.StatusNotFound, "no operation serves %s %s", r.Method, r.URL.Path)
+		return
+	}
+
+	prefer := parsePrefer(r.Header.Values("Prefer"))
+	if s.state != nil && s.serveState(w, r, rt, prefer["code"] != "") {
+		return
+	}
+	s.serveExample(w, r, rt, prefer["code"], prefer["example"])
+}
+
+// serveExample responds to r with an example response of rt, the one with
+// the status code in code if it is set, and the example named example if
+// it is set
+func (s *Server) serveExample(w http.ResponseWriter, r *http.Request, rt *route, code, example string) {
+	status, response, err := selectResponse(rt.op, code)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "%s %s: %v", rt.method, rt.path, err)
+		return
+	}
+
+	if len(response.Content) == 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	mediaType, ok := negotiate(response.Content, r.Header.Get("Accept"))
+	if !ok {
+		writeError(w, http.StatusNotAcceptable, "%s %s responds with %s", rt.method, rt.path, strings.Join(sortedKeys(response.Content), ", "))
+		return
+	}
+
+	value, err := s.example(response.Content[mediaType], example)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "%s %s: %v", rt.method, rt.path, err)
+		return
+	}
+
+	body, err := encode(mediaType, value)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "%s %s: encoding the example: %v", rt.method, rt.path, err)
+		return
+	}
+
+	if !strings.Contains(mediaType, "*") {
+		w.Header().Set("Content-Type", mediaType)
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// match returns the route serving r, preferring the path with the most
+// literal segments. If none does, it returns the methods allowed for the
+// path, if any.
+func (s *Server) match(r *http.Request) (*route, []string) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	var best *route
+	var allowed []string
+	for i := range s.routes {
+		rt := &s.routes[i]
+		if !matchSegments(rt.segments, segments) {
+			continue
+		}
+		if rt.method != r.Method {
+			allowed = append(allowed, rt.method)
+			continue
+		}
+		if best == nil || rt.literal > best.literal {
+			best = rt
+		}
+	}
+	return best, allowed
+}
+
+// matchSegments reports whether the segments of a request path match the
+// segments of a route
+func matchSegments(route, path []string) bool {
+	if len(route) != len(path) {
+		return false
+	}
+	for i, segment := range route {
+		if strings.HasPrefix(segment, ":") {
+			if path[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// selectResponse returns the response of op with the requested status code,
+// or its first success response if code is empty. Responses declared as a
+// range, such as 4XX, or as default serve any code they cover.
+func selectResponse(op models.Operation, code string) (int, models.Response, error) {
+	if code != "" {
+		status, err := strconv.Atoi(code)
+		if err != nil || status < 100 || status > 599 {
+			return 0, models.Response{}, fmt.Errorf("invalid status code %q in the Prefer header", code)
+		}
+		for _, key := range []string{code, code[:1] + "XX", code[:1] + "xx", "default"} {
+			if response, ok := op.Responses[key]; ok {
+				return status, response, nil
+			}
+		}
+		return 0, models.Response{}, fmt.Errorf("no response with status %s is declared", code)
+	}
+
+	codes := sortedKeys(op.Responses)
+	for _, key := range codes {
+		if strings.HasPrefix(key, "2") {
+			status, err := strconv.Atoi(key)
+			if err != nil {
+				status = http.StatusOK // 2XX
+			}
+			return status, op.Responses[key], nil
+		}
+	}
+	if response, ok := op.Responses["default"]; ok {
+		return http.StatusOK, response, nil
+	}
+	for _, key := range codes {
+		if status, err := strconv.Atoi(key); err == nil {
+			return status, op.Responses[key], nil
+		}
+	}
+	return http.StatusNoContent, models.Response{}, nil
+}
+
+// negotiate returns the media type of content the Accept header prefers.
+// Without an Accept header, JSON is preferred.
+func negotiate(content map[string]models.MediaType, accept string) (string, bool) {
+	mediaTypes := sortedKeys(content)
+	// JSON first, so that it wins ties
+	sort.SliceStable(mediaTypes, func(i, j int) bool {
+		return isJSON(mediaTypes[i]) && !isJSON(mediaTypes[j])
+	})
+	if strings.TrimSpace(accept) == "" {
+		return mediaTypes[0], true
+	}
+
+	ranges := parseAccept(accept)
+	best, bestQ := "", 0.0
+	for _, mediaType := range mediaTypes {
+		if q := acceptQuality(ranges, mediaType); q > bestQ {
+			best, bestQ = mediaType, q
+		}
+	}
+	return best, best != ""
+}
+
+// acceptRange is a media range of an Accept header with its quality
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept parses an Accept header
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType, q})
+	}
+	return ranges
+}
+
+// acceptQuality returns the quality of the most specific range of ranges
+// matching mediaType, or 0 if none does
+func acceptQuality(ranges []acceptRange, mediaType string) float64 {
+	mediaType, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return 0
+	}
+	kind, _, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+	for _, r := range ranges {
+		rangeKind, rangeSub, _ := strings.Cut(r.mediaType, "/")
+		var s int
+		switch {
+		case r.mediaType == mediaType:
+			s = 2
+		case rangeKind == kind && rangeSub == "*":
+			s = 1
+		case r.mediaType == "*/*":
+			s = 0
+		default:
+			continue
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q
+}
+
+// parsePrefer parses the preferences of Prefer headers, such as code=404
+func parsePrefer(headers []string) map[string]string {
+	prefer := map[string]string{}
+	for _, header := range headers {
+		for _, part := range strings.FieldsFunc(header, func(r rune) bool { return r == ',' || r == ';' }) {
+			name, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+			prefer[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return prefer
+}
+
+// example returns the example body of content: the named example if name is
+// set, the media type's example, its first named example or one synthesized
+// from its schema
+func (s *Server) example(content models.MediaType, name string) (interface{}, error) {
+	if name != "" {
+		example, ok := content.Examples[name]
+		if !ok {
+			return nil, fmt.Errorf("no example named %q", name)
+		}
+		return example.Value, nil
+	}
+	return s.examples.MediaType(content), nil
+}
+
+// encode encodes value as a body of mediaType. Strings are written as they
+// are to non-JSON media types, anything else as JSON.
+func encode(mediaType string, value interface{}) ([]byte, error) {
+	if str, ok := value.(string); ok && !isJSON(mediaType) {
+		return []byte(str), nil
+	}
+	body, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return append(body, '\n'), nil
+}
+
+// isJSON reports whether mediaType is JSON, such as application/json or
+// application/problem+json
+func isJSON(mediaType string) bool {
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	mediaType = strings.TrimSpace(mediaType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// writeError writes an error of the mock server itself as JSON
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf(format, args...)})
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
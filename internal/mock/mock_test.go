@@ -0,0 +1,300 @@
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/shubhamku044/gopenapi/internal/examples"
+	"github.com/shubhamku044/gopenapi/internal/parser"
+)
+
+const testSpec = `
+openapi: 3.0.0
+info:
+  title: Mock API
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      operationId: list_users
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/User'
+    post:
+      operationId: create_user
+      responses:
+        '201':
+          description: Created
+          content:
+            application/json:
+              examples:
+                jane:
+                  value: {id: 2, name: Jane}
+                john:
+                  value: {id: 1, name: John}
+  /users/{id}:
+    get:
+      operationId: get_user
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              example: {id: 42, name: Ada}
+            text/plain:
+              example: Ada
+        '404':
+          description: Not found
+          content:
+            application/problem+json:
+              example: {title: Not Found}
+        5XX:
+          description: Server error
+    delete:
+      operationId: delete_user
+      responses:
+        '204':
+          description: Deleted
+  /users/me:
+    get:
+      operationId: me
+      responses:
+        default:
+          description: The current user
+          content:
+            application/json:
+              example: {id: 0, name: Me}
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, name, role]
+      properties:
+        id:
+          type: string
+          format: uuid
+        name:
+          type: string
+          minLength: 8
+        role:
+          type: string
+          enum: [admin, member]
+        age:
+          type: integer
+          minimum: 18
+          multipleOf: 5
+        score:
+          type: number
+          example: 9.5
+        active:
+          type: boolean
+        tags:
+          type: array
+          minItems: 2
+          items:
+            type: string
+        manager:
+          $ref: '#/components/schemas/User'
+`
+
+func testServer(t *testing.T) *Server {
+	t.Helper()
+	specFile := filepath.Join(t.TempDir(), "api.yaml")
+	if err := os.WriteFile(specFile, []byte(testSpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := parser.ParseSpecFile(specFile)
+	if err != nil {
+		t.Fatalf("ParseSpecFile failed: %v", err)
+	}
+	return New(spec)
+}
+
+func serve(s *Server, method, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServeExamples(t *testing.T) {
+	s := testServer(t)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		header      http.Header
+		status      int
+		contentType string
+		body        string
+	}{
+		{
+			name: "Example", method: "GET", path: "/users/42",
+			status: 200, contentType: "application/json", body: `{"id":42,"name":"Ada"}`,
+		},
+		{
+			name: "LiteralPathWins", method: "GET", path: "/users/me",
+			status: 200, contentType: "application/json", body: `{"id":0,"name":"Me"}`,
+		},
+		{
+			name: "FirstNamedExample", method: "POST", path: "/users",
+			status: 201, contentType: "application/json", body: `{"id":2,"name":"Jane"}`,
+		},
+		{
+			name: "PreferExample", method: "POST", path: "/users",
+			header: http.Header{"Prefer": {"example=john"}},
+			status: 201, contentType: "application/json", body: `{"id":1,"name":"John"}`,
+		},
+		{
+			name: "PreferCode", method: "GET", path: "/users/42",
+			header: http.Header{"Prefer": {"code=404"}},
+			status: 404, contentType: "application/problem+json", body: `{"title":"Not Found"}`,
+		},
+		{
+			name: "PreferCodeRange", method: "GET", path: "/users/42",
+			header: http.Header{"Prefer": {"code=503, example=ignored"}},
+			status: 503,
+		},
+		{
+			name: "Accept", method: "GET", path: "/users/42",
+			header: http.Header{"Accept": {"application/json;q=0.5, text/*"}},
+			status: 200, contentType: "text/plain", body: "Ada",
+		},
+		{
+			name: "NoContent", method: "DELETE", path: "/users/42",
+			status: 204,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, tt.method, tt.path, tt.header)
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Expected content type %q, got %q", tt.contentType, got)
+			}
+			if got := string(rec.Body.Bytes()); trimNewline(got) != tt.body {
+				t.Errorf("Expected body %s, got %s", tt.body, got)
+			}
+		})
+	}
+}
+
+func TestServeErrors(t *testing.T) {
+	s := testServer(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header http.Header
+		status int
+	}{
+		{name: "UnknownPath", method: "GET", path: "/pets", status: http.StatusNotFound},
+		{name: "ExtraSegment", method: "GET", path: "/users/42/posts", status: http.StatusNotFound},
+		{name: "MethodNotAllowed", method: "PUT", path: "/users/42", status: http.StatusMethodNotAllowed},
+		{name: "NotAcceptable", method: "GET", path: "/users/42", header: http.Header{"Accept": {"image/png"}}, status: http.StatusNotAcceptable},
+		{name: "UndeclaredCode", method: "GET", path: "/users/42", header: http.Header{"Prefer": {"code=401"}}, status: http.StatusInternalServerError},
+		{name: "UnknownExample", method: "POST", path: "/users", header: http.Header{"Prefer": {"example=nobody"}}, status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(s, tt.method, tt.path, tt.header)
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["error"] == "" {
+				t.Errorf("Expected a JSON error, got %s", rec.Body)
+			}
+		})
+	}
+
+	if allow := serve(s, "PUT", "/users/42", nil).Header().Get("Allow"); allow != "DELETE, GET" {
+		t.Errorf("Expected Allow: DELETE, GET, got %q", allow)
+	}
+}
+
+func TestSynthesize(t *testing.T) {
+	s := testServer(t)
+	schema := s.spec.Paths["/users"]["get"].Responses["200"].Content["application/json"].Schema
+
+	for _, seed := range []uint64{0, 7} {
+		rec := serve(New(s.spec, WithSeed(seed)), "GET", "/users", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+		}
+
+		var users []map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &users); err != nil {
+			t.Fatalf("Expected a JSON array: %v", err)
+		}
+		want, _ := json.Marshal(examples.New(s.spec.Components.Schemas, seed).Value(schema))
+		if got, _ := json.Marshal(users); string(got) != string(want) {
+			t.Errorf("Seed %d: expected %s, got %s", seed, want, got)
+		}
+		for _, user := range users {
+			if user["score"] != 9.5 || user["name"] == nil || user["role"] == nil {
+				t.Errorf("Seed %d: expected the example score and the required properties, got %v", seed, user)
+			}
+		}
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	want := []string{"GET /users", "POST /users", "GET /users/me", "DELETE /users/{id}", "GET /users/{id}"}
+	if got := testServer(t).Routes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestBasePath(t *testing.T) {
+	s := New(testServer(t).spec, WithBasePath("/v2/"))
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{method: "GET", path: "/v2/users/42", status: http.StatusOK},
+		{method: "GET", path: "/v2/users/me", status: http.StatusOK},
+		{method: "POST", path: "/v2/users", status: http.StatusCreated},
+		{method: "GET", path: "/users/42", status: http.StatusNotFound},
+		{method: "GET", path: "/v2users", status: http.StatusNotFound},
+		{method: "GET", path: "/v2", status: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if rec := serve(s, tt.method, tt.path, nil); rec.Code != tt.status {
+			t.Errorf("%s %s: expected status %d, got %d: %s", tt.method, tt.path, tt.status, rec.Code, rec.Body)
+		}
+	}
+
+	if routes := s.Routes(); routes[0] != "GET /v2/users" {
+		t.Errorf("Expected routes under the base path, got %v", routes)
+	}
+}
+
+func trimNewline(s string) string {
+	if len(s) > 0 && s[len(s)-1] == '\n' {
+		return s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,127 @@
+package mock
+
+import (
+	"strings"
+
+	"github.com/shubhamku044/gopenapi/internal/models"
+	"github.com/shubhamku044/gopenapi/pkg/validate"
+)
+
+// newValidator returns a validator of requests to the operations of spec,
+// applying the rules the generated server's request validation applies
+func newValidator(spec *models.OpenAPISpec) *validate.Validator {
+	schemas := make(map[string]*validate.Schema, len(spec.Components.Schemas))
+	for name, schema := range spec.Components.Schemas {
+		schemas[name] = convertSchema(schema)
+	}
+
+	var operations []validate.Operation
+	for _, path := range sortedKeys(spec.Paths) {
+		for _, method := range sortedKeys(spec.Paths[path]) {
+			op := spec.Paths[path][method]
+			operation := validate.Operation{
+				Method: strings.ToUpper(method),
+				Path:   path,
+				Name:   op.OperationID,
+			}
+			for _, param := range op.Parameters {
+				operation.Params = append(operation.Params, convertParam(param))
+			}
+			if op.RequestBody != nil && len(op.RequestBody.Content) > 0 {
+				operation.Body = &validate.Body{
+					Required:     op.RequestBody.Required,
+					ContentTypes: sortedKeys(op.RequestBody.Content),
+				}
+				if schema, ok := jsonSchema(op.RequestBody.Content); ok {
+					operation.Body.Schema = convertSchema(schema)
+				}
+			}
+			operations = append(operations, operation)
+		}
+	}
+	return validate.New(schemas, operations)
+}
+
+// convertParam converts a parameter to its validation rules
+func convertParam(param models.Parameter) validate.Param {
+	// Only the form style, the default for query and cookie parameters,
+	// repeats array values by default
+	explode := param.In == "query" || param.In == "cookie"
+	if param.Style != "" {
+		explode = param.Style == "form"
+	}
+	if param.Explode != nil {
+		explode = *param.Explode
+	}
+	return validate.Param{
+		Name:     param.Name,
+		In:       param.In,
+		Required: param.Required || param.In == "path",
+		Explode:  explode,
+		Schema:   convertSchema(param.Schema),
+	}
+}
+
+// convertSchema converts a schema of the spec to the schema validate checks
+// values against
+func convertSchema(schema models.Schema) *validate.Schema {
+	if schema.Ref != "" {
+		return &validate.Schema{Ref: schema.Ref[strings.LastIndex(schema.Ref, "/")+1:]}
+	}
+
+	converted := &validate.Schema{
+		Type:                 schema.Type,
+		Format:               schema.Format,
+		Nullable:             schema.Nullable,
+		Enum:                 schema.Enum,
+		Required:             schema.Required,
+		AdditionalProperties: schema.AdditionalProperties,
+		Minimum:              schema.Minimum,
+		Maximum:              schema.Maximum,
+		MultipleOf:           schema.MultipleOf,
+		MinLength:            schema.MinLength,
+		MaxLength:            schema.MaxLength,
+		Pattern:              schema.Pattern,
+		MinItems:             schema.MinItems,
+		MaxItems:             schema.MaxItems,
+		UniqueItems:          schema.UniqueItems,
+		AllOf:                convertSchemas(schema.AllOf),
+		OneOf:                convertSchemas(schema.OneOf),
+		AnyOf:                convertSchemas(schema.AnyOf),
+	}
+	if len(schema.Properties) > 0 {
+		converted.Properties = make(map[string]*validate.Schema, len(schema.Properties))
+		for name, prop := range schema.Properties {
+			converted.Properties[name] = convertSchema(prop)
+		}
+	}
+	if schema.Items != nil {
+		converted.Items = convertSchema(*schema.Items)
+	}
+	if schema.Not != nil {
+		converted.Not = convertSchema(*schema.Not)
+	}
+	return converted
+}
+
+// convertSchemas converts the schemas of an allOf, oneOf or anyOf
+func convertSchemas(schemas []models.Schema) []*validate.Schema {
+	if len(schemas) == 0 {
+		return nil
+	}
+	converted := make([]*validate.Schema, len(schemas))
+	for i, schema := range schemas {
+		converted[i] = convertSchema(schema)
+	}
+	return converted
+}
+
+// jsonSchema returns the schema of the first JSON media type in content
+func jsonSchema(content map[string]models.MediaType) (models.Schema, bool) {
+	for _, mediaType := range sortedKeys(content) {
+		if isJSON(mediaType) {
+			return content[mediaType].Schema, true
+		}
+	}
+	return models.Schema{}, false
+}
@@ -7,22 +7,91 @@ type OpenAPISpec struct {
 		Version     string `json:"version" yaml:"version"`
 		Description string `json:"description" yaml:"description"`
 	} `json:"info" yaml:"info"`
+	Servers    []Server                        `json:"servers" yaml:"servers"`
 	Paths      map[string]map[string]Operation `json:"paths" yaml:"paths"`
-	Components struct {
-		Schemas map[string]Schema `json:"schemas" yaml:"schemas"`
-	} `json:"components" yaml:"components"`
+	Components Components                      `json:"components" yaml:"components"`
+	Security   []SecurityRequirement           `json:"security" yaml:"security"` // default for operations without their own
+	Raw        []byte                          `json:"-" yaml:"-"`               // document the spec was parsed from, if any
+}
+
+// Server represents an entry of servers, a URL the API is served at
+type Server struct {
+	URL         string                    `json:"url" yaml:"url"` // may hold {variables} and be relative to the spec
+	Description string                    `json:"description" yaml:"description"`
+	Variables   map[string]ServerVariable `json:"variables" yaml:"variables"`
+}
+
+// ServerVariable represents a variable substituted into a server URL
+type ServerVariable struct {
+	Default     string   `json:"default" yaml:"default"`
+	Enum        []string `json:"enum" yaml:"enum"` // allowed values, any if empty
+	Description string   `json:"description" yaml:"description"`
+}
+
+// Components holds the reusable objects of a spec
+type Components struct {
+	Schemas         map[string]Schema         `json:"schemas" yaml:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes" yaml:"securitySchemes"`
 }
 
 // Operation represents an API operation
 type Operation struct {
-	Method      string              // HTTP method (GET, POST, etc.) - populated during processing
-	OperationID string              `json:"operationId" yaml:"operationId"`
-	Summary     string              `json:"summary" yaml:"summary"`
-	Description string              `json:"description" yaml:"description"`
-	Parameters  []Parameter         `json:"parameters" yaml:"parameters"`
-	RequestBody *RequestBody        `json:"requestBody" yaml:"requestBody"`
-	Responses   map[string]Response `json:"responses" yaml:"responses"`
-	Tags        []string            `json:"tags" yaml:"tags"`
+	Method      string                 `json:"-" yaml:"-"` // HTTP method (GET, POST, etc.) - populated during processing
+	OperationID string                 `json:"operationId" yaml:"operationId"`
+	Summary     string                 `json:"summary" yaml:"summary"`
+	Description string                 `json:"description" yaml:"description"`
+	Parameters  []Parameter            `json:"parameters" yaml:"parameters"`
+	RequestBody *RequestBody           `json:"requestBody" yaml:"requestBody"`
+	Responses   map[string]Response    `json:"responses" yaml:"responses"`
+	Tags        []string               `json:"tags" yaml:"tags"`
+	Security    *[]SecurityRequirement `json:"security" yaml:"security"`       // nil inherits the spec's, empty disables it
+	Retryable   *bool                  `json:"x-retryable" yaml:"x-retryable"` // overrides retrying by HTTP method in the client
+	Timeout     string                 `json:"x-timeout" yaml:"x-timeout"`     // default client timeout, e.g. "5s"
+	Pagination  *Pagination            `json:"x-pagination" yaml:"x-pagination"`
+}
+
+// SecurityRequirement maps the names of security schemes that must all be
+// satisfied to the scopes each needs. An operation is allowed if any one of
+// its requirements is satisfied.
+type SecurityRequirement map[string][]string
+
+// SecurityScheme represents an authentication scheme in components.securitySchemes
+type SecurityScheme struct {
+	Type             string      `json:"type" yaml:"type"` // apiKey, http, oauth2, openIdConnect or mutualTLS
+	Description      string      `json:"description" yaml:"description"`
+	Name             string      `json:"name" yaml:"name"`     // header, query parameter or cookie of an apiKey scheme
+	In               string      `json:"in" yaml:"in"`         // header, query or cookie
+	Scheme           string      `json:"scheme" yaml:"scheme"` // HTTP authentication scheme, e.g. bearer or basic
+	BearerFormat     string      `json:"bearerFormat" yaml:"bearerFormat"`
+	Flows            *OAuthFlows `json:"flows" yaml:"flows"`
+	OpenIDConnectURL string      `json:"openIdConnectUrl" yaml:"openIdConnectUrl"`
+}
+
+// OAuthFlows are the flows an oauth2 security scheme supports
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit" yaml:"implicit"`
+	Password          *OAuthFlow `json:"password" yaml:"password"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials" yaml:"clientCredentials"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode" yaml:"authorizationCode"`
+}
+
+// OAuthFlow is an OAuth 2.0 flow with the scopes it grants
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl" yaml:"authorizationUrl"`
+	TokenURL         string            `json:"tokenUrl" yaml:"tokenUrl"`
+	RefreshURL       string            `json:"refreshUrl" yaml:"refreshUrl"`
+	Scopes           map[string]string `json:"scopes" yaml:"scopes"`
+}
+
+// Pagination describes how a list operation pages through its results
+type Pagination struct {
+	Style       string `json:"style" yaml:"style"`             // cursor, offset, page, or none to opt out
+	CursorParam string `json:"cursorParam" yaml:"cursorParam"` // query parameter taking the cursor
+	NextCursor  string `json:"nextCursor" yaml:"nextCursor"`   // response property holding the next cursor
+	OffsetParam string `json:"offsetParam" yaml:"offsetParam"`
+	LimitParam  string `json:"limitParam" yaml:"limitParam"`
+	PageParam   string `json:"pageParam" yaml:"pageParam"`
+	Items       string `json:"items" yaml:"items"` // response property holding the items, empty if the response is an array
 }
 
 // Parameter represents an API parameter
@@ -31,23 +100,36 @@ type Parameter struct {
 	In          string `json:"in" yaml:"in"`
 	Required    bool   `json:"required" yaml:"required"`
 	Description string `json:"description" yaml:"description"`
+	Style       string `json:"style" yaml:"style"`
+	Explode     *bool  `json:"explode" yaml:"explode"`
 	Schema      Schema `json:"schema" yaml:"schema"`
 }
 
 // RequestBody represents an API request body
 type RequestBody struct {
-	Required bool `json:"required" yaml:"required"`
-	Content  map[string]struct {
-		Schema Schema `json:"schema" yaml:"schema"`
-	} `json:"content" yaml:"content"`
+	Required bool                 `json:"required" yaml:"required"`
+	Content  map[string]MediaType `json:"content" yaml:"content"`
 }
 
 // Response represents an API response
 type Response struct {
-	Description string `json:"description" yaml:"description"`
-	Content     map[string]struct {
-		Schema Schema `json:"schema" yaml:"schema"`
-	} `json:"content" yaml:"content"`
+	Description string               `json:"description" yaml:"description"`
+	Content     map[string]MediaType `json:"content" yaml:"content"`
+}
+
+// MediaType describes a request or response body of one content type
+type MediaType struct {
+	Schema   Schema             `json:"schema" yaml:"schema"`
+	Example  interface{}        `json:"example" yaml:"example"`
+	Examples map[string]Example `json:"examples" yaml:"examples"`
+}
+
+// Example represents a named example of a body
+type Example struct {
+	Summary       string      `json:"summary" yaml:"summary"`
+	Description   string      `json:"description" yaml:"description"`
+	Value         interface{} `json:"value" yaml:"value"`
+	ExternalValue string      `json:"externalValue" yaml:"externalValue"` // URL of the example, not fetched
 }
 
 // Schema represents a data schema
@@ -59,10 +141,22 @@ type Schema struct {
 	Ref                  string            `json:"$ref" yaml:"$ref"`
 	Required             []string          `json:"required" yaml:"required"`
 	Description          string            `json:"description" yaml:"description"`
+	Example              interface{}       `json:"example" yaml:"example"`
+	Default              interface{}       `json:"default" yaml:"default"`
 	Enum                 []interface{}     `json:"enum" yaml:"enum"`
 	AllOf                []Schema          `json:"allOf" yaml:"allOf"`
 	OneOf                []Schema          `json:"oneOf" yaml:"oneOf"`
 	AnyOf                []Schema          `json:"anyOf" yaml:"anyOf"`
 	Not                  *Schema           `json:"not" yaml:"not"`
 	AdditionalProperties *bool             `json:"additionalProperties" yaml:"additionalProperties"`
+	Nullable             bool              `json:"nullable" yaml:"nullable"`
+	Minimum              *float64          `json:"minimum" yaml:"minimum"`
+	Maximum              *float64          `json:"maximum" yaml:"maximum"`
+	MultipleOf           *float64          `json:"multipleOf" yaml:"multipleOf"`
+	MinLength            *int              `json:"minLength" yaml:"minLength"`
+	MaxLength            *int              `json:"maxLength" yaml:"maxLength"`
+	Pattern              string            `json:"pattern" yaml:"pattern"`
+	MinItems             *int              `json:"minItems" yaml:"minItems"`
+	MaxItems             *int              `json:"maxItems" yaml:"maxItems"`
+	UniqueItems          bool              `json:"uniqueItems" yaml:"uniqueItems"`
 }
@@ -34,6 +34,8 @@ func ParseSpecFile(filePath string) (*models.OpenAPISpec, error) {
 		return nil, err
 	}
 
+	spec.Raw = data
+
 	// Process the spec to add derived fields
 	ProcessSpec(&spec)
 
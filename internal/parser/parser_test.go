@@ -317,6 +317,141 @@ components:
 		}
 	})
 
+	t.Run("SecuritySchemes", func(t *testing.T) {
+		tempDir := t.TempDir()
+		yamlContent := `
+openapi: 3.0.0
+info:
+  title: Secure API
+  version: 1.0.0
+security:
+  - bearerAuth: []
+paths:
+  /pets:
+    get:
+      operationId: list_pets
+      security:
+        - oauth: [pets:read]
+          apiKey: []
+    post:
+      operationId: create_pet
+  /health:
+    get:
+      operationId: health
+      security: []
+components:
+  securitySchemes:
+    bearerAuth:
+      type: http
+      scheme: bearer
+      bearerFormat: JWT
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+    oauth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://auth.example.com/token
+          scopes:
+            pets:read: Read pets
+    oidc:
+      type: openIdConnect
+      openIdConnectUrl: https://auth.example.com/.well-known/openid-configuration
+`
+
+		specFile := filepath.Join(tempDir, "secure.yaml")
+		if err := os.WriteFile(specFile, []byte(yamlContent), 0600); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		spec, err := ParseSpecFile(specFile)
+		if err != nil {
+			t.Fatalf("ParseSpecFile failed: %v", err)
+		}
+
+		schemes := spec.Components.SecuritySchemes
+		if len(schemes) != 4 {
+			t.Fatalf("Expected 4 security schemes, got %d", len(schemes))
+		}
+		if s := schemes["bearerAuth"]; s.Type != "http" || s.Scheme != "bearer" || s.BearerFormat != "JWT" {
+			t.Errorf("Unexpected bearerAuth scheme: %+v", s)
+		}
+		if s := schemes["apiKey"]; s.In != "header" || s.Name != "X-API-Key" {
+			t.Errorf("Unexpected apiKey scheme: %+v", s)
+		}
+		if s := schemes["oauth"]; s.Flows == nil || s.Flows.ClientCredentials == nil || s.Flows.ClientCredentials.Scopes["pets:read"] != "Read pets" {
+			t.Errorf("Unexpected oauth scheme: %+v", s)
+		}
+		if s := schemes["oidc"]; s.OpenIDConnectURL == "" {
+			t.Errorf("Unexpected oidc scheme: %+v", s)
+		}
+
+		if len(spec.Security) != 1 || spec.Security[0]["bearerAuth"] == nil {
+			t.Errorf("Unexpected global security: %v", spec.Security)
+		}
+		list := spec.Paths["/pets"]["get"].Security
+		if list == nil || len(*list) != 1 || len((*list)[0]) != 2 || (*list)[0]["oauth"][0] != "pets:read" {
+			t.Errorf("Unexpected list_pets security: %v", list)
+		}
+		if spec.Paths["/pets"]["post"].Security != nil {
+			t.Error("Expected create_pet to inherit the global security")
+		}
+		if health := spec.Paths["/health"]["get"].Security; health == nil || len(*health) != 0 {
+			t.Errorf("Expected health to disable security, got %v", health)
+		}
+	})
+
+	t.Run("Servers", func(t *testing.T) {
+		tempDir := t.TempDir()
+		yamlContent := `
+openapi: 3.0.0
+info:
+  title: Served API
+  version: 1.0.0
+servers:
+  - url: https://{region}.api.example.com/{version}
+    description: Production
+    variables:
+      region:
+        default: eu
+        enum: [eu, us]
+      version:
+        default: v2
+  - url: /v2
+paths: {}
+`
+
+		specFile := filepath.Join(tempDir, "servers.yaml")
+		if err := os.WriteFile(specFile, []byte(yamlContent), 0600); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		spec, err := ParseSpecFile(specFile)
+		if err != nil {
+			t.Fatalf("ParseSpecFile failed: %v", err)
+		}
+
+		if len(spec.Servers) != 2 {
+			t.Fatalf("Expected 2 servers, got %d", len(spec.Servers))
+		}
+		production := spec.Servers[0]
+		if production.URL != "https://{region}.api.example.com/{version}" || production.Description != "Production" {
+			t.Errorf("Unexpected production server: %+v", production)
+		}
+		region := production.Variables["region"]
+		if region.Default != "eu" || len(region.Enum) != 2 || region.Enum[1] != "us" {
+			t.Errorf("Unexpected region variable: %+v", region)
+		}
+		if production.Variables["version"].Default != "v2" {
+			t.Errorf("Unexpected version variable: %+v", production.Variables["version"])
+		}
+		if spec.Servers[1].URL != "/v2" || spec.Servers[1].Variables != nil {
+			t.Errorf("Unexpected relative server: %+v", spec.Servers[1])
+		}
+	})
+
 	t.Run("ValidJSONFile", func(t *testing.T) {
 		tempDir := t.TempDir()
 		jsonContent := `{
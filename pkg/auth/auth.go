@@ -0,0 +1,184 @@
+// Package auth enforces the security requirements of an OpenAPI spec with a
+// user-implemented Authenticator. The generator copies it into generated
+// projects as generated/auth.
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Scheme is a security scheme from components.securitySchemes
+type Scheme struct {
+	Name             string // key of the scheme in components.securitySchemes
+	Type             string // apiKey, http, oauth2, openIdConnect or mutualTLS
+	In               string // header, query or cookie, for apiKey schemes
+	ParamName        string // name of the header, query parameter or cookie, for apiKey schemes
+	HTTPScheme       string // HTTP authentication scheme, e.g. bearer or basic, for http schemes
+	BearerFormat     string
+	OpenIDConnectURL string
+	Scopes           []string // scopes the OAuth2 flows of the scheme declare
+}
+
+// SchemeRequirement is a scheme with the scopes an operation requires of it
+type SchemeRequirement struct {
+	Scheme *Scheme
+	Scopes []string
+}
+
+// Requirement is a set of schemes that must all authenticate a request. An
+// empty requirement allows anonymous requests.
+type Requirement []SchemeRequirement
+
+// Authenticator authenticates requests to the operations the spec secures
+type Authenticator interface {
+	// Authenticate checks that r carries valid credentials for scheme that
+	// grant scopes, and returns the request handlers should see, e.g. with
+	// the caller stored in its context. Errors wrapping ErrForbidden reject
+	// the request with 403 Forbidden, other errors with 401 Unauthorized.
+	Authenticate(r *http.Request, scheme *Scheme, scopes []string) (*http.Request, error)
+}
+
+// AuthenticatorFunc adapts a function to the Authenticator interface
+type AuthenticatorFunc func(r *http.Request, scheme *Scheme, scopes []string) (*http.Request, error)
+
+// Authenticate calls f
+func (f AuthenticatorFunc) Authenticate(r *http.Request, scheme *Scheme, scopes []string) (*http.Request, error) {
+	return f(r, scheme, scopes)
+}
+
+var (
+	// ErrUnauthenticated reports missing or invalid credentials
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden reports valid credentials lacking the required scopes
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Check authenticates r for an operation with requirements, any one of which
+// must be satisfied. It returns the request to pass on to the handler, or
+// writes a 401 or 403 response and returns false. Without an Authenticator,
+// secured operations fail with 500 Internal Server Error.
+func Check(a Authenticator, w http.ResponseWriter, r *http.Request, requirements []Requirement) (*http.Request, bool) {
+	if len(requirements) == 0 {
+		return r, true
+	}
+	if a == nil {
+		log.Printf("%s %s: the operation is secured but the server has no Authenticator", r.Method, r.URL.Path)
+		writeError(w, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return nil, false
+	}
+
+	forbidden := false
+	for _, requirement := range requirements {
+		authenticated, err := checkRequirement(a, r, requirement)
+		if err == nil {
+			return authenticated, true
+		}
+		if errors.Is(err, ErrForbidden) {
+			forbidden = true
+		}
+	}
+
+	if forbidden {
+		writeError(w, http.StatusForbidden, "forbidden")
+		return nil, false
+	}
+	for _, challenge := range challenges(requirements) {
+		w.Header().Add("WWW-Authenticate", challenge)
+	}
+	writeError(w, http.StatusUnauthorized, "unauthorized")
+	return nil, false
+}
+
+// checkRequirement authenticates r for every scheme of requirement
+func checkRequirement(a Authenticator, r *http.Request, requirement Requirement) (*http.Request, error) {
+	for _, need := range requirement {
+		next, err := a.Authenticate(r, need.Scheme, need.Scopes)
+		if err != nil {
+			return nil, err
+		}
+		if next != nil {
+			r = next
+		}
+	}
+	return r, nil
+}
+
+// challenges returns the WWW-Authenticate challenges of the http, oauth2 and
+// openIdConnect schemes in requirements
+func challenges(requirements []Requirement) []string {
+	seen := map[string]bool{}
+	var result []string
+	for _, requirement := range requirements {
+		for _, need := range requirement {
+			var challenge string
+			switch need.Scheme.Type {
+			case "http":
+				if need.Scheme.HTTPScheme == "" {
+					continue
+				}
+				challenge = strings.ToUpper(need.Scheme.HTTPScheme[:1]) + strings.ToLower(need.Scheme.HTTPScheme[1:])
+			case "oauth2", "openIdConnect":
+				challenge = "Bearer"
+			default:
+				continue
+			}
+			if !seen[challenge] {
+				seen[challenge] = true
+				result = append(result, challenge)
+			}
+		}
+	}
+	return result
+}
+
+// Credential returns the credential r carries for scheme: the API key of an
+// apiKey scheme, the token of a bearer, oauth2 or openIdConnect scheme, and
+// the encoded credentials of other http schemes. Use r.BasicAuth for the
+// user name and password of basic schemes.
+func Credential(r *http.Request, scheme *Scheme) (string, bool) {
+	switch scheme.Type {
+	case "apiKey":
+		switch scheme.In {
+		case "header":
+			value := r.Header.Get(scheme.ParamName)
+			return value, value != ""
+		case "query":
+			value := r.URL.Query().Get(scheme.ParamName)
+			return value, value != ""
+		case "cookie":
+			cookie, err := r.Cookie(scheme.ParamName)
+			if err != nil || cookie.Value == "" {
+				return "", false
+			}
+			return cookie.Value, true
+		}
+		return "", false
+	case "http":
+		return authorization(r, scheme.HTTPScheme)
+	case "oauth2", "openIdConnect":
+		return authorization(r, "bearer")
+	}
+	return "", false
+}
+
+// authorization returns the credentials of the Authorization header if it
+// uses the HTTP authentication scheme
+func authorization(r *http.Request, scheme string) (string, bool) {
+	kind, credentials, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(kind, scheme) {
+		return "", false
+	}
+	credentials = strings.TrimSpace(credentials)
+	return credentials, credentials != ""
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
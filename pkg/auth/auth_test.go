@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type userKey struct{}
+
+var (
+	bearer = &Scheme{Name: "bearer", Type: "http", HTTPScheme: "bearer"}
+	apiKey = &Scheme{Name: "key", Type: "apiKey", In: "header", ParamName: "X-API-Key"}
+	oauth  = &Scheme{Name: "oauth", Type: "oauth2", Scopes: []string{"pets:read", "pets:write"}}
+)
+
+// testAuthenticator accepts the bearer token "alice", who may read pets, and
+// the API key "secret"
+var testAuthenticator = AuthenticatorFunc(func(r *http.Request, scheme *Scheme, scopes []string) (*http.Request, error) {
+	credential, ok := Credential(r, scheme)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	switch {
+	case scheme.Type == "apiKey" && credential == "secret":
+		return r, nil
+	case scheme.Type != "apiKey" && credential == "alice":
+		for _, scope := range scopes {
+			if scope != "pets:read" {
+				return nil, fmt.Errorf("scope %s: %w", scope, ErrForbidden)
+			}
+		}
+		return r.WithContext(context.WithValue(r.Context(), userKey{}, credential)), nil
+	}
+	return nil, ErrUnauthenticated
+})
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		authenticator Authenticator
+		requirements  []Requirement
+		header        map[string]string
+		status        int // 0 if the request is allowed
+		challenge     string
+		user          string
+	}{
+		{name: "not secured", authenticator: nil},
+		{name: "no authenticator", requirements: []Requirement{{{Scheme: bearer}}}, status: 500},
+		{name: "bearer", requirements: []Requirement{{{Scheme: bearer}}}, header: map[string]string{"Authorization": "Bearer alice"}, user: "alice"},
+		{name: "missing", requirements: []Requirement{{{Scheme: bearer}}}, status: 401, challenge: "Bearer"},
+		{name: "wrong scheme", requirements: []Requirement{{{Scheme: bearer}}}, header: map[string]string{"Authorization": "Basic YWxpY2U="}, status: 401, challenge: "Bearer"},
+		{name: "scope granted", requirements: []Requirement{{{Scheme: oauth, Scopes: []string{"pets:read"}}}}, header: map[string]string{"Authorization": "bearer alice"}, user: "alice"},
+		{name: "scope denied", requirements: []Requirement{{{Scheme: oauth, Scopes: []string{"pets:write"}}}}, header: map[string]string{"Authorization": "Bearer alice"}, status: 403},
+		{name: "any requirement", requirements: []Requirement{{{Scheme: bearer}}, {{Scheme: apiKey}}}, header: map[string]string{"X-API-Key": "secret"}},
+		{name: "all schemes", requirements: []Requirement{{{Scheme: bearer}, {Scheme: apiKey}}}, header: map[string]string{"X-API-Key": "secret"}, status: 401, challenge: "Bearer"},
+		{name: "both schemes", requirements: []Requirement{{{Scheme: bearer}, {Scheme: apiKey}}}, header: map[string]string{"X-API-Key": "secret", "Authorization": "Bearer alice"}, user: "alice"},
+		{name: "anonymous allowed", requirements: []Requirement{{{Scheme: bearer}}, {}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator := tt.authenticator
+			if authenticator == nil && tt.status != 500 {
+				authenticator = testAuthenticator
+			}
+			r := httptest.NewRequest("GET", "/pets", nil)
+			for key, value := range tt.header {
+				r.Header.Set(key, value)
+			}
+			w := httptest.NewRecorder()
+
+			next, ok := Check(authenticator, w, r, tt.requirements)
+			if tt.status == 0 {
+				if !ok {
+					t.Fatalf("request rejected with %d %s", w.Code, w.Body.String())
+				}
+				if user, _ := next.Context().Value(userKey{}).(string); user != tt.user {
+					t.Errorf("user = %q, want %q", user, tt.user)
+				}
+				return
+			}
+			if ok || next != nil {
+				t.Fatal("request allowed")
+			}
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if challenge := w.Header().Get("WWW-Authenticate"); challenge != tt.challenge {
+				t.Errorf("WWW-Authenticate = %q, want %q", challenge, tt.challenge)
+			}
+		})
+	}
+}
+
+func TestCredential(t *testing.T) {
+	r := httptest.NewRequest("GET", "/pets?api_key=q", nil)
+	r.Header.Set("Authorization", "Bearer  token ")
+	r.Header.Set("X-API-Key", "h")
+	r.AddCookie(&http.Cookie{Name: "session", Value: "c"})
+
+	tests := []struct {
+		scheme *Scheme
+		want   string
+		ok     bool
+	}{
+		{&Scheme{Type: "apiKey", In: "header", ParamName: "X-API-Key"}, "h", true},
+		{&Scheme{Type: "apiKey", In: "query", ParamName: "api_key"}, "q", true},
+		{&Scheme{Type: "apiKey", In: "cookie", ParamName: "session"}, "c", true},
+		{&Scheme{Type: "apiKey", In: "cookie", ParamName: "missing"}, "", false},
+		{&Scheme{Type: "http", HTTPScheme: "Bearer"}, "token", true},
+		{&Scheme{Type: "http", HTTPScheme: "basic"}, "", false},
+		{&Scheme{Type: "openIdConnect"}, "token", true},
+		{&Scheme{Type: "mutualTLS"}, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Credential(r, tt.scheme)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Credential(%+v) = %q, %v, want %q, %v", tt.scheme, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,505 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"math"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// KeySet holds the keys JWTs are verified with
+type KeySet struct {
+	keys []jwtKey
+}
+
+// jwtKey is a verification key with its JWK key ID and algorithm, if any
+type jwtKey struct {
+	id  string
+	alg string
+	key interface{} // *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey or []byte
+}
+
+// LoadJWKS reads a JSON Web Key Set file
+func LoadJWKS(filename string) (*KeySet, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS parses a JSON Web Key Set with RSA, EC (P-256, P-384 and P-521),
+// OKP (Ed25519) and oct (HMAC) keys. Keys for encryption are skipped.
+func ParseJWKS(data []byte) (*KeySet, error) {
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Alg string `json:"alg"`
+			Use string `json:"use"`
+			Crv string `json:"crv"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+			K   string `json:"k"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("parsing JWKS: %w", err)
+	}
+
+	set := &KeySet{}
+	for i, k := range jwks.Keys {
+		if k.Use == "enc" {
+			continue
+		}
+
+		var key interface{}
+		var err error
+		switch k.Kty {
+		case "RSA":
+			key, err = rsaKey(k.N, k.E)
+		case "EC":
+			key, err = ecKey(k.Crv, k.X, k.Y)
+		case "OKP":
+			if k.Crv != "Ed25519" {
+				err = fmt.Errorf("unsupported curve %q", k.Crv)
+				break
+			}
+			var x []byte
+			if x, err = base64.RawURLEncoding.DecodeString(k.X); err == nil && len(x) != ed25519.PublicKeySize {
+				err = errors.New("invalid Ed25519 key size")
+			}
+			key = ed25519.PublicKey(x)
+		case "oct":
+			var secret []byte
+			if secret, err = base64.RawURLEncoding.DecodeString(k.K); err == nil && len(secret) == 0 {
+				err = errors.New("empty secret")
+			}
+			key = secret
+		default:
+			err = fmt.Errorf("unsupported key type %q", k.Kty)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("JWKS key %d (%s): %w", i, k.Kid, err)
+		}
+		set.keys = append(set.keys, jwtKey{id: k.Kid, alg: k.Alg, key: key})
+	}
+
+	if len(set.keys) == 0 {
+		return nil, errors.New("JWKS has no signing keys")
+	}
+	return set, nil
+}
+
+// LoadPEM reads a PEM file of public keys or certificates
+func LoadPEM(filename string) (*KeySet, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePEM(data)
+}
+
+// ParsePEM parses PEM encoded RSA, ECDSA and Ed25519 public keys and the
+// public keys of certificates. The keys have no key ID, so tokens are
+// checked against each key of the signing algorithm's type.
+func ParsePEM(data []byte) (*KeySet, error) {
+	set := &KeySet{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		var key interface{}
+		var err error
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+			if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+				key = cert.PublicKey
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing PEM %s: %w", block.Type, err)
+		}
+
+		switch key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+			set.keys = append(set.keys, jwtKey{key: key})
+		default:
+			return nil, fmt.Errorf("unsupported PEM key type %T", key)
+		}
+	}
+
+	if len(set.keys) == 0 {
+		return nil, errors.New("PEM data has no public keys")
+	}
+	return set, nil
+}
+
+// rsaKey decodes the modulus and exponent of an RSA JWK
+func rsaKey(n, e string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, err
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).SetBytes(eBytes)
+	if len(nBytes) == 0 || !exponent.IsInt64() || exponent.Int64() < 2 || exponent.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid RSA key")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(nBytes), E: int(exponent.Int64())}, nil
+}
+
+// ecKey decodes the curve and coordinates of an EC JWK
+func ecKey(crv, x, y string) (*ecdsa.PublicKey, error) {
+	curves := map[string]struct {
+		ecdsa elliptic.Curve
+		ecdh  ecdh.Curve
+	}{
+		"P-256": {elliptic.P256(), ecdh.P256()},
+		"P-384": {elliptic.P384(), ecdh.P384()},
+		"P-521": {elliptic.P521(), ecdh.P521()},
+	}
+	curve, ok := curves[crv]
+	if !ok {
+		return nil, fmt.Errorf("unsupported curve %q", crv)
+	}
+
+	xBytes, err := base64.RawURLEncoding.DecodeString(x)
+	if err != nil {
+		return nil, err
+	}
+	yBytes, err := base64.RawURLEncoding.DecodeString(y)
+	if err != nil {
+		return nil, err
+	}
+
+	// crypto/ecdh validates that the point is on the curve
+	size := (curve.ecdsa.Params().BitSize + 7) / 8
+	if len(xBytes) > size || len(yBytes) > size {
+		return nil, errors.New("invalid EC key")
+	}
+	point := make([]byte, 1+2*size)
+	point[0] = 4 // uncompressed SEC 1 encoding
+	copy(point[1+size-len(xBytes):1+size], xBytes)
+	copy(point[1+2*size-len(yBytes):], yBytes)
+	if _, err := curve.ecdh.NewPublicKey(point); err != nil {
+		return nil, fmt.Errorf("invalid EC key: %w", err)
+	}
+	return &ecdsa.PublicKey{Curve: curve.ecdsa, X: new(big.Int).SetBytes(xBytes), Y: new(big.Int).SetBytes(yBytes)}, nil
+}
+
+// Claims are the claims of a verified JWT
+type Claims map[string]interface{}
+
+// Subject returns the sub claim
+func (c Claims) Subject() string {
+	sub, _ := c["sub"].(string)
+	return sub
+}
+
+// Scopes returns the space-separated scope claim, or the scp claim as a
+// list or space-separated string
+func (c Claims) Scopes() []string {
+	if scope, ok := c["scope"].(string); ok {
+		return strings.Fields(scope)
+	}
+	switch scp := c["scp"].(type) {
+	case string:
+		return strings.Fields(scp)
+	case []interface{}:
+		scopes := make([]string, 0, len(scp))
+		for _, s := range scp {
+			if s, ok := s.(string); ok {
+				scopes = append(scopes, s)
+			}
+		}
+		return scopes
+	}
+	return nil
+}
+
+type claimsKey struct{}
+
+// ClaimsFromContext returns the claims of the JWT a JWTAuthenticator
+// verified for the request
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(Claims)
+	return claims, ok
+}
+
+// JWTAuthenticator authenticates bearer, oauth2 and openIdConnect schemes
+// with JWTs signed by local keys. Tokens must carry an exp claim; nbf, iss
+// and aud are checked when present or configured.
+type JWTAuthenticator struct {
+	// Keys verify token signatures
+	Keys *KeySet
+	// Issuer is the iss claim tokens must have, if set
+	Issuer string
+	// Audience lists the audiences tokens may be issued for, if set. The aud
+	// claim must contain one of them.
+	Audience []string
+	// Leeway allows for clock skew when checking exp and nbf
+	Leeway time.Duration
+	// Scopes maps the scopes in tokens to the OAuth2 scopes of the spec.
+	// Scopes without an entry are used as is.
+	Scopes map[string][]string
+	// Now returns the current time; nil uses time.Now
+	Now func() time.Time
+}
+
+// Authenticate implements Authenticator. It verifies the bearer token of r
+// and checks that it grants scopes, storing its claims in the context of
+// the returned request.
+func (a *JWTAuthenticator) Authenticate(r *http.Request, scheme *Scheme, scopes []string) (*http.Request, error) {
+	if scheme.Type == "http" && !strings.EqualFold(scheme.HTTPScheme, "bearer") {
+		return nil, fmt.Errorf("scheme %s isn't a bearer scheme: %w", scheme.Name, ErrUnauthenticated)
+	}
+	token, ok := Credential(r, scheme)
+	if !ok || scheme.Type == "apiKey" {
+		return nil, ErrUnauthenticated
+	}
+
+	claims, err := a.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	granted := map[string]bool{}
+	for _, scope := range claims.Scopes() {
+		mapped, ok := a.Scopes[scope]
+		if !ok {
+			mapped = []string{scope}
+		}
+		for _, s := range mapped {
+			granted[s] = true
+		}
+	}
+	for _, scope := range scopes {
+		if !granted[scope] {
+			return nil, fmt.Errorf("token lacks scope %q: %w", scope, ErrForbidden)
+		}
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), claimsKey{}, claims)), nil
+}
+
+// Verify checks the signature, expiry, issuer and audience of token and
+// returns its claims. Errors wrap ErrUnauthenticated.
+func (a *JWTAuthenticator) Verify(token string) (Claims, error) {
+	fail := func(format string, args ...interface{}) (Claims, error) {
+		return nil, fmt.Errorf("invalid JWT: %s: %w", fmt.Sprintf(format, args...), ErrUnauthenticated)
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fail("malformed token")
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return fail("malformed header")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return fail("malformed header")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fail("malformed signature")
+	}
+
+	if a.Keys == nil || !a.Keys.verify(header.Alg, header.Kid, []byte(parts[0]+"."+parts[1]), signature) {
+		return fail("signature not verified by any key for %s", header.Alg)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return fail("malformed claims")
+	}
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var claims Claims
+	if err := dec.Decode(&claims); err != nil || claims == nil {
+		return fail("malformed claims")
+	}
+
+	now := time.Now()
+	if a.Now != nil {
+		now = a.Now()
+	}
+	exp, ok := numericDate(claims["exp"])
+	if !ok {
+		return fail("missing exp")
+	}
+	if !now.Before(exp.Add(a.Leeway)) {
+		return fail("expired at %s", exp.Format(time.RFC3339))
+	}
+	if _, present := claims["nbf"]; present {
+		nbf, ok := numericDate(claims["nbf"])
+		if !ok {
+			return fail("malformed nbf")
+		}
+		if now.Add(a.Leeway).Before(nbf) {
+			return fail("not valid before %s", nbf.Format(time.RFC3339))
+		}
+	}
+
+	if a.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != a.Issuer {
+			return fail("issuer %q, want %q", iss, a.Issuer)
+		}
+	}
+	if len(a.Audience) > 0 && !audienceMatches(claims["aud"], a.Audience) {
+		return fail("audience %v, want one of %v", claims["aud"], a.Audience)
+	}
+
+	return claims, nil
+}
+
+// verify reports whether one of the keys for alg, and kid if set, verifies
+// signature over signed
+func (s *KeySet) verify(alg, kid string, signed, signature []byte) bool {
+	for _, k := range s.keys {
+		if (kid != "" && k.id != "" && k.id != kid) || (k.alg != "" && k.alg != alg) {
+			continue
+		}
+		if verifySignature(alg, k.key, signed, signature) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifySignature verifies a JWS signature. The key type must match the
+// algorithm, so public keys are never used as HMAC secrets.
+func verifySignature(alg string, key interface{}, signed, signature []byte) bool {
+	if len(alg) < 5 {
+		return false
+	}
+	var hashFunc crypto.Hash
+	var newHash func() hash.Hash
+	switch alg[len(alg)-3:] {
+	case "256":
+		hashFunc, newHash = crypto.SHA256, sha256.New
+	case "384":
+		hashFunc, newHash = crypto.SHA384, sha512.New384
+	case "512":
+		hashFunc, newHash = crypto.SHA512, sha512.New
+	}
+
+	switch alg {
+	case "RS256", "RS384", "RS512", "PS256", "PS384", "PS512":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return false
+		}
+		h := newHash()
+		h.Write(signed)
+		if alg[0] == 'P' {
+			return rsa.VerifyPSS(pub, hashFunc, h.Sum(nil), signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+		}
+		return rsa.VerifyPKCS1v15(pub, hashFunc, h.Sum(nil), signature) == nil
+	case "ES256", "ES384", "ES512":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return false
+		}
+		curves := map[string]elliptic.Curve{"ES256": elliptic.P256(), "ES384": elliptic.P384(), "ES512": elliptic.P521()}
+		size := (curves[alg].Params().BitSize + 7) / 8
+		if pub.Curve != curves[alg] || len(signature) != 2*size {
+			return false
+		}
+		h := newHash()
+		h.Write(signed)
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(pub, h.Sum(nil), r, s)
+	case "EdDSA":
+		pub, ok := key.(ed25519.PublicKey)
+		return ok && ed25519.Verify(pub, signed, signature)
+	case "HS256", "HS384", "HS512":
+		secret, ok := key.([]byte)
+		if !ok {
+			return false
+		}
+		mac := hmac.New(newHash, secret)
+		mac.Write(signed)
+		return hmac.Equal(mac.Sum(nil), signature)
+	}
+	return false
+}
+
+// numericDate converts a JWT NumericDate claim to a time
+func numericDate(value interface{}) (time.Time, bool) {
+	n, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := n.Float64()
+	if err != nil || math.IsNaN(seconds) || math.Abs(seconds) > 1e15 {
+		return time.Time{}, false
+	}
+	whole, frac := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(frac*1e9)), true
+}
+
+// audienceMatches reports whether the aud claim, a string or a list,
+// contains one of audience
+func audienceMatches(aud interface{}, audience []string) bool {
+	var values []string
+	switch aud := aud.(type) {
+	case string:
+		values = []string{aud}
+	case []interface{}:
+		for _, v := range aud {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+	for _, v := range values {
+		for _, want := range audience {
+			if v == want {
+				return true
+			}
+		}
+	}
+	return false
+}
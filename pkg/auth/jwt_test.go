@@ -0,0 +1,271 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// testKeys are locally generated signing keys
+type testKeys struct {
+	rsa     *rsa.PrivateKey
+	ec      *ecdsa.PrivateKey
+	ed      ed25519.PrivateKey
+	hmac    []byte
+	jwks    []byte
+	pemKeys []byte
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := &testKeys{rsa: rsaKey, ec: ecKey, ed: edKey, hmac: []byte("0123456789abcdef0123456789abcdef")}
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwks, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": b64(edPub)},
+		{"kty": "oct", "kid": "hmac", "alg": "HS256", "k": b64(k.hmac)},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.jwks = jwks
+
+	for _, pub := range []crypto.PublicKey{&rsaKey.PublicKey, &ecKey.PublicKey, edPub} {
+		der, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		k.pemKeys = append(k.pemKeys, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})...)
+	}
+	return k
+}
+
+// sign returns a JWT with claims signed by the key for alg
+func (k *testKeys) sign(t *testing.T, alg, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	header := map[string]string{"alg": alg, "typ": "JWT"}
+	if kid != "" {
+		header["kid"] = kid
+	}
+	headerJSON, _ := json.Marshal(header)
+	claimsJSON, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	var err error
+	switch alg {
+	case "RS256":
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, digest[:])
+	case "PS256":
+		signature, err = rsa.SignPSS(rand.Reader, k.rsa, crypto.SHA256, digest[:], &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	case "ES256":
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k.ec, digest[:])
+		if err == nil {
+			signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+		}
+	case "EdDSA":
+		signature = ed25519.Sign(k.ed, []byte(signed))
+	case "HS256":
+		mac := hmac.New(sha256.New, k.hmac)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case "none":
+	default:
+		t.Fatalf("unsupported alg %s", alg)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "alice",
+		"iss":   "https://auth.example.com",
+		"aud":   []string{"pets-api", "other"},
+		"exp":   testNow.Add(time.Hour).Unix(),
+		"nbf":   testNow.Add(-time.Minute).Unix(),
+		"scope": "pets.read pets.admin",
+	}
+}
+
+func TestJWTVerify(t *testing.T) {
+	keys := newTestKeys(t)
+	jwks, err := ParseJWKS(keys.jwks)
+	if err != nil {
+		t.Fatalf("ParseJWKS failed: %v", err)
+	}
+	a := &JWTAuthenticator{
+		Keys:     jwks,
+		Issuer:   "https://auth.example.com",
+		Audience: []string{"pets-api"},
+		Leeway:   30 * time.Second,
+		Now:      func() time.Time { return testNow },
+	}
+
+	with := func(key string, value interface{}) map[string]interface{} {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"RS256", keys.sign(t, "RS256", "rsa", validClaims()), true},
+		{"PS256", keys.sign(t, "PS256", "rsa", validClaims()), true},
+		{"ES256", keys.sign(t, "ES256", "ec", validClaims()), true},
+		{"EdDSA", keys.sign(t, "EdDSA", "ed", validClaims()), true},
+		{"HS256", keys.sign(t, "HS256", "hmac", validClaims()), true},
+		{"without kid", keys.sign(t, "RS256", "", validClaims()), true},
+		{"wrong kid", keys.sign(t, "RS256", "ec", validClaims()), false},
+		{"alg none", keys.sign(t, "none", "", validClaims()), false},
+		{"tampered", keys.sign(t, "RS256", "rsa", validClaims())[:40] + "x" + keys.sign(t, "RS256", "rsa", validClaims())[41:], false},
+		{"malformed", "not.a.jwt", false},
+		{"expired", keys.sign(t, "RS256", "rsa", with("exp", testNow.Add(-time.Minute).Unix())), false},
+		{"expired within leeway", keys.sign(t, "RS256", "rsa", with("exp", testNow.Add(-10*time.Second).Unix())), true},
+		{"missing exp", keys.sign(t, "RS256", "rsa", with("exp", nil)), false},
+		{"not yet valid", keys.sign(t, "RS256", "rsa", with("nbf", testNow.Add(time.Hour).Unix())), false},
+		{"wrong issuer", keys.sign(t, "RS256", "rsa", with("iss", "https://evil.example.com")), false},
+		{"audience string", keys.sign(t, "RS256", "rsa", with("aud", "pets-api")), true},
+		{"wrong audience", keys.sign(t, "RS256", "rsa", with("aud", "other")), false},
+		{"missing audience", keys.sign(t, "RS256", "rsa", with("aud", nil)), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := a.Verify(tt.token)
+			if !tt.valid {
+				if !errors.Is(err, ErrUnauthenticated) {
+					t.Fatalf("expected ErrUnauthenticated, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if claims.Subject() != "alice" {
+				t.Errorf("subject = %q", claims.Subject())
+			}
+		})
+	}
+}
+
+func TestJWTAuthenticate(t *testing.T) {
+	keys := newTestKeys(t)
+	dir := t.TempDir()
+	pemFile := filepath.Join(dir, "keys.pem")
+	if err := os.WriteFile(pemFile, keys.pemKeys, 0600); err != nil {
+		t.Fatal(err)
+	}
+	pemKeys, err := LoadPEM(pemFile)
+	if err != nil {
+		t.Fatalf("LoadPEM failed: %v", err)
+	}
+
+	a := &JWTAuthenticator{
+		Keys:   pemKeys,
+		Scopes: map[string][]string{"pets.admin": {"pets:read", "pets:write"}},
+		Now:    func() time.Time { return testNow },
+	}
+	scheme := &Scheme{Name: "oauth", Type: "oauth2"}
+
+	request := func(token string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/pets", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		next, ok := Check(a, w, r, []Requirement{{{Scheme: scheme, Scopes: []string{"pets:write"}}}})
+		if ok {
+			claims, found := ClaimsFromContext(next.Context())
+			if !found || claims.Subject() != "alice" {
+				t.Errorf("claims = %v, %v", claims, found)
+			}
+			w.Code = 0
+		}
+		return w
+	}
+
+	if w := request(keys.sign(t, "ES256", "", validClaims())); w.Code != 0 {
+		t.Errorf("mapped scope rejected with %d", w.Code)
+	}
+
+	claims := validClaims()
+	claims["scope"] = "pets.read"
+	if w := request(keys.sign(t, "EdDSA", "", claims)); w.Code != 403 {
+		t.Errorf("missing scope: status = %d, want 403", w.Code)
+	}
+
+	delete(claims, "scope")
+	claims["scp"] = []string{"pets:write"}
+	if w := request(keys.sign(t, "RS256", "", claims)); w.Code != 0 {
+		t.Errorf("scp list rejected with %d", w.Code)
+	}
+
+	// PEM keys are public keys, never HMAC secrets
+	if w := request(keys.sign(t, "HS256", "", validClaims())); w.Code != 401 {
+		t.Errorf("HS256 with PEM keys: status = %d, want 401", w.Code)
+	}
+
+	basic := &Scheme{Name: "basic", Type: "http", HTTPScheme: "basic"}
+	if _, err := a.Authenticate(httptest.NewRequest("GET", "/", nil), basic, nil); !errors.Is(err, ErrUnauthenticated) {
+		t.Errorf("basic scheme: expected ErrUnauthenticated, got %v", err)
+	}
+}
+
+func TestParseKeysErrors(t *testing.T) {
+	for name, data := range map[string]string{
+		"malformed":   "{",
+		"empty":       `{"keys": []}`,
+		"unsupported": `{"keys": [{"kty": "XYZ"}]}`,
+		"bad curve":   `{"keys": [{"kty": "EC", "crv": "P-256", "x": "AQ", "y": "AQ"}]}`,
+	} {
+		if _, err := ParseJWKS([]byte(data)); err == nil {
+			t.Errorf("ParseJWKS(%s): expected an error", name)
+		}
+	}
+	if _, err := ParsePEM([]byte("no keys here")); err == nil {
+		t.Error("ParsePEM: expected an error")
+	}
+}
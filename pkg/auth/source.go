@@ -0,0 +1,9 @@
+package auth
+
+import "embed"
+
+// Files holds the source of the package. The generator copies it into
+// generated projects so that they only depend on the standard library.
+//
+//go:embed auth.go jwt.go
+var Files embed.FS
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EscapeComment makes spec text safe to place on a single // comment line
+func EscapeComment(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+	// Collapse runs of whitespace left by removed line breaks
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// EscapeBlockComment makes spec text safe to place on a single line of a
+// /* */ comment, which it must not close
+func EscapeBlockComment(s string) string {
+	return strings.ReplaceAll(EscapeComment(s), "*/", "* /")
+}
+
+// QuoteGoString returns s as a double-quoted Go string literal
+func QuoteGoString(s string) string {
+	return strconv.Quote(s)
+}
+
+// SanitizeIdentifier turns s into a valid Go identifier, keeping valid
+// identifiers unchanged
+func SanitizeIdentifier(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+			b.WriteRune(r)
+		case unicode.IsDigit(r):
+			if i == 0 {
+				b.WriteRune('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+
+	ident := b.String()
+	if ident == "" {
+		return "_"
+	}
+	if token.IsKeyword(ident) {
+		return ident + "_"
+	}
+	return ident
+}
+
+// ToGoIdentifier converts an operation or schema name to an exported Go
+// identifier
+func ToGoIdentifier(s string) string {
+	// Treat any character that can't appear in an identifier as a word separator
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, s)
+	ident := ToCamelCase(s)
+	if ident == "" {
+		return "X"
+	}
+	if !unicode.IsLetter([]rune(ident)[0]) {
+		ident = "X" + ident
+	}
+	return ident
+}
+
+// markdownEscaper escapes characters that Markdown would otherwise interpret
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`#`, `\#`,
+	`|`, `\|`,
+	`!`, `\!`,
+	`<`, `&lt;`,
+	`>`, `&gt;`,
+	`&`, `&amp;`,
+)
+
+// EscapeMarkdown makes spec text safe to place inline in Markdown
+func EscapeMarkdown(s string) string {
+	return markdownEscaper.Replace(EscapeComment(s))
+}
@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestEscapeComment(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Plain text",
+			input:    "List all users",
+			expected: "List all users",
+		},
+		{
+			name:     "Newlines collapsed",
+			input:    "first line\nfunc init() {}\r\nlast",
+			expected: "first line func init() {} last",
+		},
+		{
+			name:     "Block comment terminator",
+			input:    "ends here */ code",
+			expected: "ends here */ code",
+		},
+		{
+			name:     "Control characters",
+			input:    "tab\there\x00nul",
+			expected: "tab here nul",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := EscapeComment(test.input)
+			if result != test.expected {
+				t.Errorf("EscapeComment(%q) = %q, expected %q", test.input, result, test.expected)
+			}
+		})
+	}
+}
+
+func TestEscapeBlockComment(t *testing.T) {
+	result := EscapeBlockComment("ends here */ code\nnext")
+	expected := "ends here * / code next"
+	if result != expected {
+		t.Errorf("EscapeBlockComment() = %q, expected %q", result, expected)
+	}
+}
+
+func TestQuoteGoString(t *testing.T) {
+	result := QuoteGoString("a \"quoted\"\nvalue\\")
+	expected := `"a \"quoted\"\nvalue\\"`
+	if result != expected {
+		t.Errorf("QuoteGoString() = %s, expected %s", result, expected)
+	}
+}
+
+func TestSanitizeIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Valid identifier", input: "user_id", expected: "user_id"},
+		{name: "Keeps case", input: "APIKey", expected: "APIKey"},
+		{name: "Hyphen", input: "user-id", expected: "user_id"},
+		{name: "Leading digit", input: "2fa", expected: "_2fa"},
+		{name: "Keyword", input: "type", expected: "type_"},
+		{name: "Injection", input: "id) {}", expected: "id____"},
+		{name: "Empty", input: "", expected: "_"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := SanitizeIdentifier(test.input)
+			if result != test.expected {
+				t.Errorf("SanitizeIdentifier(%q) = %q, expected %q", test.input, result, test.expected)
+			}
+		})
+	}
+}
+
+func TestToGoIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Snake case", input: "list_users", expected: "ListUsers"},
+		{name: "Spaces and punctuation", input: "get user */ ; os.Exit(1)", expected: "GetUserOsExit1"},
+		{name: "Leading digit", input: "2fa_verify", expected: "X2FaVerify"},
+		{name: "Only symbols", input: "!!!", expected: "X"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := ToGoIdentifier(test.input)
+			if result != test.expected {
+				t.Errorf("ToGoIdentifier(%q) = %q, expected %q", test.input, result, test.expected)
+			}
+		})
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "Plain text", input: "Get user", expected: "Get user"},
+		{name: "Emphasis", input: "*bold* _it_", expected: `\*bold\* \_it\_`},
+		{name: "HTML", input: "<script>", expected: "&lt;script&gt;"},
+		{name: "Heading injection", input: "x\n# Heading", expected: `x \# Heading`},
+		{name: "Link", input: "[a](b)", expected: `\[a\](b)`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := EscapeMarkdown(test.input)
+			if result != test.expected {
+				t.Errorf("EscapeMarkdown(%q) = %q, expected %q", test.input, result, test.expected)
+			}
+		})
+	}
+}
@@ -68,6 +68,32 @@ func ConvertPathToGin(path string) string {
 	return strings.ReplaceAll(strings.ReplaceAll(path, "{", ":"), "}", "")
 }
 
+// ConvertPathToEcho converts an OpenAPI path to an Echo path
+func ConvertPathToEcho(path string) string {
+	// Echo uses the same :param format as Gin
+	return ConvertPathToGin(path)
+}
+
+// ConvertPathToChi converts an OpenAPI path to a chi path
+func ConvertPathToChi(path string) string {
+	// chi shares the {param} format but reads {param:regexp} as a pattern,
+	// so colons in parameter names are replaced
+	var b strings.Builder
+	inParam := false
+	for _, r := range path {
+		switch {
+		case r == '{':
+			inParam = true
+		case r == '}':
+			inParam = false
+		case r == ':' && inParam:
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 // ToCamelCase converts a string to CamelCase
 func ToCamelCase(s string) string {
 	// Convert snake_case or kebab-case to CamelCase
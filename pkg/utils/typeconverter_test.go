@@ -324,3 +324,40 @@ func TestConvertPathToGin(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertPathToEcho(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/users/{id}", "/users/:id"},
+		{"/users/{userId}/posts/{postId}", "/users/:userId/posts/:postId"},
+		{"/users", "/users"},
+	}
+
+	for _, test := range tests {
+		if result := ConvertPathToEcho(test.path); result != test.expected {
+			t.Errorf("ConvertPathToEcho(%q) = %q, expected %q", test.path, result, test.expected)
+		}
+	}
+}
+
+func TestConvertPathToChi(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/users/{id}", "/users/{id}"},
+		{"/users/{userId}/posts/{postId}", "/users/{userId}/posts/{postId}"},
+		{"/files/{name}.json", "/files/{name}.json"},
+		{"/items/{ns:id}", "/items/{ns_id}"},
+		{"/time/12:00", "/time/12:00"},
+		{"/users", "/users"},
+	}
+
+	for _, test := range tests {
+		if result := ConvertPathToChi(test.path); result != test.expected {
+			t.Errorf("ConvertPathToChi(%q) = %q, expected %q", test.path, result, test.expected)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package validate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Response is a response an operation declares, by status code
+type Response struct {
+	ContentTypes []string // media types of the body, none if it has no body
+	Schema       *Schema  // schema of the JSON media type, if any
+}
+
+// ResponseMode sets what ResponseMiddleware does with responses that don't
+// match the spec
+type ResponseMode int
+
+const (
+	// LogViolations logs responses that don't match the spec and sends them unchanged
+	LogViolations ResponseMode = iota
+	// RejectViolations replaces responses that don't match the spec with a
+	// 500 Internal Server Error listing the violations, for tests
+	RejectViolations
+)
+
+// ResponseError lists the ways a response doesn't match the spec
+type ResponseError struct {
+	Operation string
+	Status    int
+	Errors    []Error
+}
+
+// Error implements the error interface
+func (e *ResponseError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%s response %d does not match the spec: %s", e.Operation, e.Status, strings.Join(messages, "; "))
+}
+
+// ValidateResponse validates a response to r against the operation serving
+// r, returning a *ResponseError if it doesn't match the spec. Responses to
+// requests no operation serves, and to operations declaring no responses,
+// aren't validated.
+func (v *Validator) ValidateResponse(r *http.Request, status int, header http.Header, body []byte) error {
+	op, _ := v.Match(r)
+	if op == nil || len(op.Responses) == 0 {
+		return nil
+	}
+
+	fail := func(in, field, format string, args ...interface{}) error {
+		return &ResponseError{
+			Operation: op.Name,
+			Status:    status,
+			Errors:    []Error{{In: in, Field: field, Message: fmt.Sprintf(format, args...)}},
+		}
+	}
+
+	resp, ok := matchResponse(op.Responses, status)
+	if !ok {
+		return fail("status", "", "%d is not declared, want %s", status, declaredStatuses(op.Responses))
+	}
+
+	// Responses to HEAD requests and 204/304 responses carry no body
+	if r.Method == http.MethodHead || status == http.StatusNoContent || status == http.StatusNotModified {
+		return nil
+	}
+	if len(resp.ContentTypes) == 0 {
+		if len(body) > 0 {
+			return fail("body", "", "must be empty, the spec declares no content")
+		}
+		return nil
+	}
+	if len(body) == 0 {
+		return fail("body", "", "is empty, want %s", strings.Join(resp.ContentTypes, " or "))
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil || !acceptsMediaType(resp.ContentTypes, mediaType) {
+		return fail("header", "Content-Type", "is %q, want %s", header.Get("Content-Type"), strings.Join(resp.ContentTypes, " or "))
+	}
+	if resp.Schema == nil || !isJSON(mediaType) {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return fail("body", "", "malformed JSON: %v", err)
+	}
+
+	var errs []Error
+	v.validateValue(resp.Schema, value, "body", "", &errs, 0)
+	if len(errs) > 0 {
+		return &ResponseError{Operation: op.Name, Status: status, Errors: errs}
+	}
+	return nil
+}
+
+// ResponseMiddleware buffers each response and validates it against the spec
+// before sending it, handling violations according to mode. Buffering makes
+// it unsuitable for streaming responses, so it's meant for development and
+// tests.
+func (v *Validator) ResponseMiddleware(mode ResponseMode, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := &responseBuffer{header: http.Header{}, status: http.StatusOK}
+		next.ServeHTTP(buf, r)
+
+		err := v.ValidateResponse(r, buf.status, buf.header, buf.body.Bytes())
+		if err != nil {
+			log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+			if respErr, ok := err.(*ResponseError); ok && mode == RejectViolations {
+				writeErrors(w, http.StatusInternalServerError, "response does not match the spec", respErr.Errors)
+				return
+			}
+		}
+
+		for key, values := range buf.header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(buf.status)
+		_, _ = w.Write(buf.body.Bytes())
+	})
+}
+
+// responseBuffer is an http.ResponseWriter holding the response in memory
+type responseBuffer struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+// Header implements http.ResponseWriter
+func (b *responseBuffer) Header() http.Header {
+	return b.header
+}
+
+// WriteHeader implements http.ResponseWriter
+func (b *responseBuffer) WriteHeader(status int) {
+	if !b.wroteHeader {
+		b.status, b.wroteHeader = status, true
+	}
+}
+
+// Write implements http.ResponseWriter
+func (b *responseBuffer) Write(p []byte) (int, error) {
+	if !b.wroteHeader {
+		b.WriteHeader(http.StatusOK)
+	}
+	if b.header.Get("Content-Type") == "" {
+		b.header.Set("Content-Type", http.DetectContentType(p))
+	}
+	return b.body.Write(p)
+}
+
+// matchResponse returns the response declared for status: by its exact
+// code, by a range like 2XX, or the default response
+func matchResponse(responses map[string]*Response, status int) (*Response, bool) {
+	code := strconv.Itoa(status)
+	for _, key := range []string{code, code[:1] + "XX", "default"} {
+		for declared, resp := range responses {
+			if strings.EqualFold(declared, key) {
+				if resp == nil {
+					resp = &Response{}
+				}
+				return resp, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// declaredStatuses lists the status codes of responses
+func declaredStatuses(responses map[string]*Response) string {
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return strings.Join(codes, ", ")
+}
@@ -0,0 +1,116 @@
+package validate
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func responseValidator() *Validator {
+	pet := &Schema{
+		Type:       "object",
+		Required:   []string{"name"},
+		Properties: map[string]*Schema{"name": {Type: "string"}},
+	}
+	return New(nil, []Operation{
+		{
+			Method: "GET",
+			Path:   "/pets",
+			Name:   "ListPets",
+			Responses: map[string]*Response{
+				"200":     {ContentTypes: []string{"application/json"}, Schema: &Schema{Type: "array", Items: pet}},
+				"4XX":     {ContentTypes: []string{"application/problem+json"}},
+				"default": {},
+			},
+		},
+		{
+			Method:    "POST",
+			Path:      "/pets",
+			Name:      "CreatePet",
+			Responses: map[string]*Response{"201": {ContentTypes: []string{"application/json"}, Schema: pet}},
+		},
+		{Method: "DELETE", Path: "/pets", Name: "DeletePets"},
+	})
+}
+
+func TestValidateResponse(t *testing.T) {
+	v := responseValidator()
+
+	tests := []struct {
+		name        string
+		method      string
+		status      int
+		contentType string
+		body        string
+		fields      []string // nil if the response is valid
+	}{
+		{name: "valid", method: "GET", status: 200, contentType: "application/json", body: `[{"name":"Rex"}]`},
+		{name: "missing field", method: "GET", status: 200, contentType: "application/json", body: `[{"name":"Rex"},{}]`, fields: []string{"[1].name"}},
+		{name: "wrong content type", method: "GET", status: 200, contentType: "text/plain", body: "Rex", fields: []string{"Content-Type"}},
+		{name: "empty body", method: "GET", status: 200, fields: []string{""}},
+		{name: "status range", method: "GET", status: 404, contentType: "application/problem+json", body: `{"title":"not found"}`},
+		{name: "default", method: "GET", status: 503},
+		{name: "default without content", method: "GET", status: 500, contentType: "text/plain", body: "oops", fields: []string{""}},
+		{name: "undeclared status", method: "POST", status: 200, contentType: "application/json", body: `{"name":"Rex"}`, fields: []string{""}},
+		{name: "created", method: "POST", status: 201, contentType: "application/json", body: `{"name":"Rex"}`},
+		{name: "no declared responses", method: "DELETE", status: 418},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.contentType != "" {
+				header.Set("Content-Type", tt.contentType)
+			}
+			err := v.ValidateResponse(httptest.NewRequest(tt.method, "/pets", nil), tt.status, header, []byte(tt.body))
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var respErr *ResponseError
+			if !errors.As(err, &respErr) {
+				t.Fatalf("expected a *ResponseError, got %v", err)
+			}
+			var fields []string
+			for _, e := range respErr.Errors {
+				fields = append(fields, e.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("fields = %q, want %q (%v)", fields, tt.fields, err)
+			}
+		})
+	}
+}
+
+func TestResponseMiddleware(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Pet", "rex")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"name":"Rex"}`))
+	})
+
+	// The spec says POST /pets responds with 201
+	w := httptest.NewRecorder()
+	responseValidator().ResponseMiddleware(LogViolations, handler).ServeHTTP(w, httptest.NewRequest("POST", "/pets", nil))
+	if w.Code != http.StatusOK || w.Body.String() != `{"name":"Rex"}` || w.Header().Get("X-Pet") != "rex" {
+		t.Errorf("LogViolations changed the response: %d %v %q", w.Code, w.Header(), w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	responseValidator().ResponseMiddleware(RejectViolations, handler).ServeHTTP(w, httptest.NewRequest("POST", "/pets", nil))
+	if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), "200 is not declared, want 201") {
+		t.Errorf("RejectViolations response = %d %q", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	responseValidator().ResponseMiddleware(RejectViolations, handler).ServeHTTP(w, httptest.NewRequest("GET", "/pets", nil))
+	if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), "must be an array") {
+		t.Errorf("RejectViolations response = %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,743 @@
+// Package validate checks HTTP requests against the operations of an OpenAPI
+// spec before they reach the handlers. The generator copies it into generated
+// projects as generated/validate.
+package validate
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxDepth bounds schema recursion, e.g. through self-referencing allOf
+const maxDepth = 64
+
+// Schema is a JSON schema values are validated against
+type Schema struct {
+	Ref                  string // name of the component schema this refers to
+	Type                 string
+	Format               string
+	Nullable             bool
+	Enum                 []interface{}
+	Properties           map[string]*Schema
+	Required             []string
+	AdditionalProperties *bool
+	Items                *Schema
+	AllOf                []*Schema
+	OneOf                []*Schema
+	AnyOf                []*Schema
+	Not                  *Schema
+	Minimum              *float64
+	Maximum              *float64
+	MultipleOf           *float64
+	MinLength            *int
+	MaxLength            *int
+	Pattern              string
+	MinItems             *int
+	MaxItems             *int
+	UniqueItems          bool
+}
+
+// Float returns a pointer to v, for the numeric bounds of a Schema
+func Float(v float64) *float64 { return &v }
+
+// Int returns a pointer to v, for the length bounds of a Schema
+func Int(v int) *int { return &v }
+
+// Bool returns a pointer to v, for Schema.AdditionalProperties
+func Bool(v bool) *bool { return &v }
+
+// Param is a path, query, header or cookie parameter of an operation
+type Param struct {
+	Name     string
+	In       string
+	Required bool
+	Explode  bool // whether array values are repeated instead of comma-separated
+	Schema   *Schema
+}
+
+// Body is the request body of an operation
+type Body struct {
+	Required     bool
+	ContentTypes []string // media types the operation accepts
+	Schema       *Schema  // schema of the JSON media type, if any
+}
+
+// Operation is an operation requests are validated against
+type Operation struct {
+	Method string
+	Path   string // OpenAPI path template, e.g. /users/{id}
+	Name   string
+	Params []Param
+	Body   *Body
+
+	// Responses are the declared responses by status code, such as 200,
+	// 2XX or default
+	Responses map[string]*Response
+}
+
+// Error is a single validation failure
+type Error struct {
+	In      string // path, query, header, cookie or body
+	Field   string // parameter name or location in the body, e.g. items[0].id
+	Message string
+}
+
+// Error implements the error interface
+func (e Error) Error() string {
+	if e.Field == "" {
+		return e.In + ": " + e.Message
+	}
+	return e.In + " " + e.Field + ": " + e.Message
+}
+
+// RequestError lists every validation failure of a request
+type RequestError struct {
+	// Status is 400 Bad Request for invalid parameters or malformed bodies,
+	// and 422 Unprocessable Entity for bodies violating their schema
+	Status int
+	Errors []Error
+}
+
+// Error implements the error interface
+func (e *RequestError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "invalid request: " + strings.Join(messages, "; ")
+}
+
+// Validator matches requests to operations and validates them
+type Validator struct {
+	schemas map[string]*Schema
+	routes  []route
+
+	mu       sync.Mutex
+	patterns map[string]*regexp.Regexp
+}
+
+// route is an operation with its compiled path template
+type route struct {
+	op      *Operation
+	pattern *regexp.Regexp
+	names   []string // path parameter names, in the order they appear
+	literal int      // literal characters in the path, to prefer concrete paths
+}
+
+var templateParam = regexp.MustCompile("{[^{}/]+}")
+
+// New returns a validator for operations, resolving schema references by
+// name against schemas
+func New(schemas map[string]*Schema, operations []Operation) *Validator {
+	v := &Validator{schemas: schemas, patterns: map[string]*regexp.Regexp{}}
+	for i := range operations {
+		op := &operations[i]
+		r := route{op: op}
+
+		var pattern strings.Builder
+		pattern.WriteString("^")
+		last := 0
+		for _, loc := range templateParam.FindAllStringIndex(op.Path, -1) {
+			pattern.WriteString(regexp.QuoteMeta(op.Path[last:loc[0]]))
+			pattern.WriteString("([^/]+)")
+			r.names = append(r.names, op.Path[loc[0]+1:loc[1]-1])
+			r.literal += loc[0] - last
+			last = loc[1]
+		}
+		pattern.WriteString(regexp.QuoteMeta(op.Path[last:]))
+		pattern.WriteString("$")
+		r.literal += len(op.Path) - last
+
+		r.pattern = regexp.MustCompile(pattern.String())
+		v.routes = append(v.routes, r)
+	}
+	return v
+}
+
+// Match returns the operation serving r and the values of its path
+// parameters, or nil if no operation serves r
+func (v *Validator) Match(r *http.Request) (*Operation, map[string]string) {
+	var best *route
+	var values []string
+	for i := range v.routes {
+		rt := &v.routes[i]
+		if !strings.EqualFold(rt.op.Method, r.Method) {
+			continue
+		}
+		match := rt.pattern.FindStringSubmatch(r.URL.EscapedPath())
+		if match == nil || (best != nil && rt.literal <= best.literal) {
+			continue
+		}
+		best, values = rt, match[1:]
+	}
+	if best == nil {
+		return nil, nil
+	}
+
+	params := make(map[string]string, len(best.names))
+	for i, name := range best.names {
+		value, err := url.PathUnescape(values[i])
+		if err != nil {
+			value = values[i]
+		}
+		params[name] = value
+	}
+	return best.op, params
+}
+
+// ValidateRequest validates r against the operation serving it, returning a
+// *RequestError if it is invalid. Requests no operation serves aren't
+// validated. The body is read and replaced, so handlers can still read it.
+func (v *Validator) ValidateRequest(r *http.Request) error {
+	op, pathParams := v.Match(r)
+	if op == nil {
+		return nil
+	}
+
+	var errs []Error
+	for _, param := range op.Params {
+		errs = append(errs, v.validateParam(r, param, pathParams)...)
+	}
+
+	var bodyErrs []Error
+	if op.Body != nil {
+		var malformed bool
+		var err error
+		bodyErrs, malformed, err = v.validateBody(r, op.Body)
+		if err != nil {
+			return err
+		}
+		if malformed {
+			errs = append(errs, bodyErrs...)
+			bodyErrs = nil
+		}
+	}
+
+	switch {
+	case len(errs) > 0:
+		return &RequestError{Status: http.StatusBadRequest, Errors: append(errs, bodyErrs...)}
+	case len(bodyErrs) > 0:
+		return &RequestError{Status: http.StatusUnprocessableEntity, Errors: bodyErrs}
+	}
+	return nil
+}
+
+// Middleware rejects requests failing validation with their status and a
+// JSON list of the errors
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := v.ValidateRequest(r)
+		var reqErr *RequestError
+		if errors.As(err, &reqErr) {
+			WriteError(w, reqErr)
+			return
+		}
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// WriteError writes err as a JSON response:
+//
+//	{"error": "invalid request", "errors": [{"in": "query", "field": "limit", "message": "must be at most 100"}]}
+func WriteError(w http.ResponseWriter, err *RequestError) {
+	writeErrors(w, err.Status, "invalid request", err.Errors)
+}
+
+// writeErrors writes a JSON response listing errs
+func writeErrors(w http.ResponseWriter, status int, message string, errs []Error) {
+	list := make([]map[string]string, len(errs))
+	for i, e := range errs {
+		list[i] = map[string]string{"in": e.In, "field": e.Field, "message": e.Message}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":  message,
+		"errors": list,
+	})
+}
+
+// validateParam checks the value of param in r
+func (v *Validator) validateParam(r *http.Request, param Param, pathParams map[string]string) []Error {
+	var raw []string
+	switch param.In {
+	case "path":
+		if value, ok := pathParams[param.Name]; ok {
+			raw = []string{value}
+		}
+	case "query":
+		raw = r.URL.Query()[param.Name]
+	case "header":
+		raw = r.Header.Values(param.Name)
+	case "cookie":
+		if cookie, err := r.Cookie(param.Name); err == nil {
+			raw = []string{cookie.Value}
+		}
+	}
+
+	if len(raw) == 0 {
+		if param.Required {
+			return []Error{{In: param.In, Field: param.Name, Message: "is required"}}
+		}
+		return nil
+	}
+
+	value, err := v.parseParam(param, raw)
+	if err != nil {
+		return []Error{{In: param.In, Field: param.Name, Message: err.Error()}}
+	}
+
+	var errs []Error
+	v.validateValue(param.Schema, value, param.In, param.Name, &errs, 0)
+	return errs
+}
+
+// parseParam converts the raw values of param to the type of its schema
+func (v *Validator) parseParam(param Param, raw []string) (interface{}, error) {
+	schema := v.resolve(param.Schema)
+	if schema == nil || schema.Type != "array" {
+		return v.parseScalar(schema, raw[0])
+	}
+
+	if !param.Explode {
+		raw = strings.Split(strings.Join(raw, ","), ",")
+	}
+	items := v.resolve(schema.Items)
+	values := make([]interface{}, len(raw))
+	for i, s := range raw {
+		value, err := v.parseScalar(items, s)
+		if err != nil {
+			return nil, fmt.Errorf("item %d %s", i, err)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// parseScalar converts s to the type of schema
+func (v *Validator) parseScalar(schema *Schema, s string) (interface{}, error) {
+	if schema == nil {
+		return s, nil
+	}
+	switch schema.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return nil, errors.New("must be " + schemaTypeName(schema.Type))
+		}
+		return json.Number(s), nil
+	case "boolean":
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, errors.New("must be a boolean")
+		}
+		return b, nil
+	}
+	return s, nil
+}
+
+// validateBody checks the request body and restores it for the handler. It
+// reports whether the errors are about a missing or malformed body rather
+// than its content.
+func (v *Validator) validateBody(r *http.Request, body *Body) ([]Error, bool, error) {
+	var data []byte
+	if r.Body != nil {
+		var err error
+		data, err = io.ReadAll(r.Body)
+		if err != nil {
+			return nil, false, err
+		}
+		_ = r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	if len(data) == 0 {
+		if body.Required {
+			return []Error{{In: "body", Message: "is required"}}, true, nil
+		}
+		return nil, false, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || !acceptsMediaType(body.ContentTypes, mediaType) {
+		message := fmt.Sprintf("unsupported media type %q, want %s", mediaType, strings.Join(body.ContentTypes, " or "))
+		return []Error{{In: "header", Field: "Content-Type", Message: message}}, true, nil
+	}
+	if body.Schema == nil || !isJSON(mediaType) {
+		return nil, false, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return []Error{{In: "body", Message: "malformed JSON: " + err.Error()}}, true, nil
+	}
+	if dec.More() {
+		return []Error{{In: "body", Message: "malformed JSON: unexpected data after the top-level value"}}, true, nil
+	}
+
+	var errs []Error
+	v.validateValue(body.Schema, value, "body", "", &errs, 0)
+	return errs, false, nil
+}
+
+// acceptsMediaType reports whether mediaType matches one of accepted, which
+// may contain wildcards like application/*
+func acceptsMediaType(accepted []string, mediaType string) bool {
+	for _, a := range accepted {
+		a = strings.ToLower(a)
+		switch {
+		case a == mediaType, a == "*/*":
+			return true
+		case strings.HasSuffix(a, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(a, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// isJSON reports whether mediaType is JSON, e.g. application/json or application/problem+json
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// resolve follows schema references
+func (v *Validator) resolve(schema *Schema) *Schema {
+	for i := 0; schema != nil && schema.Ref != "" && i < maxDepth; i++ {
+		schema = v.schemas[schema.Ref]
+	}
+	return schema
+}
+
+// validateValue appends the ways value violates schema to errs
+func (v *Validator) validateValue(schema *Schema, value interface{}, in, field string, errs *[]Error, depth int) {
+	schema = v.resolve(schema)
+	if schema == nil || depth > maxDepth {
+		return
+	}
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, Error{In: in, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if value == nil {
+		if !schema.Nullable && schema.Type != "" {
+			fail("must not be null")
+		}
+		return
+	}
+
+	for _, sub := range schema.AllOf {
+		v.validateValue(sub, value, in, field, errs, depth+1)
+	}
+	if len(schema.AnyOf) > 0 && v.countMatches(schema.AnyOf, value, depth) == 0 {
+		fail("must match at least one of the allowed schemas")
+	}
+	if len(schema.OneOf) > 0 {
+		if n := v.countMatches(schema.OneOf, value, depth); n != 1 {
+			fail("must match exactly one of the allowed schemas, matched %d", n)
+		}
+	}
+	if schema.Not != nil && v.countMatches([]*Schema{schema.Not}, value, depth) == 1 {
+		fail("must not match the disallowed schema")
+	}
+
+	if len(schema.Enum) > 0 {
+		found := false
+		for _, allowed := range schema.Enum {
+			if equalValues(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("must be one of %s", formatEnum(schema.Enum))
+			return
+		}
+	}
+
+	switch schema.Type {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			fail("must be a string")
+			return
+		}
+		v.validateString(schema, s, fail)
+	case "integer", "number":
+		n, ok := toFloat(value)
+		if !ok {
+			fail("must be %s", schemaTypeName(schema.Type))
+			return
+		}
+		if schema.Type == "integer" && n != math.Trunc(n) {
+			fail("must be an integer")
+			return
+		}
+		validateNumber(schema, n, fail)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("must be a boolean")
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			fail("must be an array")
+			return
+		}
+		v.validateArray(schema, items, in, field, errs, depth, fail)
+	case "object":
+		if _, ok := value.(map[string]interface{}); !ok {
+			fail("must be an object")
+			return
+		}
+	}
+
+	if obj, ok := value.(map[string]interface{}); ok {
+		v.validateObject(schema, obj, in, field, errs, depth)
+	}
+}
+
+// countMatches returns how many of schemas value satisfies
+func (v *Validator) countMatches(schemas []*Schema, value interface{}, depth int) int {
+	n := 0
+	for _, sub := range schemas {
+		var subErrs []Error
+		v.validateValue(sub, value, "", "", &subErrs, depth+1)
+		if len(subErrs) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// validateString checks the length, pattern and format of s
+func (v *Validator) validateString(schema *Schema, s string, fail func(string, ...interface{})) {
+	length := len([]rune(s))
+	if schema.MinLength != nil && length < *schema.MinLength {
+		fail("must be at least %d characters long", *schema.MinLength)
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		fail("must be at most %d characters long", *schema.MaxLength)
+	}
+	if schema.Pattern != "" {
+		if re := v.pattern(schema.Pattern); re != nil && !re.MatchString(s) {
+			fail("must match the pattern %s", schema.Pattern)
+		}
+	}
+	if !validFormat(schema.Format, s) {
+		fail("must be a valid %s", schema.Format)
+	}
+}
+
+// validateNumber checks the bounds of n
+func validateNumber(schema *Schema, n float64, fail func(string, ...interface{})) {
+	if schema.Minimum != nil && n < *schema.Minimum {
+		fail("must be at least %v", *schema.Minimum)
+	}
+	if schema.Maximum != nil && n > *schema.Maximum {
+		fail("must be at most %v", *schema.Maximum)
+	}
+	if schema.MultipleOf != nil && *schema.MultipleOf > 0 {
+		q := n / *schema.MultipleOf
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			fail("must be a multiple of %v", *schema.MultipleOf)
+		}
+	}
+}
+
+// validateArray checks the length and items of items
+func (v *Validator) validateArray(schema *Schema, items []interface{}, in, field string, errs *[]Error, depth int, fail func(string, ...interface{})) {
+	if schema.MinItems != nil && len(items) < *schema.MinItems {
+		fail("must have at least %d items", *schema.MinItems)
+	}
+	if schema.MaxItems != nil && len(items) > *schema.MaxItems {
+		fail("must have at most %d items", *schema.MaxItems)
+	}
+	if schema.UniqueItems {
+	unique:
+		for i := range items {
+			for j := 0; j < i; j++ {
+				if equalValues(items[i], items[j]) {
+					fail("must not contain duplicate items")
+					break unique
+				}
+			}
+		}
+	}
+	if schema.Items != nil {
+		for i, item := range items {
+			v.validateValue(schema.Items, item, in, fmt.Sprintf("%s[%d]", field, i), errs, depth+1)
+		}
+	}
+}
+
+// validateObject checks the required and declared properties of obj
+func (v *Validator) validateObject(schema *Schema, obj map[string]interface{}, in, field string, errs *[]Error, depth int) {
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, Error{In: in, Field: joinField(field, name), Message: "is required"})
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, ok := schema.Properties[name]
+		if !ok {
+			if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+				*errs = append(*errs, Error{In: in, Field: joinField(field, name), Message: "is not an allowed property"})
+			}
+			continue
+		}
+		v.validateValue(prop, obj[name], in, joinField(field, name), errs, depth+1)
+	}
+}
+
+// pattern compiles and caches a schema pattern. Invalid patterns match anything.
+func (v *Validator) pattern(expr string) *regexp.Regexp {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	re, ok := v.patterns[expr]
+	if !ok {
+		re, _ = regexp.Compile(expr)
+		v.patterns[expr] = re
+	}
+	return re
+}
+
+var uuidPattern = regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+
+// validFormat reports whether s is valid for format. Unknown formats accept anything.
+func validFormat(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.IsAbs()
+	case "ipv4":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	case "ipv6":
+		return net.ParseIP(s) != nil && strings.Contains(s, ":")
+	case "byte":
+		_, err := base64.StdEncoding.DecodeString(s)
+		return err == nil
+	}
+	return true
+}
+
+// toFloat returns the numeric value of a decoded JSON number or Go number
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// equalValues compares decoded JSON values, treating numbers by value
+func equalValues(a, b interface{}) bool {
+	if x, ok := toFloat(a); ok {
+		y, ok := toFloat(b)
+		return ok && x == y
+	}
+	if x, ok := a.([]interface{}); ok {
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equalValues(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	if x, ok := a.(map[string]interface{}); ok {
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k := range x {
+			if !equalValues(x[k], y[k]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// formatEnum renders the allowed values of an enum
+func formatEnum(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			parts[i] = fmt.Sprint(value)
+			continue
+		}
+		parts[i] = string(data)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// schemaTypeName names a numeric schema type in messages
+func schemaTypeName(schemaType string) string {
+	if schemaType == "integer" {
+		return "an integer"
+	}
+	return "a number"
+}
+
+// joinField appends the property name to the location field
+func joinField(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
@@ -0,0 +1,248 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func testValidator() *Validator {
+	schemas := map[string]*Schema{
+		"Pet": {
+			Type:     "object",
+			Required: []string{"name", "kind"},
+			Properties: map[string]*Schema{
+				"name":  {Type: "string", MinLength: Int(1), MaxLength: Int(20)},
+				"kind":  {Type: "string", Enum: []interface{}{"cat", "dog"}},
+				"age":   {Type: "integer", Minimum: Float(0)},
+				"tags":  {Type: "array", Items: &Schema{Type: "string"}, UniqueItems: true, MaxItems: Int(3)},
+				"email": {Type: "string", Format: "email", Nullable: true},
+			},
+			AdditionalProperties: Bool(false),
+		},
+	}
+	operations := []Operation{
+		{
+			Method: "GET",
+			Path:   "/pets",
+			Name:   "ListPets",
+			Params: []Param{
+				{Name: "limit", In: "query", Explode: true, Schema: &Schema{Type: "integer", Minimum: Float(1), Maximum: Float(100)}},
+				{Name: "kind", In: "query", Schema: &Schema{Type: "array", Items: &Schema{Type: "string", Enum: []interface{}{"cat", "dog"}}}},
+				{Name: "X-Request-ID", In: "header", Required: true, Schema: &Schema{Type: "string", Format: "uuid"}},
+			},
+		},
+		{
+			Method: "POST",
+			Path:   "/pets",
+			Name:   "CreatePet",
+			Body:   &Body{Required: true, ContentTypes: []string{"application/json"}, Schema: &Schema{Ref: "Pet"}},
+		},
+		{
+			Method: "GET",
+			Path:   "/pets/{petId}",
+			Name:   "GetPet",
+			Params: []Param{{Name: "petId", In: "path", Required: true, Schema: &Schema{Type: "integer"}}},
+		},
+		{
+			Method: "GET",
+			Path:   "/pets/mine",
+			Name:   "ListMyPets",
+		},
+	}
+	return New(schemas, operations)
+}
+
+func TestMatch(t *testing.T) {
+	v := testValidator()
+
+	tests := []struct {
+		method, target string
+		name           string
+		params         map[string]string
+	}{
+		{"GET", "/pets", "ListPets", map[string]string{}},
+		{"POST", "/pets", "CreatePet", map[string]string{}},
+		{"GET", "/pets/42", "GetPet", map[string]string{"petId": "42"}},
+		{"GET", "/pets/a%20b", "GetPet", map[string]string{"petId": "a b"}},
+		{"GET", "/pets/mine", "ListMyPets", map[string]string{}},
+		{"DELETE", "/pets", "", nil},
+		{"GET", "/owners", "", nil},
+	}
+
+	for _, tt := range tests {
+		op, params := v.Match(httptest.NewRequest(tt.method, tt.target, nil))
+		name := ""
+		if op != nil {
+			name = op.Name
+		}
+		if name != tt.name {
+			t.Errorf("%s %s matched %q, want %q", tt.method, tt.target, name, tt.name)
+		}
+		for key, want := range tt.params {
+			if params[key] != want {
+				t.Errorf("%s %s: param %s = %q, want %q", tt.method, tt.target, key, params[key], want)
+			}
+		}
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	v := testValidator()
+	const requestID = "0b1e3c1a-6f0e-4c5e-9a35-1d2f7c0e8a11"
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		header map[string]string
+		body   string
+		status int
+		fields []string
+	}{
+		{name: "valid query", method: "GET", target: "/pets?limit=10&kind=cat,dog", header: map[string]string{"X-Request-ID": requestID}},
+		{name: "missing header", method: "GET", target: "/pets", status: 400, fields: []string{"X-Request-ID"}},
+		{name: "bad uuid", method: "GET", target: "/pets", header: map[string]string{"X-Request-ID": "nope"}, status: 400, fields: []string{"X-Request-ID"}},
+		{name: "limit not integer", method: "GET", target: "/pets?limit=ten", header: map[string]string{"X-Request-ID": requestID}, status: 400, fields: []string{"limit"}},
+		{name: "limit too large", method: "GET", target: "/pets?limit=500", header: map[string]string{"X-Request-ID": requestID}, status: 400, fields: []string{"limit"}},
+		{name: "enum item", method: "GET", target: "/pets?kind=cat,fish", header: map[string]string{"X-Request-ID": requestID}, status: 400, fields: []string{"kind[1]"}},
+		{name: "path param type", method: "GET", target: "/pets/abc", status: 400, fields: []string{"petId"}},
+		{name: "valid body", method: "POST", target: "/pets", header: map[string]string{"Content-Type": "application/json; charset=utf-8"}, body: `{"name":"Rex","kind":"dog","age":3,"tags":["a","b"],"email":null}`},
+		{name: "missing body", method: "POST", target: "/pets", header: map[string]string{"Content-Type": "application/json"}, status: 400, fields: []string{""}},
+		{name: "wrong content type", method: "POST", target: "/pets", header: map[string]string{"Content-Type": "text/plain"}, body: "Rex", status: 400, fields: []string{"Content-Type"}},
+		{name: "malformed JSON", method: "POST", target: "/pets", header: map[string]string{"Content-Type": "application/json"}, body: `{"name":`, status: 400, fields: []string{""}},
+		{
+			name:   "schema violations",
+			method: "POST",
+			target: "/pets",
+			header: map[string]string{"Content-Type": "application/json"},
+			body:   `{"name":"","kind":"fish","age":1.5,"tags":["a","a"],"color":"red"}`,
+			status: 422,
+			fields: []string{"age", "color", "kind", "name", "tags"},
+		},
+		{name: "missing required", method: "POST", target: "/pets", header: map[string]string{"Content-Type": "application/json"}, body: `{"name":"Rex"}`, status: 422, fields: []string{"kind"}},
+		{name: "bad email", method: "POST", target: "/pets", header: map[string]string{"Content-Type": "application/json"}, body: `{"name":"Rex","kind":"dog","email":"rex"}`, status: 422, fields: []string{"email"}},
+		{name: "unknown route", method: "GET", target: "/owners"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			r := httptest.NewRequest(tt.method, tt.target, body)
+			for key, value := range tt.header {
+				r.Header.Set(key, value)
+			}
+
+			err := v.ValidateRequest(r)
+			if tt.status == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var reqErr *RequestError
+			if !errors.As(err, &reqErr) {
+				t.Fatalf("expected a *RequestError, got %v", err)
+			}
+			if reqErr.Status != tt.status {
+				t.Errorf("status = %d, want %d (%v)", reqErr.Status, tt.status, err)
+			}
+			var fields []string
+			for _, e := range reqErr.Errors {
+				fields = append(fields, e.Field)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("fields = %q, want %q (%v)", fields, tt.fields, err)
+			}
+		})
+	}
+}
+
+func TestValidateRequestKeepsBody(t *testing.T) {
+	v := testValidator()
+	const body = `{"name":"Rex","kind":"dog"}`
+	r := httptest.NewRequest("POST", "/pets", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+
+	if err := v.ValidateRequest(r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil || string(data) != body {
+		t.Errorf("body after validation = %q, %v", data, err)
+	}
+}
+
+func TestCompositions(t *testing.T) {
+	v := New(map[string]*Schema{
+		"Cat": {Type: "object", Required: []string{"meows"}},
+		"Dog": {Type: "object", Required: []string{"barks"}},
+	}, nil)
+
+	tests := []struct {
+		name   string
+		schema *Schema
+		value  interface{}
+		valid  bool
+	}{
+		{"oneOf match", &Schema{OneOf: []*Schema{{Ref: "Cat"}, {Ref: "Dog"}}}, map[string]interface{}{"meows": true}, true},
+		{"oneOf both", &Schema{OneOf: []*Schema{{Ref: "Cat"}, {Ref: "Dog"}}}, map[string]interface{}{"meows": true, "barks": true}, false},
+		{"anyOf none", &Schema{AnyOf: []*Schema{{Ref: "Cat"}, {Ref: "Dog"}}}, map[string]interface{}{}, false},
+		{"allOf", &Schema{AllOf: []*Schema{{Ref: "Cat"}, {Ref: "Dog"}}}, map[string]interface{}{"meows": true}, false},
+		{"not", &Schema{Not: &Schema{Type: "string"}}, "text", false},
+		{"multipleOf", &Schema{Type: "number", MultipleOf: Float(0.5)}, json.Number("2.5"), true},
+		{"not multipleOf", &Schema{Type: "number", MultipleOf: Float(0.5)}, json.Number("2.4"), false},
+		{"pattern", &Schema{Type: "string", Pattern: "^[a-z]+$"}, "abc1", false},
+		{"date", &Schema{Type: "string", Format: "date"}, "2024-02-30", false},
+		{"date-time", &Schema{Type: "string", Format: "date-time"}, "2024-02-03T04:05:06Z", true},
+		{"null", &Schema{Type: "string"}, nil, false},
+	}
+
+	for _, tt := range tests {
+		var errs []Error
+		v.validateValue(tt.schema, tt.value, "body", "", &errs, 0)
+		if valid := len(errs) == 0; valid != tt.valid {
+			t.Errorf("%s: valid = %v, want %v (%v)", tt.name, valid, tt.valid, errs)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	reached := false
+	handler := testValidator().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/pets/abc", nil))
+	if reached {
+		t.Error("invalid request reached the handler")
+	}
+	if w.Code != http.StatusBadRequest || w.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("response = %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	var resp struct {
+		Error  string
+		Errors []struct{ In, Field, Message string }
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON response %q: %v", w.Body.String(), err)
+	}
+	if len(resp.Errors) != 1 || resp.Errors[0].In != "path" || resp.Errors[0].Field != "petId" || resp.Errors[0].Message != "must be an integer" {
+		t.Errorf("unexpected errors %+v", resp.Errors)
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/pets/42", nil))
+	if !reached {
+		t.Error("valid request didn't reach the handler")
+	}
+}